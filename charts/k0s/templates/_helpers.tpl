@@ -55,7 +55,9 @@ Whether to create a cluster role or not
     .Values.sync.persistentvolumes.enabled
     .Values.sync.storageclasses.enabled
     .Values.sync.priorityclasses.enabled
+    .Values.sync.runtimeclasses.enabled
     .Values.sync.volumesnapshots.enabled
+    .Values.sync.volumegroupsnapshots.enabled
     .Values.proxy.metricsServer.nodes.enabled
     .Values.multiNamespaceMode.enabled -}}
     {{- true -}}
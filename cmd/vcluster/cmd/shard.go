@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	context2 "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	"github.com/loft-sh/vcluster/pkg/controllers"
+	"github.com/loft-sh/vcluster/pkg/sharding"
+	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/cachetransform"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StartSharding distributes the virtual namespaces across all replicas and runs the namespaced
+// syncers of this replica for its share of the namespaces
+func StartSharding(ctx *context2.ControllerContext) error {
+	hostClient, err := kubernetes.NewForConfig(rest.AddUserAgent(ctx.LocalManager.GetConfig(), "sharding"))
+	if err != nil {
+		return errors.Wrap(err, "create host client")
+	}
+	virtualClient, err := kubernetes.NewForConfig(ctx.VirtualManager.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "create virtual client")
+	}
+
+	// identity used to distinguish between the replicas
+	id, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	sharder := &sharding.Sharder{
+		Membership: &sharding.Membership{
+			Client:        hostClient,
+			Namespace:     ctx.CurrentNamespace,
+			Name:          translate.Suffix,
+			Identity:      id,
+			LeaseDuration: time.Duration(ctx.Options.LeaseDuration) * time.Second,
+		},
+		VirtualClient: virtualClient,
+		Log:           loghelper.New("sharder"),
+		Interval:      time.Duration(ctx.Options.RetryPeriod) * time.Second,
+		// a replica releases moved namespaces within one interval, the second one covers
+		// reconciles that are still running
+		HandoverDelay: 2 * time.Duration(ctx.Options.RetryPeriod) * time.Second,
+	}
+
+	// the syncers run for the lifetime of the replica and only sync the namespaces the sharder owns
+	err = StartShardControllers(ctx.Context, ctx, sharder)
+	if err != nil {
+		return err
+	}
+
+	go sharder.Run(ctx.Context)
+	return nil
+}
+
+// StartShardControllers starts the namespaced syncers with their own managers, as the managers of the leader
+// are only started on the elected replica. The syncers only sync and cache the virtual namespaces the sharder owns.
+func StartShardControllers(ctx context.Context, controllerCtx *context2.ControllerContext, sharder *sharding.Sharder) error {
+	// the caches only contain the owned namespaces and follow the sharder before it reports the change
+	hostCache := sharding.NewPartitionedCache(hostPartition(controllerCtx.Options))
+	virtualCache := sharding.NewPartitionedCache(func(options cache.Options, vNamespace string) cache.Options {
+		options.Namespaces = []string{vNamespace}
+		return options
+	})
+	sharder.OwnedChanged = func(owned []string) {
+		err := hostCache.SetPartitions(hostPartitions(controllerCtx.Options, owned))
+		if err != nil {
+			klog.Errorf("Error updating host shard cache: %v", err)
+		}
+		err = virtualCache.SetPartitions(owned)
+		if err != nil {
+			klog.Errorf("Error updating virtual shard cache: %v", err)
+		}
+	}
+
+	localManager, err := ctrl.NewManager(controllerCtx.LocalManager.GetConfig(), ctrl.Options{
+		Scheme: controllerCtx.LocalManager.GetScheme(),
+		MapperProvider: func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
+			return controllerCtx.LocalManager.GetRESTMapper(), nil
+		},
+		MetricsBindAddress: "0",
+		LeaderElection:     false,
+		Cache: cache.Options{
+			ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripData(cachetransform.KeepManagedData(controllerCtx.CurrentNamespace))),
+			DefaultTransform: cachetransform.StripManagedFields,
+		},
+		NewCache:  hostCache.New,
+		NewClient: pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
+	})
+	if err != nil {
+		return errors.Wrap(err, "create host shard manager")
+	}
+
+	virtualManager, err := ctrl.NewManager(controllerCtx.VirtualManager.GetConfig(), ctrl.Options{
+		Scheme: controllerCtx.VirtualManager.GetScheme(),
+		MapperProvider: func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
+			return controllerCtx.VirtualManager.GetRESTMapper(), nil
+		},
+		MetricsBindAddress: "0",
+		LeaderElection:     false,
+		Cache: cache.Options{
+			ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripUnusedFields),
+			DefaultTransform: cachetransform.StripManagedFields,
+		},
+		NewCache:  virtualCache.New,
+		NewClient: pluginhookclient.NewVirtualPluginClientFactory(blockingcacheclient.NewCacheClient),
+	})
+	if err != nil {
+		return errors.Wrap(err, "create virtual shard manager")
+	}
+
+	// the current namespace client needs to find objects such as the vcluster service,
+	// which are not part of the filtered host cache
+	currentNamespaceClient, err := newShardCurrentNamespaceClient(ctx, controllerCtx)
+	if err != nil {
+		return errors.Wrap(err, "create current namespace client")
+	}
+
+	shardCtx := *controllerCtx
+	shardCtx.Context = ctx
+	shardCtx.LocalManager = localManager
+	shardCtx.VirtualManager = virtualManager
+	shardCtx.CurrentNamespaceClient = currentNamespaceClient
+	shardCtx.StopChan = ctx.Done()
+	shardCtx.NamespaceFilter = sharder
+
+	// instantiate the namespaced syncers, cluster scoped resources are synced by the leader
+	syncers, err := controllers.Create(&shardCtx)
+	if err != nil {
+		return errors.Wrap(err, "instantiate controllers")
+	}
+	syncers, _, err = controllers.SplitSyncers(&shardCtx, syncers)
+	if err != nil {
+		return err
+	}
+	err = controllers.ExecuteInitializers(&shardCtx, syncers)
+	if err != nil {
+		return errors.Wrap(err, "execute initializers")
+	}
+	err = controllers.RegisterIndices(&shardCtx, syncers)
+	if err != nil {
+		return err
+	}
+
+	// start the managers
+	go func() {
+		err := localManager.Start(ctx)
+		if err != nil {
+			klog.Errorf("Error running host shard manager: %v", err)
+		}
+	}()
+	go func() {
+		err := virtualManager.Start(ctx)
+		if err != nil {
+			klog.Errorf("Error running virtual shard manager: %v", err)
+		}
+	}()
+	if !localManager.GetCache().WaitForCacheSync(ctx) || !virtualManager.GetCache().WaitForCacheSync(ctx) {
+		return errors.New("wait for shard caches to sync")
+	}
+
+	return controllers.RegisterSyncers(&shardCtx, syncers)
+}
+
+// hostPartitions returns the host cache partitions of the owned virtual namespaces. The virtual kube-system
+// namespace is always cached for the dns service, in single namespace mode also the objects without a
+// virtual namespace such as the service account token secrets.
+func hostPartitions(options *context2.VirtualClusterOptions, owned []string) []string {
+	partitions := append([]string{"kube-system"}, owned...)
+	if !options.MultiNamespaceMode {
+		partitions = append(partitions, "")
+	}
+
+	return partitions
+}
+
+// hostPartition restricts the host cache to the objects of a virtual namespace, which are either in
+// their own host namespace or labeled with the virtual namespace in the target namespace
+func hostPartition(options *context2.VirtualClusterOptions) sharding.PartitionFunc {
+	return func(cacheOptions cache.Options, vNamespace string) cache.Options {
+		if options.MultiNamespaceMode {
+			cacheOptions.Namespaces = []string{translate.Default.PhysicalNamespace(vNamespace)}
+			return cacheOptions
+		}
+
+		selector := labels.SelectorFromSet(labels.Set{translate.NamespaceLabel: vNamespace})
+		if vNamespace == "" {
+			requirement, _ := labels.NewRequirement(translate.NamespaceLabel, selection.DoesNotExist, nil)
+			selector = labels.NewSelector().Add(*requirement)
+		}
+
+		// the selectors of the objects don't fall back to the default one
+		byObject := map[client.Object]cache.ByObject{}
+		for obj, objOptions := range cacheOptions.ByObject {
+			objOptions.Label = selector
+			byObject[obj] = objOptions
+		}
+		cacheOptions.Namespaces = []string{options.TargetNamespace}
+		cacheOptions.DefaultLabelSelector = selector
+		cacheOptions.ByObject = byObject
+		return cacheOptions
+	}
+}
+
+func newShardCurrentNamespaceClient(ctx context.Context, controllerCtx *context2.ControllerContext) (client.Client, error) {
+	currentNamespaceCache, err := cache.New(controllerCtx.LocalManager.GetConfig(), cache.Options{
+		Scheme:           controllerCtx.LocalManager.GetScheme(),
+		Mapper:           controllerCtx.LocalManager.GetRESTMapper(),
+		Namespaces:       []string{controllerCtx.CurrentNamespace},
+		ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripUnusedFields),
+		DefaultTransform: cachetransform.StripManagedFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := currentNamespaceCache.Start(ctx)
+		if err != nil {
+			klog.Errorf("Error running current namespace cache: %v", err)
+		}
+	}()
+	currentNamespaceCache.WaitForCacheSync(ctx)
+
+	return blockingcacheclient.NewCacheClient(controllerCtx.LocalManager.GetConfig(), client.Options{
+		Scheme: controllerCtx.LocalManager.GetScheme(),
+		Mapper: controllerCtx.LocalManager.GetRESTMapper(),
+		Cache: &client.CacheOptions{
+			Reader: currentNamespaceCache,
+		},
+	})
+}
@@ -10,11 +10,14 @@ import (
 	"github.com/loft-sh/vcluster/pkg/leaderelection"
 	"github.com/loft-sh/vcluster/pkg/metricsapiservice"
 	"github.com/loft-sh/vcluster/pkg/server"
+	"github.com/loft-sh/vcluster/pkg/server/activity"
 	"github.com/loft-sh/vcluster/pkg/telemetry"
 	telemetrytypes "github.com/loft-sh/vcluster/pkg/telemetry/types"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/cachetransform"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,6 +29,7 @@ import (
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
 	context2 "github.com/loft-sh/vcluster/cmd/vcluster/context"
 	"github.com/loft-sh/vcluster/pkg/apis"
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
 	"github.com/loft-sh/vcluster/pkg/controllers"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/services"
@@ -77,6 +81,9 @@ func init() {
 
 	// Register VolumeSnapshot CRDs
 	_ = volumesnapshotv1.AddToScheme(scheme)
+
+	// Register VolumeGroupSnapshot CRDs
+	_ = volumegroupsnapshotv1alpha1.AddToScheme(scheme)
 }
 
 func NewStartCommand() *cobra.Command {
@@ -163,6 +170,22 @@ func ExecuteStart(ctx context.Context, options *context2.VirtualClusterOptions)
 		return err
 	}
 
+	// every replica serves the virtual cluster api, so the leader needs to know about the requests of the others
+	if controllerCtx.Options.SleepAfter > 0 && controllerCtx.Options.LeaderElect {
+		err = StartActivitySharing(controllerCtx)
+		if err != nil {
+			return errors.Wrap(err, "start activity sharing")
+		}
+	}
+
+	// start the namespaced syncers of this replica
+	if controllerCtx.Options.ShardNamespaces {
+		err = StartSharding(controllerCtx)
+		if err != nil {
+			return errors.Wrap(err, "start sharding")
+		}
+	}
+
 	// start leader election + controllers
 	err = StartLeaderElection(controllerCtx, func() error {
 		return StartControllers(controllerCtx)
@@ -209,6 +232,35 @@ func StartProxy(ctx *context2.ControllerContext) error {
 	return nil
 }
 
+// StartActivitySharing shares the requests this replica serves with the leader, which decides if the
+// virtual cluster goes to sleep
+func StartActivitySharing(ctx *context2.ControllerContext) error {
+	hostClient, err := kubernetes.NewForConfig(rest.AddUserAgent(ctx.LocalManager.GetConfig(), "activity"))
+	if err != nil {
+		return errors.Wrap(err, "create host client")
+	}
+
+	// identity used to distinguish between the replicas
+	id, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	shared := &activity.Shared{
+		Client:    hostClient,
+		Namespace: ctx.CurrentNamespace,
+		Name:      translate.Suffix,
+		Identity:  id,
+		Tracker:   activity.Default,
+		Interval:  time.Second * 10,
+	}
+	go func() {
+		_ = shared.Start(ctx.Context)
+	}()
+
+	return nil
+}
+
 func BuildControllerContext(ctx context.Context, options *context2.VirtualClusterOptions, currentNamespace string, inClusterConfig *rest.Config) (*context2.ControllerContext, error) {
 	// parse tolerations
 	for _, t := range options.Tolerations {
@@ -230,6 +282,10 @@ func BuildControllerContext(ctx context.Context, options *context2.VirtualCluste
 
 	// is multi namespace mode?
 	if options.MultiNamespaceMode {
+		if options.NameTemplate != "" {
+			return nil, fmt.Errorf("--name-template is not supported in multi-namespace mode")
+		}
+
 		// set options.TargetNamespace to empty because it will later be used in Manager
 		options.TargetNamespace = ""
 		translate.Default = translate.NewMultiNamespaceTranslator(currentNamespace)
@@ -238,7 +294,11 @@ func BuildControllerContext(ctx context.Context, options *context2.VirtualCluste
 		if options.TargetNamespace == "" {
 			options.TargetNamespace = currentNamespace
 		}
-		translate.Default = translate.NewSingleNamespaceTranslator(options.TargetNamespace)
+		singleNamespaceTranslator, err := translate.NewSingleNamespaceTranslatorWithNameTemplate(options.TargetNamespace, options.NameTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "name template")
+		}
+		translate.Default = singleNamespaceTranslator
 	}
 
 	telemetry.Collector.SetOptions(options)
@@ -285,7 +345,12 @@ func BuildControllerContext(ctx context.Context, options *context2.VirtualCluste
 		MetricsBindAddress: options.HostMetricsBindAddress,
 		LeaderElection:     false,
 		Namespace:          options.TargetNamespace,
-		NewClient:          pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
+		Cache: cache.Options{
+			// only the data of the secrets and config maps vcluster syncs is needed
+			ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripData(cachetransform.KeepManagedData(currentNamespace))),
+			DefaultTransform: cachetransform.StripManagedFields,
+		},
+		NewClient: pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
 	})
 	if err != nil {
 		return nil, err
@@ -295,7 +360,11 @@ func BuildControllerContext(ctx context.Context, options *context2.VirtualCluste
 		Scheme:             scheme,
 		MetricsBindAddress: options.VirtualMetricsBindAddress,
 		LeaderElection:     false,
-		NewClient:          pluginhookclient.NewVirtualPluginClientFactory(blockingcacheclient.NewCacheClient),
+		Cache: cache.Options{
+			ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripUnusedFields),
+			DefaultTransform: cachetransform.StripManagedFields,
+		},
+		NewClient: pluginhookclient.NewVirtualPluginClientFactory(blockingcacheclient.NewCacheClient),
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/controllers/sleep"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// WakeupCmd holds the wakeup flags
+type WakeupCmd struct {
+	Name        string
+	ServiceName string
+	Port        int
+	Timeout     time.Duration
+}
+
+func NewWakeupCommand() *cobra.Command {
+	options := &WakeupCmd{}
+	cmd := &cobra.Command{
+		Use:   "wakeup",
+		Short: "Serves the vcluster service while the virtual cluster sleeps and wakes it up on the next connection",
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return ExecuteWakeup(cobraCmd.Context(), options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Name, "name", "", "The name of the virtual cluster")
+	cmd.Flags().StringVar(&options.ServiceName, "service-name", "", "The service name where the vcluster proxy will be available")
+	cmd.Flags().IntVar(&options.Port, "port", 8443, "The port to bind to and to forward the connections to")
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", time.Minute*5, "How long the virtual cluster has to wake up")
+	return cmd
+}
+
+// ExecuteWakeup runs the wake-up responder until the virtual cluster woke up and the pod is deleted
+func ExecuteWakeup(ctx context.Context, options *WakeupCmd) error {
+	if options.Name == "" {
+		return errors.New("please specify the name of the virtual cluster via --name")
+	}
+	if options.ServiceName == "" {
+		options.ServiceName = options.Name
+	}
+
+	namespace, err := clienthelper.CurrentNamespace()
+	if err != nil {
+		return errors.Wrap(err, "get current namespace")
+	}
+	kubeClient, err := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(options.Port)))
+	if err != nil {
+		return err
+	}
+
+	responder := &sleep.Responder{
+		Client:      kubeClient,
+		Name:        options.Name,
+		Namespace:   namespace,
+		ServiceName: options.ServiceName,
+		Port:        options.Port,
+		Timeout:     options.Timeout,
+		Log:         log.GetInstance(),
+	}
+	return responder.Serve(ctx, listener)
+}
@@ -5,6 +5,7 @@ import (
 
 	servertypes "github.com/loft-sh/vcluster/pkg/server/types"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/cachetransform"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
@@ -12,8 +13,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
+// NamespaceFilter restricts the syncers to the virtual namespaces a replica owns, e.g. with namespace sharding
+type NamespaceFilter interface {
+	// Owns returns true if the objects of the virtual namespace should be synced
+	Owns(namespace string) bool
+
+	// Watch returns a channel that receives an event for every virtual namespace the replica starts to own
+	Watch() <-chan event.GenericEvent
+}
+
 type ControllerContext struct {
 	Context context.Context
 
@@ -29,6 +40,9 @@ type ControllerContext struct {
 	AdditionalServerFilters []servertypes.Filter
 	Options                 *VirtualClusterOptions
 	StopChan                <-chan struct{}
+
+	// NamespaceFilter is only set for the syncers of a shard
+	NamespaceFilter NamespaceFilter
 }
 
 func NewControllerContext(
@@ -92,9 +106,11 @@ func newCurrentNamespaceClient(ctx context.Context, currentNamespace string, loc
 	currentNamespaceCache := localManager.GetCache()
 	if currentNamespace != options.TargetNamespace {
 		currentNamespaceCache, err = cache.New(localManager.GetConfig(), cache.Options{
-			Scheme:     localManager.GetScheme(),
-			Mapper:     localManager.GetRESTMapper(),
-			Namespaces: []string{currentNamespace},
+			Scheme:           localManager.GetScheme(),
+			Mapper:           localManager.GetRESTMapper(),
+			Namespaces:       []string{currentNamespace},
+			ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripUnusedFields),
+			DefaultTransform: cachetransform.StripManagedFields,
 		})
 		if err != nil {
 			return nil, err
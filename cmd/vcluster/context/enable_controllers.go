@@ -27,8 +27,11 @@ var ExistingControllers = sets.New(
 	"storageclasses",
 	"hoststorageclasses",
 	"priorityclasses",
+	"runtimeclasses",
+	"resourceclaims",
 	"networkpolicies",
 	"volumesnapshots",
+	"volumegroupsnapshots",
 	"poddisruptionbudgets",
 	"serviceaccounts",
 	"csinodes",
@@ -58,13 +61,17 @@ var schedulerRequiredControllers = sets.New(
 )
 
 const (
-	storageV1GroupVersion = "storage.k8s.io/v1"
+	storageV1GroupVersion             = "storage.k8s.io/v1"
+	groupSnapshotV1alpha1GroupVersion = "groupsnapshot.storage.k8s.io/v1alpha1"
+	resourceV1alpha2GroupVersion      = "resource.k8s.io/v1alpha2"
 )
 
 // map from groupversion to list of resources in that groupversion
 // the syncers will be disabled unless that resource is advertised in that groupversion
 var possibleMissing = map[string][]string{
-	storageV1GroupVersion: schedulerRequiredControllers.UnsortedList(),
+	storageV1GroupVersion:             schedulerRequiredControllers.UnsortedList(),
+	groupSnapshotV1alpha1GroupVersion: {"volumegroupsnapshots"},
+	resourceV1alpha2GroupVersion:      {"resourceclaims"},
 }
 
 func parseControllers(options *VirtualClusterOptions) (sets.Set[string], error) {
@@ -110,6 +117,11 @@ func parseControllers(options *VirtualClusterOptions) (sets.Set[string], error)
 		enabledControllers.Insert("ingressclasses")
 	}
 
+	// enable volumesnapshots if volume group snapshot syncing is enabled, because group snapshots consist of volume snapshots
+	if enabledControllers.Has("volumegroupsnapshots") && !disabledControllers.Has("volumesnapshots") {
+		enabledControllers.Insert("volumesnapshots")
+	}
+
 	// enable namespaces controller in MultiNamespaceMode
 	if options.MultiNamespaceMode {
 		enabledControllers.Insert("namespaces")
@@ -177,7 +189,7 @@ func disableMissingAPIs(discoveryClient discovery.DiscoveryInterface, controller
 			}
 			if !found {
 				enabledControllers.Delete(resourcePlural)
-				klog.Warningf("host kubernetes apiserver not advertising resource %q in GroupVersion %q, disabling the syncer", resourcePlural, storageV1GroupVersion)
+				klog.Warningf("host kubernetes apiserver not advertising resource %q in GroupVersion %q, disabling the syncer", resourcePlural, groupVersion)
 			}
 		}
 	}
@@ -106,6 +106,15 @@ func TestEnableControllers(t *testing.T) {
 			expectDisabled: []string{},
 			expectError:    false,
 		},
+		{
+			desc: "volumegroupsnapshots enabled, volumesnapshots not enabled",
+			optsModifier: func(v *VirtualClusterOptions) {
+				v.Controllers = []string{"volumegroupsnapshots"}
+			},
+			expectEnabled:  []string{"volumegroupsnapshots", "volumesnapshots"},
+			expectDisabled: []string{},
+			expectError:    false,
+		},
 	}
 
 	for _, tc := range testTable {
@@ -163,6 +172,24 @@ var csiNodeV1 = metav1.APIResource{
 	Kind:         "CSINode",
 }
 
+var volumeGroupSnapshotV1alpha1 = metav1.APIResource{
+	Name:         "volumegroupsnapshots",
+	SingularName: "volumegroupsnapshot",
+	Namespaced:   true,
+	Group:        "groupsnapshot.storage.k8s.io",
+	Version:      "v1alpha1",
+	Kind:         "VolumeGroupSnapshot",
+}
+
+var resourceClaimV1alpha2 = metav1.APIResource{
+	Name:         "resourceclaims",
+	SingularName: "resourceclaim",
+	Namespaced:   true,
+	Group:        "resource.k8s.io",
+	Version:      "v1alpha2",
+	Kind:         "ResourceClaim",
+}
+
 func TestDisableMissingAPIs(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -192,6 +219,32 @@ func TestDisableMissingAPIs(t *testing.T) {
 			expectedNotFound: sets.New[string](),
 			expectedFound:    sets.New("csistoragecapacities", "csinodes", "csidrivers"),
 		},
+		{
+			name: "Volume group snapshots available",
+			apis: map[string][]metav1.APIResource{
+				storageV1GroupVersion:             {csiNodeV1, csiDriverV1, csiStorageCapacityV1},
+				groupSnapshotV1alpha1GroupVersion: {volumeGroupSnapshotV1alpha1},
+			},
+			expectedNotFound: sets.New[string](),
+			expectedFound:    sets.New("csistoragecapacities", "csinodes", "csidrivers", "volumegroupsnapshots"),
+		},
+		{
+			name: "Dynamic resource allocation disabled",
+			apis: map[string][]metav1.APIResource{
+				storageV1GroupVersion: {csiNodeV1, csiDriverV1, csiStorageCapacityV1},
+			},
+			expectedNotFound: sets.New("resourceclaims"),
+			expectedFound:    sets.New("csistoragecapacities", "csinodes", "csidrivers", "runtimeclasses"),
+		},
+		{
+			name: "Dynamic resource allocation enabled",
+			apis: map[string][]metav1.APIResource{
+				storageV1GroupVersion:        {csiNodeV1, csiDriverV1, csiStorageCapacityV1},
+				resourceV1alpha2GroupVersion: {resourceClaimV1alpha2},
+			},
+			expectedNotFound: sets.New[string](),
+			expectedFound:    sets.New("csistoragecapacities", "csinodes", "csidrivers", "resourceclaims"),
+		},
 	}
 
 	for i, testCase := range tests {
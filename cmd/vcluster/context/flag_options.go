@@ -1,6 +1,8 @@
 package context
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 )
 
@@ -32,6 +34,7 @@ type VirtualClusterOptions struct {
 	Name string `json:"name,omitempty"`
 
 	TargetNamespace string `json:"targetNamespace,omitempty"`
+	NameTemplate    string `json:"nameTemplate,omitempty"`
 	ServiceName     string `json:"serviceName,omitempty"`
 
 	SetOwner bool `json:"setOwner,omitempty"`
@@ -42,6 +45,26 @@ type VirtualClusterOptions struct {
 	FakeKubeletIPs      bool     `json:"fakeKubeletIPs,omitempty"`
 	ClearNodeImages     bool     `json:"clearNodeImages,omitempty"`
 	TranslateImages     []string `json:"translateImages,omitempty"`
+	ImageRewriteRules   []string `json:"imageRewriteRules,omitempty"`
+	ImagePullSecrets    []string `json:"imagePullSecrets,omitempty"`
+	PodMutationPolicy   string   `json:"podMutationPolicy,omitempty"`
+
+	PriorityClassMaxValue        int32    `json:"priorityClassMaxValue,omitempty"`
+	PriorityClassValueRange      string   `json:"priorityClassValueRange,omitempty"`
+	PriorityClassPreemptionNever bool     `json:"priorityClassPreemptionNever,omitempty"`
+	PriorityClassMappings        []string `json:"priorityClassMappings,omitempty"`
+
+	DryRunSyncers     []string `json:"dryRunSyncers,omitempty"`
+	DisableSyncStatus bool     `json:"disableSyncStatus,omitempty"`
+
+	GarbageCollectionInterval    int64 `json:"garbageCollectionInterval,omitempty"`
+	GarbageCollectionGracePeriod int64 `json:"garbageCollectionGracePeriod,omitempty"`
+	GarbageCollectionDelete      bool  `json:"garbageCollectionDelete,omitempty"`
+
+	SleepAfter    time.Duration `json:"sleepAfter,omitempty"`
+	SleepSchedule string        `json:"sleepSchedule,omitempty"`
+
+	HelmClient string `json:"helmClient,omitempty"`
 
 	NodeSelector        string `json:"nodeSelector,omitempty"`
 	EnforceNodeSelector bool   `json:"enforceNodeSelector,omitempty"`
@@ -57,6 +80,8 @@ type VirtualClusterOptions struct {
 	RenewDeadline int64 `json:"renewDeadline,omitempty"`
 	RetryPeriod   int64 `json:"retryPeriod,omitempty"`
 
+	ShardNamespaces bool `json:"shardNamespaces,omitempty"`
+
 	DisablePlugins      bool     `json:"disablePlugins,omitempty"`
 	PluginListenAddress string   `json:"pluginListenAddress,omitempty"`
 	Plugins             []string `json:"plugins,omitempty"`
@@ -65,6 +90,10 @@ type VirtualClusterOptions struct {
 
 	EnforcePodSecurityStandard string `json:"enforcePodSecurityStandard,omitempty"`
 
+	EnforceNetworkIsolation      bool     `json:"enforceNetworkIsolation,omitempty"`
+	NetworkIsolationEgressCIDRs  []string `json:"networkIsolationEgressCIDRs,omitempty"`
+	NetworkIsolationEgressExcept []string `json:"networkIsolationEgressExcept,omitempty"`
+
 	MapHostServices    []string `json:"mapHostServices,omitempty"`
 	MapVirtualServices []string `json:"mapVirtualServices,omitempty"`
 
@@ -119,6 +148,7 @@ func AddFlags(flags *pflag.FlagSet, options *VirtualClusterOptions) {
 	flags.StringVar(&options.KubeConfigServer, "out-kube-config-server", "", "If specified, the virtual cluster will use this server for the generated kube config (e.g. https://my-vcluster.domain.com)")
 
 	flags.StringVar(&options.TargetNamespace, "target-namespace", "", "The namespace to run the virtual cluster in (defaults to current namespace)")
+	flags.StringVar(&options.NameTemplate, "name-template", "", "Go template used to build the host names of synced namespaced objects, e.g. {{.Namespace}}-{{.Name}}. Available fields are .Name, .Namespace, .Suffix and .TargetNamespace. Falls back to the default naming if the rendered name is invalid")
 	flags.StringVar(&options.ServiceName, "service-name", "", "The service name where the vcluster proxy will be available")
 	flags.BoolVar(&options.SetOwner, "set-owner", true, "If true, will set the same owner the currently running syncer pod has on the synced resources")
 
@@ -133,6 +163,13 @@ func AddFlags(flags *pflag.FlagSet, options *VirtualClusterOptions) {
 	flags.BoolVar(&options.ClearNodeImages, "node-clear-image-status", false, "If enabled, when syncing real nodes, the status.images data will be removed from the vcluster nodes")
 
 	flags.StringSliceVar(&options.TranslateImages, "translate-image", []string{}, "Translates image names from the virtual pod to the physical pod (e.g. coredns/coredns=mirror.io/coredns/coredns)")
+	flags.StringArrayVar(&options.ImageRewriteRules, "image-rewrite-rule", []string{}, "Ordered rules to rewrite image names from the virtual pod to the physical pod, the first matching rule wins. Supports wildcards (e.g. docker.io/*=mirror.corp/dockerhub/*) and regular expressions (e.g. regex:^docker\\.io/(.*)$=mirror.corp/dockerhub/$1)")
+	flags.StringVar(&options.PodMutationPolicy, "pod-mutation-policy", "", "Path to a pod mutation policy file that adds or overrides fields of the synced host pods, such as security context, runtime class, priority class, labels or sidecar containers")
+	flags.Int32Var(&options.PriorityClassMaxValue, "priority-class-max-value", 1000000000, "The highest value a synced priority class or pod priority can have in the host cluster, higher values are capped")
+	flags.StringVar(&options.PriorityClassValueRange, "priority-class-value-range", "", "If set, virtual priority values between 0 and 1000000000 are linearly compressed into this host range before the max value is applied (e.g. 0:1000)")
+	flags.BoolVar(&options.PriorityClassPreemptionNever, "priority-class-preemption-never", false, "If enabled, synced priority classes and pods get preemptionPolicy Never in the host cluster")
+	flags.StringArrayVar(&options.PriorityClassMappings, "priority-class-mapping", []string{}, "Maps a virtual priority class to an existing host priority class instead of syncing it (e.g. high=tenant-high). Use *=host-class to map all other classes. If set, no priority classes are created in the host cluster")
+	flags.StringSliceVar(&options.ImagePullSecrets, "image-pull-secret", []string{}, "Host image pull secrets that are added to physical pods pulling from the given registry (e.g. mirror.corp=mirror-pull-secret). If no registry is specified, the secret is added to all pods")
 	flags.BoolVar(&options.EnforceNodeSelector, "enforce-node-selector", true, "If enabled and --node-selector is set then the virtual cluster will ensure that no pods are scheduled outside of the node selector")
 	flags.StringSliceVar(&options.Tolerations, "enforce-toleration", []string{}, "If set will apply the provided tolerations to all pods in the vcluster")
 	flags.StringVar(&options.NodeSelector, "node-selector", "", "If nodes sync is enabled, nodes with the given node selector will be synced to the virtual cluster. If fake nodes are used, and --enforce-node-selector flag is set, then vcluster will ensure that no pods are scheduled outside of the node selector.")
@@ -147,6 +184,19 @@ func AddFlags(flags *pflag.FlagSet, options *VirtualClusterOptions) {
 	flags.Int64Var(&options.LeaseDuration, "lease-duration", 60, "Lease duration of the leader election in seconds")
 	flags.Int64Var(&options.RenewDeadline, "renew-deadline", 40, "Renew deadline of the leader election in seconds")
 	flags.Int64Var(&options.RetryPeriod, "retry-period", 15, "Retry period of the leader election in seconds")
+	flags.BoolVar(&options.ShardNamespaces, "shard-namespaces", false, "If enabled, all replicas sync a share of the virtual namespaces, while cluster scoped resources are synced by the elected leader only")
+
+	flags.StringSliceVar(&options.DryRunSyncers, "dry-run-syncer", []string{}, "Syncers that only report the host changes they would apply as events and on the /vcluster/dry-run endpoint instead of applying them, e.g. ingress or * for all syncers")
+	flags.BoolVar(&options.DisableSyncStatus, "disable-sync-status", false, "If enabled, vcluster will not record the sync status of namespaced objects in the vcluster.loft.sh/sync-status annotation")
+
+	flags.Int64Var(&options.GarbageCollectionInterval, "gc-interval", 600, "Interval in seconds in which vcluster looks for managed host objects without a virtual object. 0 disables the periodic garbage collection")
+	flags.Int64Var(&options.GarbageCollectionGracePeriod, "gc-grace-period", 600, "Time in seconds a host object needs to be orphaned before the garbage collector deletes it")
+	flags.BoolVar(&options.GarbageCollectionDelete, "gc-delete", false, "If enabled, the garbage collector deletes orphaned host objects after the grace period instead of only reporting them")
+
+	flags.DurationVar(&options.SleepAfter, "sleep-after", 0, "If set, the virtual cluster goes to sleep after this duration without api requests (e.g. 30m) and wakes up on the next request")
+	flags.StringVar(&options.SleepSchedule, "sleep-schedule", "", "Cron schedule when the virtual cluster goes to sleep, e.g. '0 20 * * 1-5'. Prefix the schedule with TZ=<timezone> to use another timezone than UTC. The virtual cluster wakes up on the next request")
+
+	flags.StringVar(&options.HelmClient, "helm-client", "binary", "The helm client the init manifests controller deploys charts with, either binary (executes the helm binary and downloads it if it's not installed) or sdk (runs helm in-process without the helm binary)")
 
 	flags.BoolVar(&options.DisablePlugins, "disable-plugins", false, "If enabled, vcluster will not load any plugins")
 	flags.StringVar(&options.PluginListenAddress, "plugin-listen-address", "localhost:10099", "The plugin address to listen to. If this is changed, you'll need to configure your plugins to connect to the updated port")
@@ -154,6 +204,9 @@ func AddFlags(flags *pflag.FlagSet, options *VirtualClusterOptions) {
 	flags.StringVar(&options.DefaultImageRegistry, "default-image-registry", "", "This address will be prepended to all deployed system images by vcluster")
 
 	flags.StringVar(&options.EnforcePodSecurityStandard, "enforce-pod-security-standard", "", "This can be set to 'privileged', 'baseline', or 'restricted' to make vcluster enforce these policies during translation.")
+	flags.BoolVar(&options.EnforceNetworkIsolation, "enforce-network-isolation", false, "If enabled, vcluster will maintain baseline network policies in the host cluster that only allow traffic to the vcluster itself, its CoreDNS, mapped host services and the allowed egress cidrs")
+	flags.StringSliceVar(&options.NetworkIsolationEgressCIDRs, "network-isolation-egress-cidr", []string{}, "If network isolation is enforced, workloads are allowed to connect to these ip ranges (e.g. 0.0.0.0/0)")
+	flags.StringSliceVar(&options.NetworkIsolationEgressExcept, "network-isolation-egress-except", []string{}, "If network isolation is enforced, these ip ranges are excluded from the allowed egress cidrs (e.g. 10.0.0.0/8)")
 	flags.StringSliceVar(&options.SyncLabels, "sync-labels", []string{}, "The specified labels will be synced to physical resources, in addition to their vcluster translated versions.")
 	flags.StringSliceVar(&options.Plugins, "plugins", []string{}, "The plugins to wait for during startup")
 
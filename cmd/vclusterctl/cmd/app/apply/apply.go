@@ -0,0 +1,216 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/apis/vcluster/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// maxValueLength caps the length of values printed in a plan
+const maxValueLength = 60
+
+// Load reads the vcluster spec from the file or from stdin if path is -
+func Load(path string) (*v1alpha1.VCluster, error) {
+	var (
+		out []byte
+		err error
+	)
+	if path == "-" {
+		out, err = io.ReadAll(os.Stdin)
+	} else {
+		out, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(out)
+}
+
+// Parse parses and validates the vcluster spec
+func Parse(raw []byte) (*v1alpha1.VCluster, error) {
+	vCluster := &v1alpha1.VCluster{}
+	err := yaml.UnmarshalStrict(raw, vCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse vcluster spec")
+	}
+
+	if vCluster.APIVersion != v1alpha1.SchemeGroupVersion.String() || vCluster.Kind != v1alpha1.Kind {
+		return nil, fmt.Errorf("unsupported spec %s %s, expected %s %s", vCluster.APIVersion, vCluster.Kind, v1alpha1.SchemeGroupVersion.String(), v1alpha1.Kind)
+	} else if vCluster.Name == "" {
+		return nil, fmt.Errorf("metadata.name is required")
+	}
+
+	// the generic sync config is validated the same way the syncer does
+	if vCluster.Spec.Sync.Generic != nil {
+		if vCluster.Spec.Sync.Generic.Version == "" {
+			vCluster.Spec.Sync.Generic.Version = config.Version
+		}
+
+		_, err = genericSyncConfig(vCluster.Spec.Sync.Generic)
+		if err != nil {
+			return nil, errors.Wrap(err, "spec.sync.generic")
+		}
+	}
+
+	return vCluster, nil
+}
+
+// Values returns the chart values of the vcluster spec. The default values are the values
+// vcluster create would use for the same distro and kubernetes version.
+func Values(vCluster *v1alpha1.VCluster, defaultValues string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(defaultValues), &values)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse default values")
+	}
+
+	for name, enabled := range vCluster.Spec.Sync.Resources {
+		setValue(values, enabled, "sync", name, "enabled")
+	}
+	if vCluster.Spec.Sync.Generic != nil {
+		genericConfig, err := genericSyncConfig(vCluster.Spec.Sync.Generic)
+		if err != nil {
+			return nil, err
+		}
+
+		setValue(values, genericConfig, "sync", "generic", "config")
+	}
+	if vCluster.Spec.Init.Manifests != "" {
+		setValue(values, vCluster.Spec.Init.Manifests, "init", "manifests")
+	}
+	helm.MergeValues(values, vCluster.Spec.Values)
+
+	return normalize(values)
+}
+
+func genericSyncConfig(genericConfig *config.Config) (string, error) {
+	out, err := yaml.Marshal(genericConfig)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = config.Parse(string(out))
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+func setValue(values map[string]interface{}, value interface{}, path ...string) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := values[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			values[key] = child
+		}
+
+		values = child
+	}
+
+	values[path[len(path)-1]] = value
+}
+
+// normalize converts the values to the types helm stores them with in the release
+func normalize(values map[string]interface{}) (map[string]interface{}, error) {
+	out, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := map[string]interface{}{}
+	err = json.Unmarshal(out, &normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// Change is the difference of a single value between the release and the spec
+type Change struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+func (c Change) String() string {
+	if c.Old == nil {
+		return fmt.Sprintf("+ %s: %s", c.Path, formatValue(c.New))
+	} else if c.New == nil {
+		return fmt.Sprintf("- %s: %s", c.Path, formatValue(c.Old))
+	}
+
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, formatValue(c.Old), formatValue(c.New))
+}
+
+func formatValue(value interface{}) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	if len(out) > maxValueLength {
+		return string(out[:maxValueLength]) + "..."
+	}
+
+	return string(out)
+}
+
+// Diff returns the changes between the values of the release and the desired values sorted by path
+func Diff(current, desired map[string]interface{}) ([]Change, error) {
+	current, err := normalize(current)
+	if err != nil {
+		return nil, err
+	}
+	desired, err = normalize(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	currentValues := map[string]interface{}{}
+	flatten(current, "", currentValues)
+	desiredValues := map[string]interface{}{}
+	flatten(desired, "", desiredValues)
+
+	changes := []Change{}
+	for path, value := range desiredValues {
+		if !reflect.DeepEqual(currentValues[path], value) {
+			changes = append(changes, Change{Path: path, Old: currentValues[path], New: value})
+		}
+	}
+	for path, value := range currentValues {
+		if _, ok := desiredValues[path]; !ok {
+			changes = append(changes, Change{Path: path, Old: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// flatten collects all values that are no non-empty maps by their dot separated path
+func flatten(values map[string]interface{}, prefix string, out map[string]interface{}) {
+	for key, value := range values {
+		path := strings.TrimPrefix(prefix+"."+key, ".")
+		child, ok := value.(map[string]interface{})
+		if ok && len(child) > 0 {
+			flatten(child, path, out)
+			continue
+		}
+
+		out[path] = value
+	}
+}
@@ -0,0 +1,89 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const testSpec = `apiVersion: vcluster.loft.sh/v1alpha1
+kind: VCluster
+metadata:
+  name: test
+  namespace: test
+spec:
+  distro: k3s
+  kubernetesVersion: v1.27
+  sync:
+    resources:
+      ingresses: true
+      nodes: false
+    generic:
+      export:
+      - apiVersion: cert-manager.io/v1
+        kind: Certificate
+  init:
+    manifests: |-
+      apiVersion: v1
+      kind: Namespace
+      metadata:
+        name: team-a
+  values:
+    syncer:
+      extraArgs:
+      - --enforce-pod-security-standard=baseline
+`
+
+const testDefaultValues = `vcluster:
+  image: rancher/k3s:v1.27.3-k3s1
+sync:
+  nodes:
+    enabled: true
+    syncAllNodes: true
+service:
+  type: NodePort
+`
+
+func TestParse(t *testing.T) {
+	vCluster, err := Parse([]byte(testSpec))
+	assert.NilError(t, err)
+	assert.Equal(t, vCluster.Name, "test")
+	assert.Equal(t, vCluster.Spec.Sync.Generic.Version, "v1beta1")
+
+	_, err = Parse([]byte(strings.Replace(testSpec, "kind: VCluster", "kind: Cluster", 1)))
+	assert.ErrorContains(t, err, "unsupported spec")
+	_, err = Parse([]byte(strings.Replace(testSpec, "distro: k3s", "distribution: k3s", 1)))
+	assert.ErrorContains(t, err, "unknown field")
+}
+
+func TestValuesAndDiff(t *testing.T) {
+	vCluster, err := Parse([]byte(testSpec))
+	assert.NilError(t, err)
+
+	values, err := Values(vCluster, testDefaultValues)
+	assert.NilError(t, err)
+	sync := values["sync"].(map[string]interface{})
+	assert.Equal(t, sync["ingresses"].(map[string]interface{})["enabled"], true)
+	assert.Equal(t, sync["nodes"].(map[string]interface{})["enabled"], false)
+	assert.Equal(t, sync["nodes"].(map[string]interface{})["syncAllNodes"], true)
+	assert.Assert(t, strings.Contains(sync["generic"].(map[string]interface{})["config"].(string), "kind: Certificate"))
+	assert.Assert(t, strings.Contains(values["init"].(map[string]interface{})["manifests"].(string), "team-a"))
+
+	// nothing changed
+	changes, err := Diff(values, values)
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 0)
+
+	// changed, added and removed values
+	vCluster.Spec.Sync.Resources["nodes"] = true
+	vCluster.Spec.Values = nil
+	desired, err := Values(vCluster, testDefaultValues+"isolation:\n  enabled: true\n")
+	assert.NilError(t, err)
+	changes, err = Diff(values, desired)
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 3)
+	assert.Equal(t, changes[0].String(), "+ isolation.enabled: true")
+	assert.Equal(t, changes[1].String(), "~ sync.nodes.enabled: false -> true")
+	assert.Equal(t, changes[2].String(), `- syncer.extraArgs: ["--enforce-pod-security-standard=baseline"]`)
+}
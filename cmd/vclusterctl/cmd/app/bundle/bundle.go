@@ -0,0 +1,296 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AnnotationKind is set on every manifest of the bundle index and is one of KindChart, KindHelm or KindImage
+	AnnotationKind = "sh.loft.vcluster.bundle.kind"
+
+	AnnotationDistro            = "sh.loft.vcluster.bundle.distro"
+	AnnotationChartName         = "sh.loft.vcluster.bundle.chart-name"
+	AnnotationChartVersion      = "sh.loft.vcluster.bundle.chart-version"
+	AnnotationKubernetesVersion = "sh.loft.vcluster.bundle.kubernetes-version"
+
+	KindChart = "chart"
+	KindHelm  = "helm"
+	KindImage = "image"
+)
+
+var imageRegEx = regexp.MustCompile(`(?m)^\s*(?:-\s+)?image:\s*["']?([^"'\s]+)["']?\s*$`)
+
+// ImagesFromManifests returns the images referenced by the given rendered manifests, including
+// the manifests embedded into config maps such as the coredns manifests
+func ImagesFromManifests(manifests string) []string {
+	found := map[string]bool{}
+	for _, match := range imageRegEx.FindAllStringSubmatch(manifests, -1) {
+		if strings.Contains(match[1], "{{") {
+			continue
+		}
+
+		found[match[1]] = true
+	}
+
+	images := []string{}
+	for image := range found {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images
+}
+
+// Metadata describes for which vcluster a bundle was created
+type Metadata struct {
+	Distro            string
+	ChartName         string
+	ChartVersion      string
+	KubernetesVersion string
+}
+
+// Builder creates a new bundle within a directory
+type Builder struct {
+	layout   *Layout
+	index    *Manifest
+	client   *Client
+	platform *Platform
+}
+
+// NewBuilder creates a new bundle for the given metadata within the directory, images are pulled for the given platform
+func NewBuilder(path string, metadata Metadata, platform *Platform, client *Client) (*Builder, error) {
+	layout, err := NewLayout(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Builder{
+		layout: layout,
+		index: &Manifest{
+			SchemaVersion: 2,
+			MediaType:     MediaTypeImageIndex,
+			Annotations: map[string]string{
+				AnnotationDistro:            metadata.Distro,
+				AnnotationChartName:         metadata.ChartName,
+				AnnotationChartVersion:      metadata.ChartVersion,
+				AnnotationKubernetesVersion: metadata.KubernetesVersion,
+			},
+		},
+		client:   client,
+		platform: platform,
+	}, nil
+}
+
+// AddChart adds the packaged chart as helm OCI artifact
+func (b *Builder) AddChart(chart []byte, name, version string) error {
+	config, err := json.Marshal(map[string]string{"name": name, "version": version})
+	if err != nil {
+		return err
+	}
+
+	return b.addArtifact(KindChart, name+":"+version, MediaTypeHelmConfig, config, MediaTypeHelmChartContent, chart, name+"-"+version+".tgz", nil)
+}
+
+// AddHelm adds the helm binary of the current platform, which is used by vcluster create if helm is not installed
+func (b *Builder) AddHelm(helmPath string) error {
+	helmBinary, err := os.ReadFile(helmPath)
+	if err != nil {
+		return errors.Wrap(err, "read helm binary")
+	}
+
+	return b.addArtifact(KindHelm, "helm", MediaTypeEmptyConfig, []byte("{}"), MediaTypeHelmBinaryContent, helmBinary, "helm", &Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH})
+}
+
+// AddImage pulls the image into the bundle
+func (b *Builder) AddImage(ctx context.Context, image string) error {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return err
+	}
+
+	descriptor, err := b.client.Pull(ctx, ref, b.platform, b.layout)
+	if err != nil {
+		return errors.Wrapf(err, "pull image %s", image)
+	}
+
+	descriptor.Annotations = map[string]string{
+		AnnotationKind:    KindImage,
+		AnnotationRefName: image,
+	}
+	b.index.Manifests = append(b.index.Manifests, *descriptor)
+	return nil
+}
+
+// Close writes the index of the bundle
+func (b *Builder) Close() error {
+	return b.layout.WriteIndex(b.index)
+}
+
+// Layout returns the layout of the bundle
+func (b *Builder) Layout() *Layout {
+	return b.layout
+}
+
+func (b *Builder) addArtifact(kind, refName, configMediaType string, config []byte, contentMediaType string, content []byte, title string, platform *Platform) error {
+	configDigest, configSize, err := b.layout.WriteBlob(bytes.NewReader(config), "")
+	if err != nil {
+		return err
+	}
+	contentDigest, contentSize, err := b.layout.WriteBlob(bytes.NewReader(content), "")
+	if err != nil {
+		return err
+	}
+
+	manifest, err := json.Marshal(&Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        &Descriptor{MediaType: configMediaType, Digest: configDigest, Size: configSize},
+		Layers: []Descriptor{{
+			MediaType:   contentMediaType,
+			Digest:      contentDigest,
+			Size:        contentSize,
+			Annotations: map[string]string{AnnotationTitle: title},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	manifestDigest, manifestSize, err := b.layout.WriteBlob(bytes.NewReader(manifest), "")
+	if err != nil {
+		return err
+	}
+
+	b.index.Manifests = append(b.index.Manifests, Descriptor{
+		MediaType: MediaTypeImageManifest,
+		Digest:    manifestDigest,
+		Size:      manifestSize,
+		Platform:  platform,
+		Annotations: map[string]string{
+			AnnotationKind:    kind,
+			AnnotationRefName: refName,
+		},
+	})
+	return nil
+}
+
+// Bundle is an extracted bundle
+type Bundle struct {
+	Metadata Metadata
+
+	layout *Layout
+	index  *Manifest
+}
+
+// Open extracts the bundle archive into the given directory
+func Open(archive, path string) (*Bundle, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	layout, err := Extract(file, path)
+	if err != nil {
+		return nil, err
+	}
+	index, err := layout.Index()
+	if err != nil {
+		return nil, err
+	}
+	if index.Annotations[AnnotationChartName] == "" {
+		return nil, fmt.Errorf("%s is not a vcluster bundle", archive)
+	}
+
+	return &Bundle{
+		Metadata: Metadata{
+			Distro:            index.Annotations[AnnotationDistro],
+			ChartName:         index.Annotations[AnnotationChartName],
+			ChartVersion:      index.Annotations[AnnotationChartVersion],
+			KubernetesVersion: index.Annotations[AnnotationKubernetesVersion],
+		},
+		layout: layout,
+		index:  index,
+	}, nil
+}
+
+// Chart returns the packaged chart of the bundle
+func (b *Bundle) Chart() ([]byte, error) {
+	return b.artifact(KindChart, nil)
+}
+
+// Helm returns the helm binary of the bundle for the current platform
+func (b *Bundle) Helm() ([]byte, error) {
+	return b.artifact(KindHelm, &Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH})
+}
+
+// Images returns the images of the bundle
+func (b *Bundle) Images() []string {
+	images := []string{}
+	for _, descriptor := range b.index.Manifests {
+		if descriptor.Annotations[AnnotationKind] == KindImage {
+			images = append(images, descriptor.Annotations[AnnotationRefName])
+		}
+	}
+
+	return images
+}
+
+// PushImages pushes all images of the bundle to the registry. The images are pushed to
+// REGISTRY/IMAGE, which matches the images the chart deploys if defaultImageRegistry is set to REGISTRY/.
+func (b *Bundle) PushImages(ctx context.Context, client *Client, registry string, log log.Logger) error {
+	registry = strings.TrimSuffix(registry, "/")
+	for i := range b.index.Manifests {
+		descriptor := &b.index.Manifests[i]
+		if descriptor.Annotations[AnnotationKind] != KindImage {
+			continue
+		}
+
+		image := descriptor.Annotations[AnnotationRefName]
+		target, err := ParseReference(registry + "/" + image)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Push image %s to %s", image, target)
+		err = client.Push(ctx, b.layout, descriptor, target)
+		if err != nil {
+			return errors.Wrapf(err, "push image %s", image)
+		}
+	}
+
+	return nil
+}
+
+func (b *Bundle) artifact(kind string, platform *Platform) ([]byte, error) {
+	for _, descriptor := range b.index.Manifests {
+		if descriptor.Annotations[AnnotationKind] != kind {
+			continue
+		} else if platform != nil && (descriptor.Platform == nil || descriptor.Platform.String() != platform.String()) {
+			continue
+		}
+
+		manifest, _, err := b.layout.ReadManifest(descriptor.Digest)
+		if err != nil {
+			return nil, err
+		} else if len(manifest.Layers) != 1 {
+			return nil, fmt.Errorf("unexpected number of layers in %s of bundle", kind)
+		}
+
+		return b.layout.ReadBlob(manifest.Layers[0].Digest)
+	}
+
+	if platform != nil {
+		return nil, fmt.Errorf("bundle doesn't contain %s for platform %s", kind, platform)
+	}
+	return nil, fmt.Errorf("bundle doesn't contain %s", kind)
+}
@@ -0,0 +1,204 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"gotest.tools/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := map[string]string{
+		"alpine":                                 "registry-1.docker.io/library/alpine:latest",
+		"rancher/k3s:v1.26.0-k3s1":               "registry-1.docker.io/rancher/k3s:v1.26.0-k3s1",
+		"ghcr.io/loft-sh/vcluster:0.15.0":        "ghcr.io/loft-sh/vcluster:0.15.0",
+		"localhost:5000/coredns/coredns:1.10.1":  "localhost:5000/coredns/coredns:1.10.1",
+		"registry.k8s.io/etcd@sha256:0123456789": "registry.k8s.io/etcd@sha256:0123456789",
+	}
+	for image, expected := range tests {
+		ref, err := ParseReference(image)
+		assert.NilError(t, err)
+		assert.Equal(t, ref.String(), expected)
+	}
+
+	_, err := ParseReference("ghcr.io/loft-sh/vcluster:")
+	assert.ErrorContains(t, err, "invalid image reference")
+}
+
+func TestImagesFromManifests(t *testing.T) {
+	manifests := `
+apiVersion: apps/v1
+kind: StatefulSet
+spec:
+  template:
+    spec:
+      containers:
+      - image: rancher/k3s:v1.26.0-k3s1
+        name: vcluster
+      - name: syncer
+        image: "ghcr.io/loft-sh/vcluster:0.15.0"
+---
+apiVersion: v1
+kind: ConfigMap
+data:
+  coredns.yaml: |-
+    containers:
+      - name: coredns
+        image: {{.IMAGE}}
+      - name: other
+        image: coredns/coredns:1.10.1
+`
+
+	assert.DeepEqual(t, ImagesFromManifests(manifests), []string{
+		"coredns/coredns:1.10.1",
+		"ghcr.io/loft-sh/vcluster:0.15.0",
+		"rancher/k3s:v1.26.0-k3s1",
+	})
+}
+
+func TestCreateAndPushBundle(t *testing.T) {
+	source := newFakeRegistry()
+	sourceServer := httptest.NewServer(source)
+	defer sourceServer.Close()
+	target := newFakeRegistry()
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+
+	// serve a multi platform image from the source registry
+	layer := source.addBlob([]byte("layer"))
+	config := source.addBlob([]byte("{}"))
+	manifest := mustMarshal(t, &Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        &Descriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: config, Size: 2},
+		Layers:        []Descriptor{{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Digest: layer, Size: 5}},
+	})
+	manifestDigest := source.addBlob(manifest)
+	source.manifests["loft-sh/vcluster:0.15.0"] = mustMarshal(t, &Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageIndex,
+		Manifests: []Descriptor{
+			{MediaType: MediaTypeImageManifest, Digest: source.addBlob([]byte("arm64")), Size: 5, Platform: &Platform{OS: "linux", Architecture: "arm64"}},
+			{MediaType: MediaTypeImageManifest, Digest: manifestDigest, Size: int64(len(manifest)), Platform: &Platform{OS: "linux", Architecture: "amd64"}},
+		},
+	})
+	source.manifests["loft-sh/vcluster:"+manifestDigest] = manifest
+
+	client := NewClient()
+	client.PlainHTTP = func(string) bool { return true }
+	image := strings.TrimPrefix(sourceServer.URL, "http://") + "/loft-sh/vcluster:0.15.0"
+
+	// create the bundle
+	dir := t.TempDir()
+	builder, err := NewBuilder(filepath.Join(dir, "layout"), Metadata{Distro: "k3s", ChartName: "vcluster", ChartVersion: "0.15.0", KubernetesVersion: "v1.26"}, &Platform{OS: "linux", Architecture: "amd64"}, client)
+	assert.NilError(t, err)
+	assert.NilError(t, builder.AddChart([]byte("chart"), "vcluster", "0.15.0"))
+	assert.NilError(t, builder.AddImage(context.Background(), image))
+	assert.NilError(t, builder.Close())
+
+	archive := filepath.Join(dir, "bundle.tar.gz")
+	file, err := os.Create(archive)
+	assert.NilError(t, err)
+	assert.NilError(t, builder.Layout().Archive(file))
+	assert.NilError(t, file.Close())
+
+	// open the bundle and push the images
+	installBundle, err := Open(archive, filepath.Join(dir, "extracted"))
+	assert.NilError(t, err)
+	assert.Equal(t, installBundle.Metadata.KubernetesVersion, "v1.26")
+	assert.DeepEqual(t, installBundle.Images(), []string{image})
+	chart, err := installBundle.Chart()
+	assert.NilError(t, err)
+	assert.Equal(t, string(chart), "chart")
+	_, err = installBundle.Helm()
+	assert.ErrorContains(t, err, "doesn't contain helm")
+
+	registry := strings.TrimPrefix(targetServer.URL, "http://") + "/mirror"
+	err = installBundle.PushImages(context.Background(), client, registry, log.Discard)
+	assert.NilError(t, err)
+
+	pushed := "mirror/" + strings.TrimPrefix(sourceServer.URL, "http://") + "/loft-sh/vcluster:0.15.0"
+	assert.DeepEqual(t, target.manifests[pushed], manifest)
+	assert.DeepEqual(t, target.blobs[layer], []byte("layer"))
+	assert.DeepEqual(t, target.blobs[config], []byte("{}"))
+}
+
+func mustMarshal(t *testing.T, obj interface{}) []byte {
+	out, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	return out
+}
+
+// fakeRegistry implements the parts of the registry v2 api the client uses
+type fakeRegistry struct {
+	m         sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		blobs:     map[string][]byte{},
+		manifests: map[string][]byte{},
+	}
+}
+
+func (f *fakeRegistry) addBlob(content []byte) string {
+	hash := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(hash[:])
+	f.blobs[digest] = content
+	return digest
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case strings.Contains(path, "/manifests/"):
+		repository, reference, _ := strings.Cut(path, "/manifests/")
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			f.manifests[repository+":"+reference] = body
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		manifest, ok := f.manifests[repository+":"+reference]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(manifest)
+	case strings.HasSuffix(path, "/blobs/uploads/"):
+		w.Header().Set("Location", "/upload?id=1")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/"):
+		_, digest, _ := strings.Cut(path, "/blobs/")
+		blob, ok := f.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.Copy(w, bytes.NewReader(blob))
+	case r.URL.Path == "/upload":
+		body, _ := io.ReadAll(r.Body)
+		f.blobs[r.URL.Query().Get("digest")] = body
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
@@ -0,0 +1,289 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	MediaTypeImageIndex        = "application/vnd.oci.image.index.v1+json"
+	MediaTypeImageManifest     = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerManifest    = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerList        = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeEmptyConfig       = "application/vnd.oci.empty.v1+json"
+	MediaTypeHelmConfig        = "application/vnd.cncf.helm.config.v1+json"
+	MediaTypeHelmChartContent  = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	MediaTypeHelmBinaryContent = "application/vnd.loft.vcluster.helm.binary"
+
+	// AnnotationRefName is the standard annotation for the image name of a manifest within an index
+	AnnotationRefName = "org.opencontainers.image.ref.name"
+	// AnnotationTitle is the standard annotation for the file name of a layer
+	AnnotationTitle = "org.opencontainers.image.title"
+
+	layoutFile    = "oci-layout"
+	indexFile     = "index.json"
+	layoutVersion = `{"imageLayoutVersion":"1.0.0"}`
+)
+
+// Platform describes the platform of an image manifest
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ParsePlatform parses a platform in the format os/arch[/variant]
+func ParsePlatform(platform string) (*Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid platform %q, please use the format os/arch[/variant]", platform)
+	}
+
+	parsed := &Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		parsed.Variant = parts[2]
+	}
+	return parsed, nil
+}
+
+func (p *Platform) String() string {
+	platform := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		platform += "/" + p.Variant
+	}
+
+	return platform
+}
+
+// Descriptor references a blob within the layout or a registry
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Platform    *Platform         `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest holds the fields of image manifests and image indexes we care about
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	Config        *Descriptor       `json:"config,omitempty"`
+	Layers        []Descriptor      `json:"layers,omitempty"`
+	Manifests     []Descriptor      `json:"manifests,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Layout is an OCI image layout within a directory
+type Layout struct {
+	Path string
+}
+
+// NewLayout creates an empty OCI image layout within the given directory
+func NewLayout(path string) (*Layout, error) {
+	err := os.MkdirAll(filepath.Join(path, "blobs", "sha256"), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(path, layoutFile), []byte(layoutVersion), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Layout{Path: path}, nil
+}
+
+// OpenLayout opens an existing OCI image layout
+func OpenLayout(path string) (*Layout, error) {
+	_, err := os.Stat(filepath.Join(path, layoutFile))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an OCI image layout: %w", path, err)
+	}
+
+	return &Layout{Path: path}, nil
+}
+
+// BlobPath returns the path of the blob with the given digest
+func (l *Layout) BlobPath(digest string) string {
+	return filepath.Join(l.Path, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:"))
+}
+
+// HasBlob returns true if the blob with the given digest exists
+func (l *Layout) HasBlob(digest string) bool {
+	_, err := os.Stat(l.BlobPath(digest))
+	return err == nil
+}
+
+// WriteBlob writes the content into the layout and verifies it against the expected digest, if set
+func (l *Layout) WriteBlob(r io.Reader, expectedDigest string) (string, int64, error) {
+	tempFile, err := os.CreateTemp(filepath.Join(l.Path, "blobs"), "blob-")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tempFile, hash), r)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "write blob")
+	}
+	digest := "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	if expectedDigest != "" && digest != expectedDigest {
+		return "", 0, fmt.Errorf("digest mismatch, expected %s but got %s", expectedDigest, digest)
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return "", 0, err
+	}
+	err = os.Rename(tempFile.Name(), l.BlobPath(digest))
+	if err != nil {
+		return "", 0, err
+	}
+
+	return digest, size, nil
+}
+
+// ReadBlob reads the blob with the given digest
+func (l *Layout) ReadBlob(digest string) ([]byte, error) {
+	return os.ReadFile(l.BlobPath(digest))
+}
+
+// ReadManifest reads and parses the manifest with the given digest
+func (l *Layout) ReadManifest(digest string) (*Manifest, []byte, error) {
+	out, err := l.ReadBlob(digest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifest := &Manifest{}
+	err = json.Unmarshal(out, manifest)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "parse manifest %s", digest)
+	}
+
+	return manifest, out, nil
+}
+
+// Index reads the index of the layout
+func (l *Layout) Index() (*Manifest, error) {
+	out, err := os.ReadFile(filepath.Join(l.Path, indexFile))
+	if err != nil {
+		return nil, err
+	}
+
+	index := &Manifest{}
+	err = json.Unmarshal(out, index)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse index")
+	}
+
+	return index, nil
+}
+
+// WriteIndex writes the index of the layout
+func (l *Layout) WriteIndex(index *Manifest) error {
+	out, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(l.Path, indexFile), out, 0644)
+}
+
+// Archive writes the layout as tar.gz
+func (l *Layout) Archive(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	err := filepath.Walk(l.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.IsDir() {
+			return nil
+		}
+
+		name, err := filepath.Rel(l.Path, path)
+		if err != nil {
+			return err
+		}
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name:    filepath.ToSlash(name),
+			Mode:    0644,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// Extract extracts a layout archived by Archive into the given directory
+func Extract(r io.Reader, path string) (*Layout, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read bundle")
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read bundle")
+		} else if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(path, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(path)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("invalid file %s in bundle", header.Name)
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0755)
+		if err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(file, tarReader)
+		file.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "extract %s", header.Name)
+		}
+	}
+
+	return OpenLayout(path)
+}
@@ -0,0 +1,84 @@
+package bundle
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	defaultTag        = "latest"
+)
+
+// Reference is a parsed image reference such as ghcr.io/loft-sh/vcluster:0.15.0
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image reference the same way docker does, so images without a registry are
+// pulled from docker hub and images without a tag or digest use the latest tag
+func ParseReference(image string) (*Reference, error) {
+	if image == "" || strings.ContainsAny(image, " \t\n") {
+		return nil, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	ref := &Reference{}
+	name := image
+	if index := strings.Index(name, "@"); index != -1 {
+		ref.Digest = name[index+1:]
+		name = name[:index]
+		if !strings.HasPrefix(ref.Digest, "sha256:") {
+			return nil, fmt.Errorf("invalid digest in image reference %q", image)
+		}
+	}
+	if index := strings.LastIndex(name, ":"); index != -1 && !strings.Contains(name[index:], "/") {
+		ref.Tag = name[index+1:]
+		name = name[:index]
+	}
+
+	registry, repository, found := strings.Cut(name, "/")
+	if !found || (!strings.ContainsAny(registry, ".:") && registry != "localhost") {
+		registry = "docker.io"
+		repository = name
+	}
+	if registry == "docker.io" {
+		registry = dockerHubRegistry
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	if repository == "" || ref.Tag == "" && strings.HasSuffix(image, ":") {
+		return nil, fmt.Errorf("invalid image reference %q", image)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	ref.Registry = registry
+	ref.Repository = repository
+	return ref, nil
+}
+
+// Identifier returns the digest of the reference if set, otherwise the tag
+func (r *Reference) Identifier() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+
+	return r.Tag
+}
+
+func (r *Reference) String() string {
+	name := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		name += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		name += "@" + r.Digest
+	}
+
+	return name
+}
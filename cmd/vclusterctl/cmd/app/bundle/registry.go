@@ -0,0 +1,447 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var manifestMediaTypes = []string{
+	MediaTypeImageIndex,
+	MediaTypeImageManifest,
+	MediaTypeDockerList,
+	MediaTypeDockerManifest,
+}
+
+// Client pulls and pushes images from and to registries via the registry v2 api. Credentials are
+// read from the docker config, credential helpers are not supported.
+type Client struct {
+	HTTPClient *http.Client
+
+	// PlainHTTP returns true if the registry should be accessed via http instead of https
+	PlainHTTP func(registry string) bool
+
+	// auths are the base64 encoded basic auth credentials per registry
+	auths map[string]string
+
+	tokensMutex sync.Mutex
+	tokens      map[string]string
+}
+
+// NewClient creates a new registry client with the credentials of the docker config
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: http.DefaultClient,
+		PlainHTTP:  isLocalRegistry,
+		auths:      loadDockerAuths(),
+		tokens:     map[string]string{},
+	}
+}
+
+func isLocalRegistry(registry string) bool {
+	host := registry
+	if index := strings.LastIndex(host, ":"); index != -1 {
+		host = host[:index]
+	}
+
+	return host == "localhost" || host == "127.0.0.1"
+}
+
+func loadDockerAuths() map[string]string {
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+
+		configDir = filepath.Join(home, ".docker")
+	}
+
+	out, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		return nil
+	}
+
+	config := struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	err = json.Unmarshal(out, &config)
+	if err != nil {
+		return nil
+	}
+
+	auths := map[string]string{}
+	for registry, auth := range config.Auths {
+		registry = strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+		registry = strings.Split(registry, "/")[0]
+		if registry == "index.docker.io" || registry == "docker.io" {
+			registry = dockerHubRegistry
+		}
+		if auth.Auth != "" {
+			auths[registry] = auth.Auth
+		}
+	}
+
+	return auths
+}
+
+// Pull copies the image manifest for the given platform together with its config and layers into the layout
+func (c *Client) Pull(ctx context.Context, ref *Reference, platform *Platform, layout *Layout) (*Descriptor, error) {
+	mediaType, manifestBytes, err := c.getManifest(ctx, ref, ref.Identifier())
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	err = json.Unmarshal(manifestBytes, manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse manifest of %s", ref)
+	}
+
+	// select the manifest of the platform
+	if mediaType == MediaTypeImageIndex || mediaType == MediaTypeDockerList {
+		var selected *Descriptor
+		for i, descriptor := range manifest.Manifests {
+			if descriptor.Platform != nil && descriptor.Platform.OS == platform.OS && descriptor.Platform.Architecture == platform.Architecture && (platform.Variant == "" || descriptor.Platform.Variant == platform.Variant) {
+				selected = &manifest.Manifests[i]
+				break
+			}
+		}
+		if selected == nil {
+			return nil, fmt.Errorf("image %s is not available for platform %s", ref, platform)
+		}
+
+		mediaType, manifestBytes, err = c.getManifest(ctx, ref, selected.Digest)
+		if err != nil {
+			return nil, err
+		}
+		manifest = &Manifest{}
+		err = json.Unmarshal(manifestBytes, manifest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse manifest of %s", ref)
+		}
+	}
+
+	blobs := manifest.Layers
+	if manifest.Config != nil {
+		blobs = append(blobs, *manifest.Config)
+	}
+	for _, blob := range blobs {
+		if layout.HasBlob(blob.Digest) {
+			continue
+		}
+
+		err = c.pullBlob(ctx, ref, blob.Digest, layout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pull blob %s of %s", blob.Digest, ref)
+		}
+	}
+
+	digest, size, err := layout.WriteBlob(bytes.NewReader(manifestBytes), "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Descriptor{
+		MediaType: mediaType,
+		Digest:    digest,
+		Size:      size,
+		Platform:  platform,
+	}, nil
+}
+
+// Push pushes the manifest of the layout together with its config and layers to the given reference
+func (c *Client) Push(ctx context.Context, layout *Layout, descriptor *Descriptor, ref *Reference) error {
+	manifest, manifestBytes, err := layout.ReadManifest(descriptor.Digest)
+	if err != nil {
+		return err
+	}
+
+	blobs := manifest.Layers
+	if manifest.Config != nil {
+		blobs = append(blobs, *manifest.Config)
+	}
+	for _, blob := range blobs {
+		err = c.pushBlob(ctx, ref, blob, layout)
+		if err != nil {
+			return errors.Wrapf(err, "push blob %s to %s", blob.Digest, ref)
+		}
+	}
+
+	response, err := c.do(ctx, ref, "pull,push", http.MethodPut, c.url(ref, "/manifests/"+ref.Identifier()), http.Header{"Content-Type": {descriptor.MediaType}}, func() io.Reader {
+		return bytes.NewReader(manifestBytes)
+	}, int64(len(manifestBytes)))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		return responseError(response, "push manifest to "+ref.String())
+	}
+
+	return nil
+}
+
+func (c *Client) getManifest(ctx context.Context, ref *Reference, identifier string) (string, []byte, error) {
+	response, err := c.do(ctx, ref, "pull", http.MethodGet, c.url(ref, "/manifests/"+identifier), http.Header{"Accept": {strings.Join(manifestMediaTypes, ", ")}}, nil, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", nil, responseError(response, "get manifest of "+ref.String())
+	}
+
+	manifestBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if strings.HasPrefix(identifier, "sha256:") {
+		hash := sha256.Sum256(manifestBytes)
+		if digest := "sha256:" + hex.EncodeToString(hash[:]); digest != identifier {
+			return "", nil, fmt.Errorf("digest mismatch for manifest of %s, expected %s but got %s", ref, identifier, digest)
+		}
+	}
+
+	mediaType := strings.Split(response.Header.Get("Content-Type"), ";")[0]
+	manifest := &Manifest{}
+	if json.Unmarshal(manifestBytes, manifest) == nil && manifest.MediaType != "" {
+		mediaType = manifest.MediaType
+	}
+
+	return mediaType, manifestBytes, nil
+}
+
+func (c *Client) pullBlob(ctx context.Context, ref *Reference, digest string, layout *Layout) error {
+	response, err := c.do(ctx, ref, "pull", http.MethodGet, c.url(ref, "/blobs/"+digest), nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return responseError(response, "get blob")
+	}
+
+	_, _, err = layout.WriteBlob(response.Body, digest)
+	return err
+}
+
+func (c *Client) pushBlob(ctx context.Context, ref *Reference, blob Descriptor, layout *Layout) error {
+	// check if the blob already exists
+	response, err := c.do(ctx, ref, "pull,push", http.MethodHead, c.url(ref, "/blobs/"+blob.Digest), nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// start the upload
+	response, err = c.do(ctx, ref, "pull,push", http.MethodPost, c.url(ref, "/blobs/uploads/"), nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusAccepted {
+		return responseError(response, "start upload")
+	}
+
+	location, err := response.Request.URL.Parse(response.Header.Get("Location"))
+	if err != nil {
+		return errors.Wrap(err, "parse upload location")
+	}
+	query := location.Query()
+	query.Set("digest", blob.Digest)
+	location.RawQuery = query.Encode()
+
+	// upload the blob in a single request
+	blobPath := layout.BlobPath(blob.Digest)
+	stat, err := os.Stat(blobPath)
+	if err != nil {
+		return err
+	}
+	// the file is closed by the http client after the request was sent
+	response, err = c.do(ctx, ref, "pull,push", http.MethodPut, location.String(), http.Header{"Content-Type": {"application/octet-stream"}}, func() io.Reader {
+		file, openErr := os.Open(blobPath)
+		if openErr != nil {
+			return &errorReader{err: openErr}
+		}
+		return file
+	}, stat.Size())
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		return responseError(response, "upload blob")
+	}
+
+	return nil
+}
+
+func (c *Client) url(ref *Reference, path string) string {
+	scheme := "https"
+	if c.PlainHTTP != nil && c.PlainHTTP(ref.Registry) {
+		scheme = "http"
+	}
+
+	return scheme + "://" + ref.Registry + "/v2/" + ref.Repository + path
+}
+
+// do executes the request and authenticates against the registry if it responds with unauthorized
+func (c *Client) do(ctx context.Context, ref *Reference, actions, method, rawURL string, header http.Header, body func() io.Reader, size int64) (*http.Response, error) {
+	tokenKey := ref.Registry + "/" + ref.Repository + ":" + actions
+	send := func() (*http.Response, error) {
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = body()
+		}
+
+		request, err := http.NewRequestWithContext(ctx, method, rawURL, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			request.Header[key] = values
+		}
+		if body != nil {
+			request.ContentLength = size
+		}
+
+		c.tokensMutex.Lock()
+		token := c.tokens[tokenKey]
+		c.tokensMutex.Unlock()
+		if token != "" {
+			request.Header.Set("Authorization", token)
+		}
+
+		return c.HTTPClient.Do(request)
+	}
+
+	response, err := send()
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusUnauthorized {
+		return response, nil
+	}
+	response.Body.Close()
+
+	token, err := c.authenticate(ctx, ref, actions, response.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "authenticate against %s", ref.Registry)
+	}
+	c.tokensMutex.Lock()
+	c.tokens[tokenKey] = token
+	c.tokensMutex.Unlock()
+
+	return send()
+}
+
+// authenticate returns the authorization header for the given challenge
+func (c *Client) authenticate(ctx context.Context, ref *Reference, actions, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	basicAuth := c.auths[ref.Registry]
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if basicAuth == "" {
+			return "", fmt.Errorf("no credentials found, please run docker login %s", ref.Registry)
+		}
+
+		return "Basic " + basicAuth, nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	challengeParams := parseChallengeParams(params)
+	tokenURL, err := url.Parse(challengeParams["realm"])
+	if err != nil || challengeParams["realm"] == "" {
+		return "", fmt.Errorf("invalid authentication challenge %q", challenge)
+	}
+	query := tokenURL.Query()
+	if challengeParams["service"] != "" {
+		query.Set("service", challengeParams["service"])
+	}
+	query.Set("scope", "repository:"+ref.Repository+":"+actions)
+	tokenURL.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if basicAuth != "" {
+		request.Header.Set("Authorization", "Basic "+basicAuth)
+	}
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", responseError(response, "get token")
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&token)
+	if err != nil {
+		return "", errors.Wrap(err, "parse token")
+	} else if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	return "Bearer " + token.Token, nil
+}
+
+func parseChallengeParams(params string) map[string]string {
+	parsed := map[string]string{}
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(strings.TrimLeft(params, " ,"), "=")
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+
+		parsed[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+
+	return parsed
+}
+
+func responseError(response *http.Response, operation string) error {
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("%s: unexpected status code %d: %s", operation, response.StatusCode, strings.TrimSpace(string(message)))
+}
+
+type errorReader struct {
+	err error
+}
+
+func (e *errorReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
@@ -25,6 +25,11 @@ type CreateOptions struct {
 	Upgrade       bool
 	Isolate       bool
 	ReleaseValues string
+
+	SkipPreflightChecks bool
+
+	Bundle         string
+	BundleRegistry string
 }
 
 type Values struct {
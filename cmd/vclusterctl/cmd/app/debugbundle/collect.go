@@ -0,0 +1,309 @@
+package debugbundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/describe"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/controllers/manifests"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// syncerContainer is the name of the syncer container within the control plane pods
+const syncerContainer = "syncer"
+
+// metricsFlags are the syncer flags that expose controller metrics
+var metricsFlags = []string{"--host-metrics-bind-address", "--virtual-metrics-bind-address"}
+
+// pprofProfiles are the profiles that are collected from the syncer if DEBUG is enabled
+var pprofProfiles = map[string]string{
+	"goroutine": "debug=2",
+	"heap":      "",
+	"allocs":    "",
+	"mutex":     "",
+	"block":     "",
+}
+
+// Collector collects the debug information of a virtual cluster into a bundle
+type Collector struct {
+	Name      string
+	Namespace string
+
+	HostClient kubernetes.Interface
+
+	// VirtualClient is the client of the virtual cluster, it's nil if the virtual cluster is not reachable
+	VirtualClient kubernetes.Interface
+
+	// TailLines limits the number of log lines per container, all lines are collected if it's negative
+	TailLines int64
+
+	Log log.Logger
+
+	errors []string
+}
+
+// Collect collects all information into the bundle. Errors of single collectors are written to the
+// bundle and do not stop the collection, so that the bundle contains as much information as possible.
+func (c *Collector) Collect(ctx context.Context, w *Writer) error {
+	c.collect("helm release", func() error { return c.collectRelease(ctx, w) })
+	c.collect("init manifests", func() error { return c.collectInitManifests(ctx, w) })
+	c.collect("host events", func() error {
+		return collectEvents(ctx, c.HostClient, c.Namespace, w, "host/events.yaml")
+	})
+
+	pods := []corev1.Pod{}
+	c.collect("control plane pods", func() error {
+		podList, err := c.HostClient.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "release=" + c.Name + "," + describe.ControlPlaneSelector,
+		})
+		if err != nil {
+			return err
+		}
+
+		pods = podList.Items
+		return nil
+	})
+	for i := range pods {
+		pod := &pods[i]
+		c.collect("pod "+pod.Name, func() error {
+			return w.AddYAML(path.Join("host", "pods", pod.Name+".yaml"), RedactPod(pod))
+		})
+		c.collectLogs(ctx, pod, w)
+		c.collectMetrics(ctx, pod, w)
+	}
+
+	if c.VirtualClient != nil {
+		c.collect("virtual events", func() error {
+			return collectEvents(ctx, c.VirtualClient, metav1.NamespaceAll, w, "virtual/events.yaml")
+		})
+		if debugEnabled(pods) {
+			c.collectProfiles(ctx, w)
+			c.collect("virtual metrics", func() error {
+				out, err := c.VirtualClient.Discovery().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+				if err != nil {
+					return err
+				}
+
+				return w.Add("virtual/metrics.txt", out)
+			})
+		}
+	}
+
+	if len(c.errors) > 0 {
+		return w.Add("errors.txt", []byte(strings.Join(c.errors, "\n")+"\n"))
+	}
+
+	return nil
+}
+
+func (c *Collector) collect(name string, collect func() error) {
+	c.Log.Debugf("Collect %s", name)
+	err := collect()
+	if err != nil {
+		c.Log.Warnf("Error collecting %s: %v", name, err)
+		c.errors = append(c.errors, fmt.Sprintf("%s: %v", name, err))
+	}
+}
+
+func (c *Collector) collectRelease(ctx context.Context, w *Writer) error {
+	release, err := helm.NewSecrets(c.HostClient).Get(ctx, c.Name, c.Namespace)
+	if err != nil {
+		return err
+	}
+
+	info := map[string]interface{}{
+		"name":      release.Name,
+		"namespace": release.Namespace,
+		"revision":  release.Version,
+	}
+	if release.Info != nil {
+		info["status"] = release.Info.Status
+		info["description"] = release.Info.Description
+		info["lastDeployed"] = release.Info.LastDeployed
+	}
+	if release.Chart != nil && release.Chart.Metadata != nil {
+		info["chart"] = release.Chart.Metadata.Name
+		info["chartVersion"] = release.Chart.Metadata.Version
+	}
+	err = w.AddYAML("helm/release.yaml", info)
+	if err != nil {
+		return err
+	}
+
+	return w.AddYAML("helm/values.yaml", RedactValues(release.Config))
+}
+
+func (c *Collector) collectInitManifests(ctx context.Context, w *Writer) error {
+	configMap, err := c.HostClient.CoreV1().ConfigMaps(c.Namespace).Get(ctx, c.Name+manifests.InitManifestSuffix, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = w.AddYAML("init-manifests/status.yaml", manifests.ParseStatus(configMap))
+	if err != nil {
+		return err
+	}
+
+	configMap.ManagedFields = nil
+	if configMap.Data[manifests.InitManifestsKey] != "" {
+		configMap.Data[manifests.InitManifestsKey] = RedactManifests(configMap.Data[manifests.InitManifestsKey])
+	}
+	if configMap.Data[manifests.InitChartsKey] != "" {
+		// chart values might contain credentials as well
+		configMap.Data[manifests.InitChartsKey] = Redacted
+	}
+
+	return w.AddYAML("init-manifests/configmap.yaml", configMap)
+}
+
+func collectEvents(ctx context.Context, kubeClient kubernetes.Interface, namespace string, w *Writer, name string) error {
+	events, err := kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(&events.Items[i]).Before(eventTime(&events.Items[j]))
+	})
+	for i := range events.Items {
+		events.Items[i].ManagedFields = nil
+	}
+
+	return w.AddYAML(name, events.Items)
+}
+
+func eventTime(event *corev1.Event) *metav1.Time {
+	if !event.LastTimestamp.IsZero() {
+		return &event.LastTimestamp
+	} else if !event.EventTime.IsZero() {
+		return &metav1.Time{Time: event.EventTime.Time}
+	}
+
+	return &event.CreationTimestamp
+}
+
+func (c *Collector) collectLogs(ctx context.Context, pod *corev1.Pod, w *Writer) {
+	containers := append([]corev1.Container{}, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	restarted := map[string]bool{}
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		restarted[status.Name] = status.RestartCount > 0
+	}
+
+	for _, container := range containers {
+		c.collect("logs of "+pod.Name+"/"+container.Name, func() error {
+			return c.collectLog(ctx, pod, container.Name, false, w)
+		})
+		if restarted[container.Name] {
+			c.collect("previous logs of "+pod.Name+"/"+container.Name, func() error {
+				return c.collectLog(ctx, pod, container.Name, true, w)
+			})
+		}
+	}
+}
+
+func (c *Collector) collectLog(ctx context.Context, pod *corev1.Pod, container string, previous bool, w *Writer) error {
+	options := &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}
+	if c.TailLines >= 0 {
+		options.TailLines = &c.TailLines
+	}
+
+	stream, err := c.HostClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	out, err := io.ReadAll(stream)
+	if err != nil {
+		return err
+	}
+
+	name := container + ".log"
+	if previous {
+		name = container + ".previous.log"
+	}
+	return w.Add(path.Join("host", "logs", pod.Name, name), out)
+}
+
+// collectMetrics collects the controller metrics of the syncer through the pod proxy, if they are exposed
+func (c *Collector) collectMetrics(ctx context.Context, pod *corev1.Pod, w *Writer) {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != syncerContainer {
+			continue
+		}
+
+		for _, arg := range append(container.Command, container.Args...) {
+			for _, flag := range metricsFlags {
+				address, found := strings.CutPrefix(arg, flag+"=")
+				if !found || address == "0" {
+					continue
+				}
+
+				port := address[strings.LastIndex(address, ":")+1:]
+				c.collect("metrics of "+pod.Name+":"+port, func() error {
+					out, err := c.HostClient.CoreV1().Pods(pod.Namespace).ProxyGet("http", pod.Name, port, "/metrics", nil).DoRaw(ctx)
+					if err != nil {
+						return err
+					}
+
+					return w.Add(path.Join("host", "metrics", pod.Name, strings.TrimPrefix(flag, "--")+".txt"), out)
+				})
+			}
+		}
+	}
+}
+
+// collectProfiles collects the pprof profiles, which the syncer serves through the virtual cluster api if DEBUG is enabled
+func (c *Collector) collectProfiles(ctx context.Context, w *Writer) {
+	for profile, query := range pprofProfiles {
+		c.collect("pprof "+profile, func() error {
+			request := c.VirtualClient.Discovery().RESTClient().Get().AbsPath("/debug/pprof", profile)
+			extension := ".pb.gz"
+			if query != "" {
+				key, value, _ := strings.Cut(query, "=")
+				request = request.Param(key, value)
+				extension = ".txt"
+			}
+
+			out, err := request.DoRaw(ctx)
+			if err != nil {
+				return err
+			}
+
+			return w.Add(path.Join("virtual", "pprof", profile+extension), out)
+		})
+	}
+}
+
+func debugEnabled(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name != syncerContainer {
+				continue
+			}
+
+			for _, env := range container.Env {
+				if env.Name == "DEBUG" && env.Value == "true" {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,149 @@
+package debugbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/controllers/manifests"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRedactValues(t *testing.T) {
+	values := map[string]interface{}{
+		"syncer": map[string]interface{}{
+			"extraArgs": []interface{}{"--tls-san=my-vcluster.example.com", "--service-account-token=abc"},
+			"env": []interface{}{
+				map[string]interface{}{"name": "DEBUG", "value": "true"},
+				map[string]interface{}{"name": "AWS_SECRET_ACCESS_KEY", "value": "abc"},
+			},
+		},
+		"etcd": map[string]interface{}{
+			"password":        "abc",
+			"secretName":      "etcd-certs",
+			"tokenExpiration": 3600,
+		},
+		"init": map[string]interface{}{
+			"manifests":         "apiVersion: v1\nkind: Secret\nmetadata:\n  name: test\nstringData:\n  password: abc\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\ndata:\n  password: abc\n",
+			"manifestsTemplate": "apiVersion: v1\nkind: Secret\nmetadata:\n  name: {{ .Release.Name }}\nstringData:\n  password: abc\n",
+			"helm": []interface{}{
+				map[string]interface{}{
+					"chart":  map[string]interface{}{"name": "test", "repo": "https://charts.example.com"},
+					"values": "auth:\n  rootPassword: abc\n",
+				},
+				map[string]interface{}{
+					"chart": map[string]interface{}{"name": "without-values"},
+				},
+			},
+		},
+	}
+
+	redacted := RedactValues(values)
+	syncer := redacted["syncer"].(map[string]interface{})
+	assert.DeepEqual(t, syncer["extraArgs"], []interface{}{"--tls-san=my-vcluster.example.com", "--service-account-token=REDACTED"})
+	assert.Equal(t, syncer["env"].([]interface{})[0].(map[string]interface{})["value"], "true")
+	assert.Equal(t, syncer["env"].([]interface{})[1].(map[string]interface{})["value"], Redacted)
+
+	etcd := redacted["etcd"].(map[string]interface{})
+	assert.Equal(t, etcd["password"], Redacted)
+	assert.Equal(t, etcd["secretName"], "etcd-certs")
+	assert.Equal(t, etcd["tokenExpiration"], 3600)
+
+	// only the data of secrets is redacted
+	initManifests := redacted["init"].(map[string]interface{})["manifests"].(string)
+	assert.Assert(t, strings.Contains(initManifests, "password: REDACTED"))
+	assert.Assert(t, strings.Contains(initManifests, "password: abc"))
+
+	// templates and chart values are redacted completely
+	assert.Equal(t, redacted["init"].(map[string]interface{})["manifestsTemplate"], Redacted)
+	initCharts := redacted["init"].(map[string]interface{})["helm"].([]interface{})
+	assert.Equal(t, initCharts[0].(map[string]interface{})["values"], Redacted)
+	assert.Equal(t, initCharts[0].(map[string]interface{})["chart"].(map[string]interface{})["name"], "test")
+	_, ok := initCharts[1].(map[string]interface{})["values"]
+	assert.Assert(t, !ok)
+
+	// the original values are not modified
+	assert.Equal(t, values["etcd"].(map[string]interface{})["password"], "abc")
+	assert.Equal(t, values["init"].(map[string]interface{})["helm"].([]interface{})[0].(map[string]interface{})["values"], "auth:\n  rootPassword: abc\n")
+}
+
+func TestCollect(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-0",
+			Namespace: "test",
+			Labels:    map[string]string{"app": "vcluster", "release": "test"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "vcluster"},
+				{Name: "syncer", Env: []corev1.EnvVar{{Name: "VCLUSTER_TOKEN", Value: "abc"}}},
+			},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test" + manifests.InitManifestSuffix,
+			Namespace:   "test",
+			Annotations: map[string]string{manifests.StatusKey: `{"manifests":{"phase":"Failed","reason":"ApplyFailed"}}`},
+		},
+		Data: map[string]string{manifests.InitManifestsKey: "apiVersion: v1\nkind: Secret\nmetadata:\n  name: test\ndata:\n  token: YWJj\n"},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "test-0.1", Namespace: "test"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "test-0"},
+		Reason:         "BackOff",
+	}
+
+	collector := &Collector{
+		Name:       "test",
+		Namespace:  "test",
+		HostClient: fake.NewSimpleClientset(pod, configMap, event),
+		TailLines:  -1,
+		Log:        log.Discard,
+	}
+	buffer := &bytes.Buffer{}
+	writer := NewWriter(buffer, "bundle")
+	err := collector.Collect(context.Background(), writer)
+	assert.NilError(t, err)
+	assert.NilError(t, writer.Close())
+
+	files := readBundle(t, buffer)
+	assert.Assert(t, strings.Contains(files["bundle/init-manifests/status.yaml"], "phase: Failed"))
+	assert.Assert(t, strings.Contains(files["bundle/init-manifests/configmap.yaml"], "token: REDACTED"))
+	assert.Assert(t, strings.Contains(files["bundle/host/events.yaml"], "reason: BackOff"))
+	assert.Assert(t, strings.Contains(files["bundle/host/pods/test-0.yaml"], "value: REDACTED"))
+	assert.Equal(t, files["bundle/host/logs/test-0/syncer.log"], "fake logs")
+
+	// the missing helm release is reported within the bundle
+	assert.Assert(t, strings.Contains(files["bundle/errors.txt"], "helm release"))
+}
+
+func readBundle(t *testing.T, r io.Reader) map[string]string {
+	gzReader, err := gzip.NewReader(r)
+	assert.NilError(t, err)
+	tarReader := tar.NewReader(gzReader)
+
+	files := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+
+		content, err := io.ReadAll(tarReader)
+		assert.NilError(t, err)
+		files[header.Name] = string(content)
+	}
+
+	return files
+}
@@ -0,0 +1,174 @@
+package debugbundle
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	kyaml "sigs.k8s.io/yaml"
+)
+
+// Redacted replaces sensitive values within the bundle
+const Redacted = "REDACTED"
+
+// sensitiveKey matches the names of values, env variables and flags that usually hold credentials
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|token|secret|credential|private-?key|access-?key|api-?key|cert-?key|client-?key|tls\.key|\.key$)`)
+
+// referenceKey matches the names of values that only reference credentials, e.g. secretName
+var referenceKey = regexp.MustCompile(`(?i)(name|ref|enabled|expiration|path)$`)
+
+// IsSensitive returns true if the value with the given name should be redacted
+func IsSensitive(name string) bool {
+	return sensitiveKey.MatchString(name) && !referenceKey.MatchString(name)
+}
+
+// RedactValues returns a copy of the helm values where all sensitive values are redacted. Init manifests
+// and the values of init charts are redacted as well, as they might contain secrets.
+func RedactValues(values map[string]interface{}) map[string]interface{} {
+	redacted := redactValue("", values).(map[string]interface{})
+	initValues, ok := redacted["init"].(map[string]interface{})
+	if !ok {
+		return redacted
+	}
+
+	if manifests, ok := initValues["manifests"].(string); ok && manifests != "" {
+		initValues["manifests"] = RedactManifests(manifests)
+	}
+	if manifestsTemplate, ok := initValues["manifestsTemplate"].(string); ok && manifestsTemplate != "" {
+		// templates can't be parsed as yaml before they are rendered
+		initValues["manifestsTemplate"] = Redacted
+	}
+	if charts, ok := initValues["helm"].([]interface{}); ok {
+		for _, chart := range charts {
+			chart, ok := chart.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			// chart values might contain credentials as well
+			if chartValues, ok := chart["values"].(string); ok && chartValues != "" {
+				chart["values"] = Redacted
+			}
+		}
+	}
+
+	return redacted
+}
+
+func redactValue(key string, val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for childKey, child := range v {
+			result[childKey] = redactValue(childKey, child)
+		}
+
+		// env variables are lists of name value pairs
+		if name, ok := v["name"].(string); ok && IsSensitive(name) {
+			if _, ok := v["value"]; ok {
+				result["value"] = Redacted
+			}
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			if arg, ok := child.(string); ok && !IsSensitive(key) {
+				result[i] = RedactArg(arg)
+				continue
+			}
+
+			result[i] = redactValue(key, child)
+		}
+
+		return result
+	case nil:
+		return nil
+	default:
+		if IsSensitive(key) {
+			return Redacted
+		}
+
+		return val
+	}
+}
+
+// RedactArg redacts the value of a command line flag such as --token=abc
+func RedactArg(arg string) string {
+	name, _, found := strings.Cut(arg, "=")
+	if found && strings.HasPrefix(name, "-") && IsSensitive(name) {
+		return name + "=" + Redacted
+	}
+
+	return arg
+}
+
+// RedactManifests redacts the data of all secrets within the multi document yaml
+func RedactManifests(manifests string) string {
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifests), 4096)
+	documents := []string{}
+	for {
+		obj := map[string]interface{}{}
+		err := decoder.Decode(&obj)
+		if err != nil {
+			if err != io.EOF {
+				// we can't parse the manifests, so we can't tell what to redact
+				return Redacted
+			}
+
+			break
+		} else if len(obj) == 0 {
+			continue
+		}
+
+		if obj["kind"] == "Secret" {
+			for _, field := range []string{"data", "stringData"} {
+				data, ok := obj[field].(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				for key := range data {
+					data[key] = Redacted
+				}
+			}
+		}
+
+		out, err := kyaml.Marshal(obj)
+		if err != nil {
+			return Redacted
+		}
+		documents = append(documents, string(bytes.TrimSpace(out)))
+	}
+
+	return strings.Join(documents, "\n---\n")
+}
+
+// RedactPod redacts the sensitive env variables and arguments of all containers of the pod
+func RedactPod(pod *corev1.Pod) *corev1.Pod {
+	pod = pod.DeepCopy()
+	pod.ManagedFields = nil
+	redactContainers := func(containers []corev1.Container) {
+		for i := range containers {
+			for j := range containers[i].Env {
+				if containers[i].Env[j].Value != "" && IsSensitive(containers[i].Env[j].Name) {
+					containers[i].Env[j].Value = Redacted
+				}
+			}
+			for j := range containers[i].Args {
+				containers[i].Args[j] = RedactArg(containers[i].Args[j])
+			}
+			for j := range containers[i].Command {
+				containers[i].Command[j] = RedactArg(containers[i].Command[j])
+			}
+		}
+	}
+	redactContainers(pod.Spec.InitContainers)
+	redactContainers(pod.Spec.Containers)
+
+	return pod
+}
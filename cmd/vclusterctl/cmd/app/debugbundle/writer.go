@@ -0,0 +1,64 @@
+package debugbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"path"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Writer writes the files of a debug bundle into a tar.gz archive
+type Writer struct {
+	root      string
+	gzWriter  *gzip.Writer
+	tarWriter *tar.Writer
+}
+
+// NewWriter creates a new writer that places all files below the root directory of the archive
+func NewWriter(w io.Writer, root string) *Writer {
+	gzWriter := gzip.NewWriter(w)
+	return &Writer{
+		root:      root,
+		gzWriter:  gzWriter,
+		tarWriter: tar.NewWriter(gzWriter),
+	}
+}
+
+// Add adds a file with the given content to the archive
+func (w *Writer) Add(name string, content []byte) error {
+	err := w.tarWriter.WriteHeader(&tar.Header{
+		Name:    path.Join(w.root, name),
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.tarWriter.Write(content)
+	return err
+}
+
+// AddYAML adds the object as yaml file to the archive
+func (w *Writer) AddYAML(name string, obj interface{}) error {
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	return w.Add(name, out)
+}
+
+// Close flushes and closes the archive, but not the underlying writer
+func (w *Writer) Close() error {
+	err := w.tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return w.gzWriter.Close()
+}
@@ -0,0 +1,434 @@
+package describe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/controllers/garbagecollector"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+)
+
+// ControlPlaneSelector selects the pods and volumes of the control plane components of all distros
+const ControlPlaneSelector = "app in (vcluster,vcluster-api,vcluster-controller,vcluster-scheduler,vcluster-etcd)"
+
+// Description describes a virtual cluster and its footprint within the host cluster
+type Description struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Context   string    `json:"context,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Created   time.Time `json:"created"`
+
+	Chart             Chart    `json:"chart"`
+	Distro            string   `json:"distro,omitempty"`
+	KubernetesVersion string   `json:"kubernetesVersion,omitempty"`
+	MultiNamespace    bool     `json:"multiNamespace,omitempty"`
+	Syncers           []string `json:"syncers"`
+	Plugins           []string `json:"plugins,omitempty"`
+
+	Endpoints    []Endpoint    `json:"endpoints,omitempty"`
+	Volumes      []Volume      `json:"volumes,omitempty"`
+	ControlPlane []Pod         `json:"controlPlane,omitempty"`
+	Objects      []ObjectCount `json:"objects,omitempty"`
+
+	// Values are the values the release was deployed with
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+type Chart struct {
+	Name         string    `json:"name,omitempty"`
+	Version      string    `json:"version,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Revision     int       `json:"revision,omitempty"`
+	LastDeployed time.Time `json:"lastDeployed,omitempty"`
+}
+
+// Endpoint is a way to reach the api server of the virtual cluster
+type Endpoint struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// Volume is a persistent volume claim of the control plane
+type Volume struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	StorageClass string `json:"storageClass,omitempty"`
+	Capacity     string `json:"capacity,omitempty"`
+	// Used is the used space of the volume reported by the kubelet, if available
+	Used string `json:"used,omitempty"`
+}
+
+// Pod is a pod of the control plane
+type Pod struct {
+	Name      string    `json:"name"`
+	Component string    `json:"component"`
+	Status    string    `json:"status"`
+	Ready     string    `json:"ready"`
+	Restarts  int32     `json:"restarts"`
+	Node      string    `json:"node,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// ObjectCount is the number of host objects of a kind that were synced by the virtual cluster
+type ObjectCount struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// New creates the description of the virtual cluster from its helm release
+func New(vCluster *find.VCluster, release *helm.Release) *Description {
+	description := &Description{
+		Name:      vCluster.Name,
+		Namespace: vCluster.Namespace,
+		Context:   vCluster.Context,
+		Status:    string(vCluster.Status),
+		Created:   vCluster.Created.Time,
+		Syncers:   []string{},
+	}
+	if release == nil {
+		return description
+	}
+
+	description.Values = release.Config
+	description.Chart.Revision = release.Version
+	if release.Info != nil {
+		description.Chart.Status = release.Info.Status
+		description.Chart.LastDeployed = release.Info.LastDeployed.Time
+	}
+
+	values := map[string]interface{}{}
+	if release.Chart != nil {
+		if release.Chart.Metadata != nil {
+			description.Chart.Name = release.Chart.Metadata.Name
+			description.Chart.Version = release.Chart.Metadata.Version
+		}
+
+		helm.MergeValues(values, release.Chart.Values)
+	}
+	helm.MergeValues(values, release.Config)
+
+	description.Distro = DistroFromChart(description.Chart.Name)
+	description.KubernetesVersion = imageTag(stringValue(values, "vcluster", "image"))
+	if description.Distro == "k8s" || description.Distro == "eks" {
+		description.KubernetesVersion = imageTag(stringValue(values, "api", "image"))
+	}
+	description.MultiNamespace, _ = helm.LookupValue(values, "multiNamespaceMode", "enabled").(bool)
+
+	syncValues, _ := values["sync"].(map[string]interface{})
+	for name := range syncValues {
+		if enabled, _ := helm.LookupValue(syncValues, name, "enabled").(bool); enabled {
+			description.Syncers = append(description.Syncers, name)
+		}
+	}
+	sort.Strings(description.Syncers)
+
+	pluginValues, _ := values["plugin"].(map[string]interface{})
+	for name := range pluginValues {
+		description.Plugins = append(description.Plugins, name)
+	}
+	sort.Strings(description.Plugins)
+
+	return description
+}
+
+// Collect adds the endpoints, volumes, control plane pods and synced objects of the virtual cluster
+// within the host cluster to the description
+func (d *Description) Collect(ctx context.Context, kubeClient kubernetes.Interface, metadataClient metadata.Interface, log log.Logger) error {
+	var err error
+	d.Endpoints, err = endpoints(ctx, kubeClient, d.Name, d.Namespace)
+	if err != nil {
+		return fmt.Errorf("get endpoints: %w", err)
+	}
+
+	selector := "release=" + d.Name + "," + ControlPlaneSelector
+	pods, err := kubeClient.CoreV1().Pods(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("list control plane pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		d.ControlPlane = append(d.ControlPlane, newPod(&pod))
+	}
+	if d.KubernetesVersion == "" {
+		d.KubernetesVersion = kubernetesVersionFromPods(pods.Items)
+	}
+
+	pvcs, err := kubeClient.CoreV1().PersistentVolumeClaims(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("list control plane volumes: %w", err)
+	}
+	usage := volumeUsage(ctx, kubeClient, pods.Items, log)
+	for _, pvc := range pvcs.Items {
+		volume := Volume{
+			Name:   pvc.Name,
+			Status: string(pvc.Status.Phase),
+			Used:   usage[pvc.Name],
+		}
+		if pvc.Spec.StorageClassName != nil {
+			volume.StorageClass = *pvc.Spec.StorageClassName
+		}
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			volume.Capacity = capacity.String()
+		} else if request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			volume.Capacity = request.String()
+		}
+
+		d.Volumes = append(d.Volumes, volume)
+	}
+
+	d.Objects, err = d.countObjects(ctx, kubeClient, metadataClient, log)
+	if err != nil {
+		return fmt.Errorf("count synced objects: %w", err)
+	}
+
+	return nil
+}
+
+func endpoints(ctx context.Context, kubeClient kubernetes.Interface, name, namespace string) ([]Endpoint, error) {
+	result := []Endpoint{}
+	for _, serviceName := range []string{name, translate.GetLoadBalancerSVCName(name)} {
+		service, err := kubeClient.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else if len(service.Spec.Ports) == 0 {
+			continue
+		}
+
+		port := service.Spec.Ports[0]
+		endpoint := Endpoint{
+			Type:    string(service.Spec.Type),
+			Name:    service.Name,
+			Address: service.Spec.ClusterIP + ":" + strconv.Itoa(int(port.Port)),
+		}
+		switch service.Spec.Type {
+		case corev1.ServiceTypeNodePort:
+			endpoint.Address = fmt.Sprintf("%s (node port %d)", endpoint.Address, port.NodePort)
+		case corev1.ServiceTypeLoadBalancer:
+			addresses := []string{}
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				address := ingress.IP
+				if ingress.Hostname != "" {
+					address = ingress.Hostname
+				}
+
+				addresses = append(addresses, address+":"+strconv.Itoa(int(port.Port)))
+			}
+			endpoint.Address = "<pending>"
+			if len(addresses) > 0 {
+				endpoint.Address = strings.Join(addresses, ", ")
+			}
+		}
+
+		result = append(result, endpoint)
+	}
+
+	ingress, err := kubeClient.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) && !kerrors.IsForbidden(err) {
+		return nil, err
+	} else if err == nil {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				result = append(result, Endpoint{Type: "Ingress", Name: ingress.Name, Address: "https://" + rule.Host})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func newPod(pod *corev1.Pod) Pod {
+	ready := 0
+	restarts := int32(0)
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		restarts += status.RestartCount
+	}
+
+	return Pod{
+		Name:      pod.Name,
+		Component: pod.Labels["app"],
+		Status:    find.GetPodStatus(pod),
+		Ready:     fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+		Restarts:  restarts,
+		Node:      pod.Spec.NodeName,
+		Created:   pod.CreationTimestamp.Time,
+	}
+}
+
+// kubernetesVersionFromPods returns the image tag of the api server, if the version is not part of the values
+func kubernetesVersionFromPods(pods []corev1.Pod) string {
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == "vcluster" || container.Name == "kube-apiserver" {
+				return imageTag(container.Image)
+			}
+		}
+	}
+
+	return ""
+}
+
+// summary is the part of the kubelet stats summary that contains the volume usage
+type summary struct {
+	Pods []struct {
+		Volume []struct {
+			UsedBytes *uint64 `json:"usedBytes,omitempty"`
+			PVCRef    *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef,omitempty"`
+		} `json:"volume,omitempty"`
+	} `json:"pods"`
+}
+
+// volumeUsage returns the used bytes of the volumes of the given pods by their claim name. The usage is
+// read from the kubelet through the node proxy, which might not be allowed, so errors are only logged.
+func volumeUsage(ctx context.Context, kubeClient kubernetes.Interface, pods []corev1.Pod, log log.Logger) map[string]string {
+	usage := map[string]string{}
+	nodes := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || nodes[pod.Spec.NodeName] {
+			continue
+		}
+		nodes[pod.Spec.NodeName] = true
+
+		out, err := kubeClient.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes", pod.Spec.NodeName, "proxy", "stats", "summary").DoRaw(ctx)
+		if err != nil {
+			log.Debugf("Error retrieving volume usage from node %s: %v", pod.Spec.NodeName, err)
+			continue
+		}
+
+		stats := &summary{}
+		err = json.Unmarshal(out, stats)
+		if err != nil {
+			log.Debugf("Error parsing stats summary of node %s: %v", pod.Spec.NodeName, err)
+			continue
+		}
+
+		for _, podStats := range stats.Pods {
+			for _, volume := range podStats.Volume {
+				if volume.PVCRef == nil || volume.UsedBytes == nil || volume.PVCRef.Namespace != pod.Namespace {
+					continue
+				}
+
+				usage[volume.PVCRef.Name] = formatBytes(*volume.UsedBytes)
+			}
+		}
+	}
+
+	return usage
+}
+
+// countObjects counts the host objects of the default resources that are managed by the virtual cluster.
+// In multi-namespace mode all objects within the host namespaces of the virtual cluster are counted.
+func (d *Description) countObjects(ctx context.Context, kubeClient kubernetes.Interface, metadataClient metadata.Interface, log log.Logger) ([]ObjectCount, error) {
+	namespaces := []string{d.Namespace}
+	selector := translate.MarkerLabel + "=" + d.Name
+	result := []ObjectCount{}
+	if d.MultiNamespace {
+		namespaceList, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+			LabelSelector: translate.MarkerLabel + "=" + translate.SafeConcatName(d.Namespace, "x", d.Name),
+		})
+		if kerrors.IsForbidden(err) {
+			log.Warnf("Skip counting synced objects, because listing the host namespaces is not allowed: %v", err)
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		namespaces = []string{}
+		for _, namespace := range namespaceList.Items {
+			namespaces = append(namespaces, namespace.Name)
+		}
+		selector = ""
+		result = append(result, ObjectCount{Kind: "Namespace", Count: len(namespaces)})
+	}
+
+	for _, gvk := range garbagecollector.DefaultResources {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		count := 0
+		served := true
+		for _, namespace := range namespaces {
+			list, err := metadataClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) {
+				// the resource is not installed in the host cluster or we are not allowed to list it
+				log.Debugf("Skip counting %s: %v", gvr.Resource, err)
+				served = false
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("list %s: %w", gvr.Resource, err)
+			}
+
+			count += len(list.Items)
+		}
+		if served {
+			result = append(result, ObjectCount{Kind: gvk.Kind, Count: count})
+		}
+	}
+
+	return result, nil
+}
+
+// DistroFromChart returns the distro of the given vcluster chart name
+func DistroFromChart(chartName string) string {
+	if chartName == "vcluster" {
+		return "k3s"
+	}
+
+	distro := strings.TrimPrefix(chartName, "vcluster-")
+	switch distro {
+	case "k0s", "k8s", "eks":
+		return distro
+	}
+
+	return ""
+}
+
+// imageTag returns the tag of the image, which is the kubernetes version for the images of all distros
+func imageTag(image string) string {
+	index := strings.LastIndex(image, ":")
+	if index == -1 || strings.Contains(image[index:], "/") {
+		return ""
+	}
+
+	return image[index+1:]
+}
+
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ci", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func stringValue(values map[string]interface{}, path ...string) string {
+	str, _ := helm.LookupValue(values, path...).(string)
+	return str
+}
@@ -0,0 +1,90 @@
+package describe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNew(t *testing.T) {
+	release := &helm.Release{
+		Version: 3,
+		Info:    &helm.Info{Status: "deployed"},
+		Chart: &helm.Chart{
+			Metadata: &helm.Metadata{Name: "vcluster-k8s", Version: "0.16.0"},
+			Values: map[string]interface{}{
+				"api": map[string]interface{}{"image": "registry.k8s.io/kube-apiserver:v1.26.1"},
+				"sync": map[string]interface{}{
+					"services":  map[string]interface{}{"enabled": true},
+					"ingresses": map[string]interface{}{"enabled": false},
+					"nodes":     map[string]interface{}{"enabled": false, "syncAllNodes": false},
+				},
+			},
+		},
+		Config: map[string]interface{}{
+			"api": map[string]interface{}{"image": "registry.k8s.io/kube-apiserver:v1.27.3"},
+			"sync": map[string]interface{}{
+				"ingresses": map[string]interface{}{"enabled": true},
+			},
+			"plugin": map[string]interface{}{
+				"hooks": map[string]interface{}{"image": "my-registry:5000/hooks"},
+			},
+		},
+	}
+
+	description := New(&find.VCluster{Name: "test", Namespace: "test"}, release)
+	assert.Equal(t, description.Chart.Name, "vcluster-k8s")
+	assert.Equal(t, description.Chart.Revision, 3)
+	assert.Equal(t, description.Distro, "k8s")
+	assert.Equal(t, description.KubernetesVersion, "v1.27.3")
+	assert.DeepEqual(t, description.Syncers, []string{"ingresses", "services"})
+	assert.DeepEqual(t, description.Plugins, []string{"hooks"})
+
+	// the chart values are not modified by the release values
+	assert.Equal(t, release.Chart.Values["api"].(map[string]interface{})["image"], "registry.k8s.io/kube-apiserver:v1.26.1")
+
+	// without a release only the vcluster itself is described
+	description = New(&find.VCluster{Name: "test", Namespace: "test"}, nil)
+	assert.Equal(t, description.Distro, "")
+	assert.Equal(t, len(description.Syncers), 0)
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, imageTag("rancher/k3s:v1.27.3-k3s1"), "v1.27.3-k3s1")
+	assert.Equal(t, imageTag("my-registry:5000/k3s"), "")
+	assert.Equal(t, imageTag("k3s"), "")
+}
+
+func TestEndpoints(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeNodePort,
+				ClusterIP: "10.96.0.10",
+				Ports:     []corev1.ServicePort{{Port: 443, NodePort: 31443}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-lb", Namespace: "test"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeLoadBalancer,
+				ClusterIP: "10.96.0.11",
+				Ports:     []corev1.ServicePort{{Port: 443}},
+			},
+		},
+	)
+
+	result, err := endpoints(context.Background(), kubeClient, "test", "test")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, []Endpoint{
+		{Type: "NodePort", Name: "test", Address: "10.96.0.10:443 (node port 31443)"},
+		{Type: "LoadBalancer", Name: "test-lb", Address: "<pending>"},
+	})
+}
@@ -0,0 +1,410 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/localkubernetes"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/util/servicecidr"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+type Severity string
+
+const (
+	SeverityPass    Severity = "Pass"
+	SeverityWarning Severity = "Warning"
+	SeverityError   Severity = "Error"
+)
+
+const (
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// Result is the result of a single preflight check
+type Result struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+
+	// Fix describes how to fix a failed check
+	Fix string `json:"fix,omitempty"`
+}
+
+// Options describe the virtual cluster that should be deployed
+type Options struct {
+	Namespace string
+	Distro    string
+
+	// Values are the chart values the virtual cluster will be deployed with
+	Values map[string]interface{}
+
+	// CIDR is the configured service cidr, if empty the service cidr is detected
+	CIDR string
+
+	// ClusterType is the detected type of the host cluster and ExposeLocal is true if the virtual
+	// cluster should be exposed through the local kubernetes distro
+	ClusterType localkubernetes.ClusterType
+	ExposeLocal bool
+}
+
+// Run runs all preflight checks against the host cluster
+func Run(ctx context.Context, kubeClient kubernetes.Interface, options *Options) []Result {
+	_, err := kubeClient.Discovery().ServerVersion()
+	if err != nil {
+		return []Result{{
+			Check:    "api-server",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("The host cluster is not reachable: %v", err),
+			Fix:      "Make sure the kube context is correct and `kubectl get namespaces` is working",
+		}}
+	}
+
+	results := []Result{{Check: "api-server", Severity: SeverityPass, Message: "The host cluster is reachable"}}
+	syncers := EnabledSyncers(options.Values)
+	results = append(results, checkPermissions(ctx, kubeClient, options, syncers)...)
+	results = append(results, checkAPIs(kubeClient, syncers)...)
+	results = append(results, checkStorage(ctx, kubeClient, options))
+	results = append(results, checkPodSecurity(ctx, kubeClient, options.Namespace))
+	results = append(results, checkServiceCIDR(ctx, kubeClient, options))
+	if options.ExposeLocal && options.ClusterType.LocalKubernetes() {
+		results = append(results, checkLocalDistro(options.ClusterType))
+	}
+
+	return results
+}
+
+// HasErrors returns true if one of the results is an error
+func HasErrors(results []Result) bool {
+	for _, result := range results {
+		if result.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkPermissions(ctx context.Context, kubeClient kubernetes.Interface, options *Options, syncers []string) []Result {
+	results := []Result{}
+	check := func(name string, permissions []Permission, fix string) {
+		missing, err := missingPermissions(ctx, kubeClient, options.Namespace, permissions)
+		if err != nil {
+			results = append(results, Result{Check: name, Severity: SeverityWarning, Message: fmt.Sprintf("Couldn't check permissions: %v", err)})
+		} else if len(missing) > 0 {
+			results = append(results, Result{
+				Check:    name,
+				Severity: SeverityError,
+				Message:  "Missing host permissions: " + strings.Join(missing, ", "),
+				Fix:      fix,
+			})
+		}
+	}
+
+	namespacePermissions := append([]Permission{}, basePermissions...)
+	_, err := kubeClient.CoreV1().Namespaces().Get(ctx, options.Namespace, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		namespacePermissions = append(namespacePermissions, Permission{Resource: "namespaces", Verbs: []string{"create"}, Cluster: true})
+	}
+	if isolated, _ := helm.LookupValue(options.Values, "isolation", "enabled").(bool); isolated {
+		namespacePermissions = append(namespacePermissions, isolationPermissions...)
+	}
+	adminFix := fmt.Sprintf("Ask a cluster admin to grant you these permissions in namespace %s", options.Namespace)
+	check("permissions", namespacePermissions, adminFix)
+
+	needsClusterRole := false
+	for _, syncer := range syncers {
+		for _, permission := range syncerPermissions[syncer] {
+			needsClusterRole = needsClusterRole || permission.Cluster
+		}
+	}
+	if needsClusterRole {
+		check("permissions", clusterRolePermissions, "Ask a cluster admin to grant you these permissions, the enabled syncers need a cluster role")
+	}
+
+	for _, syncer := range syncers {
+		if len(syncerPermissions[syncer]) == 0 {
+			continue
+		}
+
+		check("permissions/"+syncer, syncerPermissions[syncer], fmt.Sprintf("Ask a cluster admin to grant you these permissions or disable the syncer via the helm value sync.%s.enabled=false", syncer))
+	}
+
+	if len(results) == 0 {
+		results = append(results, Result{Check: "permissions", Severity: SeverityPass, Message: fmt.Sprintf("All permissions for the syncers %s are granted", strings.Join(syncers, ", "))})
+	}
+
+	return results
+}
+
+// missingPermissions checks the permissions via self subject access reviews and returns the missing ones
+func missingPermissions(ctx context.Context, kubeClient kubernetes.Interface, namespace string, permissions []Permission) ([]string, error) {
+	missing := []string{}
+	for _, permission := range permissions {
+		resource := permission.Resource
+		if permission.Subresource != "" {
+			resource += "/" + permission.Subresource
+		}
+		if permission.Group != "" {
+			resource += "." + permission.Group
+		}
+
+		deniedVerbs := []string{}
+		for _, verb := range permission.Verbs {
+			attributes := &authorizationv1.ResourceAttributes{
+				Verb:        verb,
+				Group:       permission.Group,
+				Resource:    permission.Resource,
+				Subresource: permission.Subresource,
+			}
+			if !permission.Cluster {
+				attributes.Namespace = namespace
+			}
+
+			review, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return nil, err
+			} else if !review.Status.Allowed {
+				deniedVerbs = append(deniedVerbs, verb)
+			}
+		}
+		if len(deniedVerbs) > 0 {
+			missing = append(missing, fmt.Sprintf("%s %s", strings.Join(deniedVerbs, ","), resource))
+		}
+	}
+
+	return missing, nil
+}
+
+// syncerAPIs are the group versions the syncers need within the host cluster
+var syncerAPIs = map[string]string{
+	"ingresses":            "networking.k8s.io/v1",
+	"ingressclasses":       "networking.k8s.io/v1",
+	"networkpolicies":      "networking.k8s.io/v1",
+	"poddisruptionbudgets": "policy/v1",
+	"priorityclasses":      "scheduling.k8s.io/v1",
+	"runtimeclasses":       "node.k8s.io/v1",
+	"storageclasses":       "storage.k8s.io/v1",
+	"hoststorageclasses":   "storage.k8s.io/v1",
+	"csinodes":             "storage.k8s.io/v1",
+	"csidrivers":           "storage.k8s.io/v1",
+	"csistoragecapacities": "storage.k8s.io/v1",
+	"volumesnapshots":      "snapshot.storage.k8s.io/v1",
+	"volumegroupsnapshots": "groupsnapshot.storage.k8s.io/v1alpha1",
+	"resourceclaims":       "resource.k8s.io/v1alpha2",
+}
+
+// disabledIfMissing are the syncers the syncer disables itself if the host cluster doesn't serve their api
+var disabledIfMissing = map[string]bool{
+	"csinodes":             true,
+	"csidrivers":           true,
+	"csistoragecapacities": true,
+	"volumegroupsnapshots": true,
+	"resourceclaims":       true,
+}
+
+func checkAPIs(kubeClient kubernetes.Interface, syncers []string) []Result {
+	results := []Result{}
+	for _, syncer := range syncers {
+		groupVersion, ok := syncerAPIs[syncer]
+		if !ok {
+			continue
+		}
+
+		resource := strings.TrimPrefix(syncer, "host")
+		resources, err := kubeClient.Discovery().ServerResourcesForGroupVersion(groupVersion)
+		if err != nil && !kerrors.IsNotFound(err) {
+			results = append(results, Result{Check: "api/" + syncer, Severity: SeverityWarning, Message: fmt.Sprintf("Couldn't discover %s: %v", groupVersion, err)})
+			continue
+		}
+
+		found := false
+		if resources != nil {
+			for _, apiResource := range resources.APIResources {
+				found = found || apiResource.Name == resource
+			}
+		}
+		if found {
+			continue
+		}
+
+		if disabledIfMissing[syncer] {
+			results = append(results, Result{
+				Check:    "api/" + syncer,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("The host cluster doesn't serve %s in %s, the syncer will be disabled", resource, groupVersion),
+			})
+		} else {
+			results = append(results, Result{
+				Check:    "api/" + syncer,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("The host cluster doesn't serve %s in %s", resource, groupVersion),
+				Fix:      fmt.Sprintf("Install the %s api in the host cluster or disable the syncer via the helm value sync.%s.enabled=false", groupVersion, syncer),
+			})
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, Result{Check: "api", Severity: SeverityPass, Message: "All apis of the enabled syncers are available"})
+	}
+
+	return results
+}
+
+func checkStorage(ctx context.Context, kubeClient kubernetes.Interface, options *Options) Result {
+	storageValues := "storage"
+	if options.Distro == "k8s" || options.Distro == "eks" {
+		storageValues = "etcd.storage"
+	}
+	path := strings.Split(storageValues, ".")
+	if persistence, ok := helm.LookupValue(options.Values, append(path, "persistence")...).(bool); ok && !persistence {
+		return Result{Check: "storage", Severity: SeverityPass, Message: "Persistence is disabled, the control plane will use an emptyDir"}
+	}
+
+	storageClasses, err := kubeClient.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Result{Check: "storage", Severity: SeverityWarning, Message: fmt.Sprintf("Couldn't list storage classes: %v", err)}
+	}
+
+	names := []string{}
+	defaultClass := ""
+	for _, storageClass := range storageClasses.Items {
+		names = append(names, storageClass.Name)
+		if storageClass.Annotations[defaultStorageClassAnnotation] == "true" || storageClass.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+			defaultClass = storageClass.Name
+		}
+	}
+	sort.Strings(names)
+
+	className, _ := helm.LookupValue(options.Values, append(path, "className")...).(string)
+	if className != "" {
+		for _, name := range names {
+			if name == className {
+				return Result{Check: "storage", Severity: SeverityPass, Message: fmt.Sprintf("Storage class %s exists", className)}
+			}
+		}
+
+		return Result{
+			Check:    "storage",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("Storage class %s doesn't exist", className),
+			Fix:      fmt.Sprintf("Set the helm value %s.className to one of: %s", storageValues, strings.Join(names, ", ")),
+		}
+	} else if defaultClass != "" {
+		return Result{Check: "storage", Severity: SeverityPass, Message: fmt.Sprintf("Default storage class %s exists", defaultClass)}
+	}
+
+	fix := fmt.Sprintf("Mark a storage class as default, or set the helm value %s.persistence=false to use an emptyDir", storageValues)
+	if len(names) > 0 {
+		fix = fmt.Sprintf("Set the helm value %s.className to one of: %s, mark one of them as default, or set %s.persistence=false to use an emptyDir", storageValues, strings.Join(names, ", "), storageValues)
+	}
+	return Result{
+		Check:    "storage",
+		Severity: SeverityError,
+		Message:  "There is no default storage class, so the persistent volume claim of the control plane would stay pending",
+		Fix:      fix,
+	}
+}
+
+func checkPodSecurity(ctx context.Context, kubeClient kubernetes.Interface, namespace string) Result {
+	ns, err := kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return Result{Check: "pod-security", Severity: SeverityPass, Message: fmt.Sprintf("Namespace %s doesn't exist yet and will be created", namespace)}
+	} else if err != nil {
+		return Result{Check: "pod-security", Severity: SeverityWarning, Message: fmt.Sprintf("Couldn't get namespace %s: %v", namespace, err)}
+	}
+
+	level := ns.Labels[podSecurityEnforceLabel]
+	if level == "restricted" {
+		return Result{
+			Check:    "pod-security",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("Namespace %s enforces the restricted pod security standard, which would reject the control plane pods", namespace),
+			Fix:      fmt.Sprintf("kubectl label namespace %s %s=baseline --overwrite", namespace, podSecurityEnforceLabel),
+		}
+	} else if level == "" {
+		level = "no"
+	}
+
+	return Result{Check: "pod-security", Severity: SeverityPass, Message: fmt.Sprintf("Namespace %s enforces %s pod security standard", namespace, level)}
+}
+
+func checkServiceCIDR(ctx context.Context, kubeClient kubernetes.Interface, options *Options) Result {
+	if options.CIDR != "" {
+		return Result{Check: "service-cidr", Severity: SeverityPass, Message: fmt.Sprintf("Using the configured service CIDR %s", options.CIDR)}
+	}
+
+	// the detection creates services with invalid ips, so it needs an existing namespace
+	namespace := options.Namespace
+	_, err := kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		namespace = metav1.NamespaceDefault
+	}
+
+	cidr, warning := servicecidr.GetServiceCIDR(ctx, kubeClient, namespace)
+	if warning != "" {
+		return Result{
+			Check:    "service-cidr",
+			Severity: SeverityWarning,
+			Message:  warning,
+			Fix:      "Pass the service CIDR of the host cluster via --cidr",
+		}
+	}
+
+	return Result{Check: "service-cidr", Severity: SeverityPass, Message: fmt.Sprintf("Detected service CIDR %s", cidr)}
+}
+
+func checkLocalDistro(clusterType localkubernetes.ClusterType) Result {
+	switch clusterType {
+	case localkubernetes.ClusterTypeKIND, localkubernetes.ClusterTypeK3D, localkubernetes.ClusterTypeMinikube:
+		if !localkubernetes.IsDockerInstalledAndUpAndRunning() {
+			return Result{
+				Check:    "local-distro",
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("Detected local kubernetes cluster %s, but docker is not reachable, so vcluster connect will fall back to port-forwarding", clusterType),
+				Fix:      "Start docker or pass --expose-local=false",
+			}
+		}
+	}
+
+	return Result{Check: "local-distro", Severity: SeverityPass, Message: fmt.Sprintf("Detected local kubernetes cluster %s", clusterType)}
+}
+
+// LoadValues merges the default values with the given values files the same way helm does
+func LoadValues(defaultValues string, files []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(defaultValues), &values)
+	if err != nil {
+		return nil, fmt.Errorf("parse default values: %w", err)
+	}
+
+	for _, file := range files {
+		out, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		fileValues := map[string]interface{}{}
+		err = yaml.Unmarshal(out, &fileValues)
+		if err != nil {
+			return nil, fmt.Errorf("parse values file %s: %w", file, err)
+		}
+
+		helm.MergeValues(values, fileValues)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,93 @@
+package doctor
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestEnabledSyncers(t *testing.T) {
+	syncers := EnabledSyncers(map[string]interface{}{
+		"sync": map[string]interface{}{
+			"ingresses": map[string]interface{}{"enabled": true},
+			"events":    map[string]interface{}{"enabled": false},
+			"nodes":     map[string]interface{}{"enableScheduler": true},
+		},
+		"multiNamespaceMode": map[string]interface{}{"enabled": true},
+	})
+
+	assert.DeepEqual(t, syncers, []string{
+		"configmaps",
+		"csidrivers",
+		"csinodes",
+		"csistoragecapacities",
+		"endpoints",
+		"fake-nodes",
+		"fake-persistentvolumes",
+		"ingressclasses",
+		"ingresses",
+		"namespaces",
+		"persistentvolumeclaims",
+		"pods",
+		"secrets",
+		"services",
+	})
+}
+
+func TestRun(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test",
+			Labels: map[string]string{podSecurityEnforceLabel: "restricted"},
+		},
+	}
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "standard",
+			Annotations: map[string]string{defaultStorageClassAnnotation: "true"},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(namespace, storageClass)
+
+	// only ingresses are forbidden
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource != "ingresses"
+		return true, review, nil
+	})
+
+	results := Run(context.Background(), kubeClient, &Options{
+		Namespace: "test",
+		Distro:    "k3s",
+		CIDR:      "10.96.0.0/12",
+		Values: map[string]interface{}{
+			"sync": map[string]interface{}{
+				"ingresses": map[string]interface{}{"enabled": true},
+			},
+		},
+	})
+
+	severities := map[string]Severity{}
+	for _, result := range results {
+		severities[result.Check] = result.Severity
+	}
+	assert.Equal(t, severities["api-server"], SeverityPass)
+	assert.Equal(t, severities["permissions/ingresses"], SeverityError)
+	assert.Equal(t, severities["api/ingresses"], SeverityError)
+	assert.Equal(t, severities["storage"], SeverityPass)
+	assert.Equal(t, severities["pod-security"], SeverityError)
+	assert.Equal(t, severities["service-cidr"], SeverityPass)
+	assert.Assert(t, HasErrors(results))
+
+	// the base permissions are granted
+	_, ok := severities["permissions"]
+	assert.Assert(t, !ok)
+}
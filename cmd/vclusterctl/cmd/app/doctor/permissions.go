@@ -0,0 +1,182 @@
+package doctor
+
+import (
+	"sort"
+
+	"github.com/loft-sh/vcluster/pkg/helm"
+)
+
+var (
+	allVerbs  = []string{"create", "delete", "patch", "update", "get", "list", "watch"}
+	readVerbs = []string{"get", "list", "watch"}
+)
+
+// Permission is a permission within the host cluster
+type Permission struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verbs       []string
+
+	// Cluster is true if the permission is needed cluster wide instead of within the vcluster namespace
+	Cluster bool
+}
+
+// basePermissions are needed to install the chart and are granted to the syncer by the role of the chart,
+// which requires the user to hold them as well
+var basePermissions = []Permission{
+	{Resource: "configmaps", Verbs: allVerbs},
+	{Resource: "secrets", Verbs: allVerbs},
+	{Resource: "services", Verbs: allVerbs},
+	{Resource: "pods", Verbs: allVerbs},
+	{Resource: "persistentvolumeclaims", Verbs: allVerbs},
+	{Resource: "pods", Subresource: "exec", Verbs: []string{"create"}},
+	{Resource: "pods", Subresource: "portforward", Verbs: []string{"create"}},
+	{Resource: "pods", Subresource: "log", Verbs: readVerbs},
+	{Resource: "endpoints", Verbs: readVerbs},
+	{Resource: "events", Verbs: readVerbs},
+	{Resource: "serviceaccounts", Verbs: []string{"create", "get"}},
+	{Group: "apps", Resource: "statefulsets", Verbs: allVerbs},
+	{Group: "apps", Resource: "deployments", Verbs: readVerbs},
+	{Group: "apps", Resource: "replicasets", Verbs: readVerbs},
+	{Group: "rbac.authorization.k8s.io", Resource: "roles", Verbs: []string{"create", "get"}},
+	{Group: "rbac.authorization.k8s.io", Resource: "rolebindings", Verbs: []string{"create", "get"}},
+}
+
+// isolationPermissions are needed to create the isolation objects of the chart
+var isolationPermissions = []Permission{
+	{Resource: "resourcequotas", Verbs: []string{"create", "get"}},
+	{Resource: "limitranges", Verbs: []string{"create", "get"}},
+	{Group: "networking.k8s.io", Resource: "networkpolicies", Verbs: []string{"create", "get"}},
+}
+
+// clusterRolePermissions are needed if one of the enabled syncers requires a cluster role
+var clusterRolePermissions = []Permission{
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Verbs: []string{"create", "get"}, Cluster: true},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings", Verbs: []string{"create", "get"}, Cluster: true},
+}
+
+// syncerPermissions are the host permissions of the syncers, see charts/*/templates/rbac
+var syncerPermissions = map[string][]Permission{
+	"endpoints": {
+		{Resource: "endpoints", Verbs: []string{"create", "delete", "patch", "update"}},
+	},
+	"ingresses": {
+		{Group: "networking.k8s.io", Resource: "ingresses", Verbs: allVerbs},
+	},
+	"networkpolicies": {
+		{Group: "networking.k8s.io", Resource: "networkpolicies", Verbs: allVerbs},
+	},
+	"volumesnapshots": {
+		{Group: "snapshot.storage.k8s.io", Resource: "volumesnapshots", Verbs: allVerbs},
+		{Group: "snapshot.storage.k8s.io", Resource: "volumesnapshotclasses", Verbs: readVerbs, Cluster: true},
+		{Group: "snapshot.storage.k8s.io", Resource: "volumesnapshotcontents", Verbs: allVerbs, Cluster: true},
+	},
+	"volumegroupsnapshots": {
+		{Group: "groupsnapshot.storage.k8s.io", Resource: "volumegroupsnapshots", Verbs: allVerbs},
+		{Group: "groupsnapshot.storage.k8s.io", Resource: "volumegroupsnapshotclasses", Verbs: readVerbs, Cluster: true},
+		{Group: "groupsnapshot.storage.k8s.io", Resource: "volumegroupsnapshotcontents", Verbs: allVerbs, Cluster: true},
+	},
+	"serviceaccounts": {
+		{Resource: "serviceaccounts", Verbs: allVerbs},
+	},
+	"poddisruptionbudgets": {
+		{Group: "policy", Resource: "poddisruptionbudgets", Verbs: allVerbs},
+	},
+	"resourceclaims": {
+		{Group: "resource.k8s.io", Resource: "resourceclaims", Verbs: allVerbs},
+		{Group: "resource.k8s.io", Resource: "resourceclaimtemplates", Verbs: allVerbs},
+	},
+	"nodes": {
+		{Resource: "nodes", Verbs: readVerbs, Cluster: true},
+		{Resource: "nodes", Subresource: "status", Verbs: readVerbs, Cluster: true},
+		{Resource: "nodes", Subresource: "proxy", Verbs: []string{"get"}, Cluster: true},
+		{Resource: "pods", Verbs: readVerbs, Cluster: true},
+	},
+	"persistentvolumes": {
+		{Resource: "persistentvolumes", Verbs: allVerbs, Cluster: true},
+	},
+	"storageclasses": {
+		{Group: "storage.k8s.io", Resource: "storageclasses", Verbs: allVerbs, Cluster: true},
+	},
+	"hoststorageclasses": {
+		{Group: "storage.k8s.io", Resource: "storageclasses", Verbs: readVerbs, Cluster: true},
+	},
+	"priorityclasses": {
+		{Group: "scheduling.k8s.io", Resource: "priorityclasses", Verbs: allVerbs, Cluster: true},
+	},
+	"runtimeclasses": {
+		{Group: "node.k8s.io", Resource: "runtimeclasses", Verbs: readVerbs, Cluster: true},
+	},
+	"ingressclasses": {
+		{Group: "networking.k8s.io", Resource: "ingressclasses", Verbs: readVerbs, Cluster: true},
+	},
+	"csinodes": {
+		{Group: "storage.k8s.io", Resource: "csinodes", Verbs: readVerbs, Cluster: true},
+	},
+	"csidrivers": {
+		{Group: "storage.k8s.io", Resource: "csidrivers", Verbs: readVerbs, Cluster: true},
+	},
+	"csistoragecapacities": {
+		{Group: "storage.k8s.io", Resource: "csistoragecapacities", Verbs: readVerbs, Cluster: true},
+	},
+	"namespaces": {
+		{Resource: "namespaces", Verbs: allVerbs, Cluster: true},
+		{Resource: "serviceaccounts", Verbs: allVerbs, Cluster: true},
+	},
+}
+
+// defaultSyncers are the syncers the charts enable by default
+var defaultSyncers = []string{
+	"services",
+	"configmaps",
+	"secrets",
+	"endpoints",
+	"pods",
+	"events",
+	"persistentvolumeclaims",
+	"fake-nodes",
+	"fake-persistentvolumes",
+}
+
+// EnabledSyncers returns the syncers that are enabled by the given chart values
+func EnabledSyncers(values map[string]interface{}) []string {
+	enabled := map[string]bool{}
+	for _, name := range defaultSyncers {
+		enabled[name] = true
+	}
+
+	syncValues, _ := values["sync"].(map[string]interface{})
+	explicit := map[string]bool{}
+	for name := range syncValues {
+		if value, ok := helm.LookupValue(syncValues, name, "enabled").(bool); ok {
+			enabled[name] = value
+			explicit[name] = true
+		}
+	}
+
+	// these are enabled by the syncer if not explicitly disabled
+	if enabled["ingresses"] && !explicit["ingressclasses"] {
+		enabled["ingressclasses"] = true
+	}
+	if enabled["volumegroupsnapshots"] && !explicit["volumesnapshots"] {
+		enabled["volumesnapshots"] = true
+	}
+	if multiNamespace, _ := helm.LookupValue(values, "multiNamespaceMode", "enabled").(bool); multiNamespace {
+		enabled["namespaces"] = true
+	}
+	if enableScheduler, _ := helm.LookupValue(syncValues, "nodes", "enableScheduler").(bool); enableScheduler && enabled["persistentvolumeclaims"] {
+		enabled["csinodes"] = true
+		enabled["csidrivers"] = true
+		enabled["csistoragecapacities"] = true
+	}
+
+	syncers := []string{}
+	for name, isEnabled := range enabled {
+		if isEnabled {
+			syncers = append(syncers, name)
+		}
+	}
+	sort.Strings(syncers)
+	return syncers
+}
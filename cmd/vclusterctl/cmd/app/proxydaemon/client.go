@@ -0,0 +1,136 @@
+package proxydaemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Client talks to the daemon through its unix socket
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the daemon listening on the socket path
+func NewClient(socketPath string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					dialer := &net.Dialer{}
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// List returns all port-forwardings of the daemon
+func (c *Client) List(ctx context.Context) ([]Status, error) {
+	statuses := []Status{}
+	err := c.do(ctx, http.MethodGet, "/proxies", nil, &statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// Start starts or restarts the port-forwarding of the spec
+func (c *Client) Start(ctx context.Context, spec *Spec) (*Status, error) {
+	status := &Status{}
+	err := c.do(ctx, http.MethodPost, "/proxies", spec, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// Stop stops the matching port-forwardings and returns them. Empty values match everything.
+func (c *Client) Stop(ctx context.Context, name, namespace, kubeContext string) ([]Status, error) {
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("namespace", namespace)
+	query.Set("context", kubeContext)
+
+	statuses := []Status{}
+	err := c.do(ctx, http.MethodDelete, "/proxies?"+query.Encode(), nil, &statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// WaitForConnected waits until the port-forwarding of the spec is connected
+func (c *Client) WaitForConnected(ctx context.Context, spec *Spec, timeout time.Duration) error {
+	lastError := ""
+	err := wait.PollUntilContextTimeout(ctx, time.Millisecond*200, timeout, true, func(ctx context.Context) (bool, error) {
+		statuses, err := c.List(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		for _, status := range statuses {
+			if status.Matches(spec.Name, spec.Namespace, spec.Context) {
+				lastError = status.LastError
+				return status.Phase == PhaseConnected, nil
+			}
+		}
+
+		return false, fmt.Errorf("port-forwarding was stopped")
+	})
+	if err != nil {
+		if lastError != "" {
+			return fmt.Errorf("%v: %s", err, lastError)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(raw)
+	}
+
+	// the host is ignored as all requests go to the unix socket
+	req, err := http.NewRequestWithContext(ctx, method, "http://vcluster-proxy-daemon"+path, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "reach proxy daemon")
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy daemon: %s", strings.TrimSpace(string(raw)))
+	}
+
+	return json.Unmarshal(raw, out)
+}
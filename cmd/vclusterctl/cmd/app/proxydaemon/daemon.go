@@ -0,0 +1,132 @@
+package proxydaemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/util/cliconfig"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	socketFileName = "proxy-daemon.sock"
+	logFileName    = "proxy-daemon.log"
+)
+
+// SocketPath returns the path of the unix socket of the daemon of the current user
+func SocketPath() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Wrap(err, "detect home directory")
+	}
+
+	return filepath.Join(home, cliconfig.VclusterFolder, socketFileName), nil
+}
+
+// LogPath returns the path of the log file of the daemon of the current user
+func LogPath() (string, error) {
+	socketPath, err := SocketPath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(socketPath), logFileName), nil
+}
+
+// Listen creates the unix socket of the daemon. A socket that is left over from a daemon
+// that didn't shut down cleanly is replaced.
+func Listen(socketPath string) (net.Listener, error) {
+	err := os.MkdirAll(filepath.Dir(socketPath), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(socketPath); err == nil {
+		conn, err := net.DialTimeout("unix", socketPath, time.Second)
+		if err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("proxy daemon is already running at %s", socketPath)
+		}
+
+		err = os.Remove(socketPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "remove stale socket")
+		}
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the current user is allowed to control the daemon
+	err = os.Chmod(socketPath, 0600)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// EnsureRunning returns a client for the daemon of the current user and starts
+// the daemon in the background if it is not running yet
+func EnsureRunning(ctx context.Context, log log.Logger) (*Client, error) {
+	socketPath, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewClient(socketPath)
+	if _, err := client.List(ctx); err == nil {
+		return client, nil
+	}
+
+	logPath, err := LogPath()
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(logPath), 0755)
+	if err != nil {
+		return nil, err
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "open proxy daemon log")
+	}
+	defer logFile.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Starting vcluster proxy daemon, logs are written to %s", logPath)
+	daemonCmd := exec.Command(executable, "proxies", "daemon")
+	daemonCmd.Stdout = logFile
+	daemonCmd.Stderr = logFile
+	detach(daemonCmd)
+	err = daemonCmd.Start()
+	if err != nil {
+		return nil, errors.Wrap(err, "start proxy daemon")
+	}
+	_ = daemonCmd.Process.Release()
+
+	err = wait.PollUntilContextTimeout(ctx, time.Millisecond*100, time.Second*10, true, func(ctx context.Context) (bool, error) {
+		_, err := client.List(ctx)
+		return err == nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("wait for proxy daemon to start, check %s for errors: %v", logPath, err)
+	}
+
+	return client, nil
+}
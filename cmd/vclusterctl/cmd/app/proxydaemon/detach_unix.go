@@ -0,0 +1,13 @@
+//go:build !windows
+
+package proxydaemon
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the daemon in its own session, so it outlives the terminal that started it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
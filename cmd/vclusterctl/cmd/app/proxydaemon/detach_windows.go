@@ -0,0 +1,13 @@
+//go:build windows
+
+package proxydaemon
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the daemon in its own process group, so it doesn't receive the CTRL+C of the terminal
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,84 @@
+package proxydaemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/util/portforward"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// PortForwarder forwards to the vcluster pod through the kubernetes port-forwarding api
+type PortForwarder struct {
+	Log log.Logger
+}
+
+func (f *PortForwarder) Forward(ctx context.Context, spec *Spec, connected func(pod string)) error {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(spec.KubeConfig)
+	if err != nil {
+		return errors.Wrap(err, "load host kube config")
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "create host kube client")
+	}
+
+	// the pod is looked up again on every connect, as a restarted vcluster pod might have a new name
+	pod := spec.Pod
+	if pod == "" {
+		pod, err = findReadyPod(ctx, kubeClient, spec.Name, spec.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	stopChan, err := portforward.StartPortForwarding(restConfig, kubeClient, spec.Address, pod, spec.Namespace, strconv.Itoa(spec.LocalPort), strconv.Itoa(spec.RemotePort), io.Discard, io.Discard, f.Log)
+	if err != nil {
+		return errors.Wrapf(err, "port-forward to pod %s", pod)
+	}
+	connected(pod)
+
+	select {
+	case <-ctx.Done():
+		close(stopChan)
+		return nil
+	case <-stopChan:
+		return fmt.Errorf("lost connection to pod %s", pod)
+	}
+}
+
+// findReadyPod returns the newest ready pod of the vcluster
+func findReadyPod(ctx context.Context, kubeClient kubernetes.Interface, name, namespace string) (string, error) {
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=vcluster,release=" + name,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "list vcluster pods")
+	}
+
+	// sort by newest
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Unix() > pods.Items[j].CreationTimestamp.Unix()
+	})
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return pod.Name, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("can't find a ready vcluster pod in namespace %s", namespace)
+}
@@ -0,0 +1,261 @@
+package proxydaemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+)
+
+// Forwarder forwards the local port of the spec to the virtual cluster
+type Forwarder interface {
+	// Forward blocks until ctx is canceled or the connection is lost. Connected is called
+	// with the name of the pod as soon as the port-forwarding is ready.
+	Forward(ctx context.Context, spec *Spec, connected func(pod string)) error
+}
+
+// Server maintains the port-forwardings and serves the api of the daemon
+type Server struct {
+	Forwarder Forwarder
+	Log       log.Logger
+
+	// RetryInterval is the time to wait before a lost port-forwarding is reestablished
+	RetryInterval time.Duration
+
+	m       sync.Mutex
+	proxies map[string]*proxy
+	idle    chan struct{}
+}
+
+type proxy struct {
+	spec   *Spec
+	status Status
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Serve serves the api on the listener until ctx is canceled or the last port-forwarding is stopped
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	s.m.Lock()
+	s.proxies = map[string]*proxy{}
+	s.idle = make(chan struct{})
+	s.m.Unlock()
+
+	server := &http.Server{Handler: s.Handler()}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	case <-s.idle:
+		s.Log.Info("No port-forwardings left, stopping daemon")
+	}
+
+	s.stop("", "", "")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// Handler returns the http handler of the daemon api
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/proxies", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, s.list())
+		case http.MethodPost:
+			spec := &Spec{}
+			err := json.NewDecoder(r.Body).Decode(spec)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("decode spec: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			status, err := s.start(spec)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
+			writeJSON(w, status)
+		case http.MethodDelete:
+			query := r.URL.Query()
+			writeJSON(w, s.stop(query.Get("name"), query.Get("namespace"), query.Get("context")))
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	return mux
+}
+
+func (s *Server) list() []Status {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	statuses := []Status{}
+	for _, p := range s.proxies {
+		statuses = append(statuses, p.status)
+	}
+	sortStatuses(statuses)
+	return statuses
+}
+
+func (s *Server) start(spec *Spec) (*Status, error) {
+	if spec.Name == "" || spec.Namespace == "" || spec.LocalPort == 0 || spec.RemotePort == 0 {
+		return nil, fmt.Errorf("name, namespace, local port and remote port are required")
+	}
+
+	// a restarted port-forwarding replaces the existing one
+	key := spec.Key()
+	s.m.Lock()
+	if s.stopping() {
+		s.m.Unlock()
+		return nil, fmt.Errorf("daemon is stopping")
+	}
+	for otherKey, other := range s.proxies {
+		if otherKey != key && other.spec.LocalPort == spec.LocalPort && other.spec.Address == spec.Address {
+			s.m.Unlock()
+			return nil, fmt.Errorf("local port %d is already used by vcluster %s in namespace %s", spec.LocalPort, other.spec.Name, other.spec.Namespace)
+		}
+	}
+	existing := s.proxies[key]
+	delete(s.proxies, key)
+	s.m.Unlock()
+	if existing != nil {
+		existing.cancel()
+		<-existing.done
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &proxy{
+		spec: spec,
+		status: Status{
+			Name:      spec.Name,
+			Namespace: spec.Namespace,
+			Context:   spec.Context,
+			Address:   spec.Address,
+			LocalPort: spec.LocalPort,
+			Phase:     PhaseConnecting,
+			Pod:       spec.Pod,
+			Since:     time.Now(),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	s.m.Lock()
+	if concurrent := s.proxies[key]; concurrent != nil {
+		concurrent.cancel()
+	}
+	s.proxies[key] = p
+	status := p.status
+	s.m.Unlock()
+
+	s.Log.Infof("Start port-forwarding for vcluster %s in namespace %s on port %d", spec.Name, spec.Namespace, spec.LocalPort)
+	go s.run(ctx, p)
+	return &status, nil
+}
+
+// run keeps the port-forwarding alive until it is stopped
+func (s *Server) run(ctx context.Context, p *proxy) {
+	defer close(p.done)
+
+	for {
+		err := s.Forwarder.Forward(ctx, p.spec, func(pod string) {
+			s.m.Lock()
+			defer s.m.Unlock()
+
+			p.status.Phase = PhaseConnected
+			p.status.Pod = pod
+			p.status.Since = time.Now()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("port-forwarding stopped")
+		}
+
+		s.m.Lock()
+		if p.status.Phase == PhaseConnected {
+			p.status.Reconnects++
+			p.status.Phase = PhaseReconnecting
+			p.status.Since = time.Now()
+		}
+		p.status.LastError = err.Error()
+		s.m.Unlock()
+		s.Log.Warnf("Port-forwarding for vcluster %s in namespace %s: %v", p.spec.Name, p.spec.Namespace, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.RetryInterval):
+		}
+	}
+}
+
+// stop stops all port-forwardings that match and returns their last status
+func (s *Server) stop(name, namespace, kubeContext string) []Status {
+	s.m.Lock()
+	stopped := []*proxy{}
+	for key, p := range s.proxies {
+		if p.status.Matches(name, namespace, kubeContext) {
+			stopped = append(stopped, p)
+			delete(s.proxies, key)
+		}
+	}
+	if len(stopped) > 0 && len(s.proxies) == 0 && !s.stopping() {
+		close(s.idle)
+	}
+	s.m.Unlock()
+
+	statuses := []Status{}
+	for _, p := range stopped {
+		p.cancel()
+		<-p.done
+
+		s.Log.Infof("Stopped port-forwarding for vcluster %s in namespace %s", p.spec.Name, p.spec.Namespace)
+		statuses = append(statuses, p.status)
+	}
+	sortStatuses(statuses)
+	return statuses
+}
+
+// stopping returns true if the daemon is about to stop, s.m needs to be held
+func (s *Server) stopping() bool {
+	select {
+	case <-s.idle:
+		return true
+	default:
+		return false
+	}
+}
+
+func sortStatuses(statuses []Status) {
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Context != statuses[j].Context {
+			return statuses[i].Context < statuses[j].Context
+		} else if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+
+		return statuses[i].Name < statuses[j].Name
+	})
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(obj)
+}
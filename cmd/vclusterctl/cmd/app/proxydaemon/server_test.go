@@ -0,0 +1,118 @@
+package proxydaemon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"gotest.tools/assert"
+)
+
+// fakeForwarder connects to pod-0, pod-1, ... and loses the connection whenever lose is called
+type fakeForwarder struct {
+	m    sync.Mutex
+	pods int
+	lost map[string]chan struct{}
+}
+
+func (f *fakeForwarder) Forward(ctx context.Context, spec *Spec, connected func(pod string)) error {
+	f.m.Lock()
+	pod := fmt.Sprintf("pod-%d", f.pods)
+	f.pods++
+	lost := make(chan struct{})
+	f.lost[spec.Name] = lost
+	f.m.Unlock()
+
+	connected(pod)
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-lost:
+		return fmt.Errorf("lost connection to pod %s", pod)
+	}
+}
+
+func (f *fakeForwarder) lose(name string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	close(f.lost[name])
+}
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	socketPath := filepath.Join(t.TempDir(), socketFileName)
+	listener, err := Listen(socketPath)
+	assert.NilError(t, err)
+
+	// a second daemon is not allowed to take over the socket
+	_, err = Listen(socketPath)
+	assert.ErrorContains(t, err, "already running")
+
+	forwarder := &fakeForwarder{lost: map[string]chan struct{}{}}
+	server := &Server{
+		Forwarder:     forwarder,
+		Log:           &log.DiscardLogger{},
+		RetryInterval: time.Millisecond,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(ctx, listener)
+	}()
+
+	client := NewClient(socketPath)
+	test := &Spec{Name: "test", Namespace: "test", Context: "kind", LocalPort: 11000, RemotePort: 8443}
+	_, err = client.Start(ctx, test)
+	assert.NilError(t, err)
+	assert.NilError(t, client.WaitForConnected(ctx, test, time.Second*5))
+
+	// the local port can only be used once
+	_, err = client.Start(ctx, &Spec{Name: "other", Namespace: "test", Context: "kind", LocalPort: 11000, RemotePort: 8443})
+	assert.ErrorContains(t, err, "local port 11000 is already used")
+	other := &Spec{Name: "other", Namespace: "test", Context: "kind", LocalPort: 11001, RemotePort: 8443}
+	_, err = client.Start(ctx, other)
+	assert.NilError(t, err)
+	assert.NilError(t, client.WaitForConnected(ctx, other, time.Second*5))
+
+	// a lost connection is reestablished
+	forwarder.lose("test")
+	var status Status
+	for i := 0; i < 100; i++ {
+		statuses, err := client.List(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, len(statuses), 2)
+		status = statuses[1]
+		if status.Reconnects == 1 && status.Phase == PhaseConnected {
+			break
+		}
+
+		time.Sleep(time.Millisecond * 20)
+	}
+	assert.Equal(t, status.Name, "test")
+	assert.Equal(t, status.Reconnects, 1)
+	assert.Equal(t, status.Phase, PhaseConnected)
+	assert.Equal(t, status.Pod, "pod-2")
+	assert.Equal(t, status.LastError, "lost connection to pod pod-0")
+
+	// stopping the last port-forwarding stops the daemon
+	stopped, err := client.Stop(ctx, "test", "", "")
+	assert.NilError(t, err)
+	assert.Equal(t, len(stopped), 1)
+	stopped, err = client.Stop(ctx, "", "", "")
+	assert.NilError(t, err)
+	assert.Equal(t, len(stopped), 1)
+	assert.Equal(t, stopped[0].Name, "other")
+
+	select {
+	case err := <-serveErr:
+		assert.NilError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("daemon did not stop")
+	}
+}
@@ -0,0 +1,63 @@
+package proxydaemon
+
+import (
+	"strings"
+	"time"
+)
+
+type Phase string
+
+const (
+	PhaseConnecting   Phase = "Connecting"
+	PhaseConnected    Phase = "Connected"
+	PhaseReconnecting Phase = "Reconnecting"
+)
+
+// Spec describes a port-forwarding to a virtual cluster the daemon should maintain
+type Spec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Context   string `json:"context,omitempty"`
+
+	// Pod pins the port-forwarding to a single pod. If empty, the newest ready pod
+	// of the virtual cluster is used, which is looked up again after every disconnect.
+	Pod string `json:"pod,omitempty"`
+
+	Address    string `json:"address,omitempty"`
+	LocalPort  int    `json:"localPort"`
+	RemotePort int    `json:"remotePort"`
+
+	// KubeConfig is the host kube config flattened to the context of the virtual cluster
+	KubeConfig []byte `json:"kubeConfig,omitempty"`
+}
+
+// Key identifies the port-forwarding of a virtual cluster
+func (s *Spec) Key() string {
+	return strings.Join([]string{s.Context, s.Namespace, s.Name}, "/")
+}
+
+// Status is the current state of a port-forwarding maintained by the daemon
+type Status struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Context   string `json:"context,omitempty"`
+	Address   string `json:"address,omitempty"`
+	LocalPort int    `json:"localPort"`
+
+	Phase Phase  `json:"phase"`
+	Pod   string `json:"pod,omitempty"`
+
+	// Reconnects counts how often the port-forwarding was reestablished after it was lost
+	Reconnects int `json:"reconnects"`
+
+	// LastError is the error of the last failed or lost connection
+	LastError string `json:"lastError,omitempty"`
+
+	// Since is when the phase changed the last time
+	Since time.Time `json:"since"`
+}
+
+// Matches returns true if the status belongs to the given virtual cluster. Empty values match everything.
+func (s *Status) Matches(name, namespace, kubeContext string) bool {
+	return (name == "" || s.Name == name) && (namespace == "" || s.Namespace == namespace) && (kubeContext == "" || s.Context == kubeContext)
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/loft-sh/utils/pkg/helm/values"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/apply"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/create"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/apis/vcluster/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+)
+
+// ApplyCmd holds the apply cmd flags
+type ApplyCmd struct {
+	*flags.GlobalFlags
+
+	File   string
+	DryRun bool
+
+	Log log.Logger
+}
+
+// NewApplyCmd creates a new command
+func NewApplyCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ApplyCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Creates or updates a virtual cluster from a declarative spec",
+		Long: `
+#######################################################
+#################### vcluster apply ###################
+#######################################################
+Converts a vcluster.loft.sh/v1alpha1 VCluster spec to
+chart values, compares them with the deployed release,
+prints the plan and only upgrades the virtual cluster
+if something changed.
+
+Example spec:
+apiVersion: vcluster.loft.sh/v1alpha1
+kind: VCluster
+metadata:
+  name: test
+  namespace: test
+spec:
+  distro: k3s
+  kubernetesVersion: v1.27
+  sync:
+    resources:
+      ingresses: true
+
+Example:
+vcluster apply -f vcluster.yaml
+vcluster apply -f vcluster.yaml --dry-run
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.File, "file", "f", "", "The file with the VCluster spec, use - to read from stdin")
+	cobraCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "If enabled, only prints the plan without applying it")
+	_ = cobraCmd.MarkFlagRequired("file")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *ApplyCmd) Run(ctx context.Context) error {
+	vCluster, err := apply.Load(cmd.File)
+	if err != nil {
+		return err
+	}
+
+	// the sdk helm client doesn't need the helm binary
+	helmBinaryPath := ""
+	if helm.NeedsBinary(helm.ClientType(cmd.HelmClient)) {
+		helmBinaryPath, err = GetHelmBinaryPath(ctx, cmd.Log)
+		if err != nil {
+			return err
+		}
+		output, err := exec.Command(helmBinaryPath, "version", "--client").CombinedOutput()
+		if errHelm := CheckHelmVersion(string(output)); errHelm != nil {
+			return errHelm
+		} else if err != nil {
+			return err
+		}
+	}
+
+	// the spec is deployed the same way vcluster create would deploy it
+	globalFlags := *cmd.GlobalFlags
+	if vCluster.Namespace != "" {
+		globalFlags.Namespace = vCluster.Namespace
+	}
+	createCmd := &CreateCmd{
+		GlobalFlags:   &globalFlags,
+		CreateOptions: createOptions(vCluster),
+		log:           cmd.Log,
+	}
+	// only resolve the namespace here, it is created after the plan was printed
+	err = createCmd.loadKubeConfig()
+	if err != nil {
+		return err
+	}
+	err = createCmd.resolveNamespace(vCluster.Name)
+	if err != nil {
+		return err
+	}
+
+	// compare with the deployed release
+	var desiredValues map[string]interface{}
+	changes := []apply.Change{}
+	release, err := helm.NewSecrets(createCmd.kubeClient).Get(ctx, vCluster.Name, createCmd.Namespace)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "get helm release")
+	} else if release != nil {
+		// the namespace exists already, so the service cidr can be detected before the plan
+		createCmd.detectServiceCIDR(ctx)
+		desiredValues, err = cmd.desiredValues(createCmd, vCluster)
+		if err != nil {
+			return err
+		}
+
+		changes, err = apply.Diff(release.Config, desiredValues)
+		if err != nil {
+			return err
+		}
+
+		currentChart := release.Chart.Metadata.Name + " " + release.Chart.Metadata.Version
+		desiredChart := createCmd.ChartName + " " + strings.TrimPrefix(createCmd.ChartVersion, "v")
+		if currentChart != desiredChart {
+			changes = append([]apply.Change{{Path: "chart", Old: currentChart, New: desiredChart}}, changes...)
+		}
+		if len(changes) == 0 && release.Secret.Labels["status"] == "deployed" {
+			cmd.Log.Donef("vcluster %s in namespace %s is up to date", vCluster.Name, createCmd.Namespace)
+			return nil
+		}
+	}
+
+	// print the plan
+	if release == nil {
+		cmd.Log.Infof("Plan: create vcluster %s in namespace %s with chart %s %s", vCluster.Name, createCmd.Namespace, createCmd.ChartName, strings.TrimPrefix(createCmd.ChartVersion, "v"))
+	} else {
+		cmd.Log.Infof("Plan: update vcluster %s in namespace %s", vCluster.Name, createCmd.Namespace)
+		if len(changes) == 0 {
+			cmd.Log.WriteString(fmt.Sprintf("  release is %s and will be upgraded without changes\n", release.Secret.Labels["status"]))
+		}
+		for _, change := range changes {
+			cmd.Log.WriteString("  " + change.String() + "\n")
+		}
+	}
+	if cmd.DryRun {
+		return nil
+	}
+
+	// create the namespace of a new vcluster
+	if release == nil {
+		err = createCmd.prepareNamespace(ctx, vCluster.Name)
+		if err != nil {
+			return err
+		}
+		desiredValues, err = cmd.desiredValues(createCmd, vCluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	out, err := yaml.Marshal(desiredValues)
+	if err != nil {
+		return err
+	}
+	err = createCmd.deployChart(ctx, vCluster.Name, string(out), helmBinaryPath)
+	if err != nil {
+		return err
+	}
+
+	cmd.Log.Donef("Successfully applied vcluster %s in namespace %s", vCluster.Name, createCmd.Namespace)
+	return nil
+}
+
+// desiredValues returns the helm values vcluster create would deploy for the spec
+func (cmd *ApplyCmd) desiredValues(createCmd *CreateCmd, vCluster *v1alpha1.VCluster) (map[string]interface{}, error) {
+	kubernetesVersion, err := createCmd.getKubernetesVersion()
+	if err != nil {
+		return nil, err
+	}
+	chartOptions, err := createCmd.ToChartOptions(kubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	defaultValues, err := values.GetDefaultReleaseValues(chartOptions, cmd.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	return apply.Values(vCluster, defaultValues)
+}
+
+func createOptions(vCluster *v1alpha1.VCluster) create.CreateOptions {
+	options := create.CreateOptions{
+		ChartName:         vCluster.Spec.Chart.Name,
+		ChartRepo:         vCluster.Spec.Chart.Repo,
+		ChartVersion:      vCluster.Spec.Chart.Version,
+		Distro:            vCluster.Spec.Distro,
+		KubernetesVersion: vCluster.Spec.KubernetesVersion,
+		CreateNamespace:   true,
+		Expose:            vCluster.Spec.Expose,
+		ExposeLocal:       true,
+		Upgrade:           true,
+		Isolate:           vCluster.Spec.Isolation.Enabled,
+	}
+	if options.ChartName == "" {
+		options.ChartName = "vcluster"
+	}
+	if options.ChartRepo == "" {
+		options.ChartRepo = LoftChartRepo
+	}
+	if options.ChartVersion == "" {
+		options.ChartVersion = upgrade.GetVersion()
+	}
+	if options.Distro == "" {
+		options.Distro = "k3s"
+	}
+
+	return options
+}
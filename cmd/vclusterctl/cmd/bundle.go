@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/loft-sh/utils/pkg/helm/values"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	syncercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/bundle"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/create"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/coredns"
+	"github.com/loft-sh/vcluster/pkg/embed"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+)
+
+// NewBundleCmd creates a new command
+func NewBundleCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	bundleCmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manages bundles for air-gapped installations",
+		Long: `
+#######################################################
+################### vcluster bundle ###################
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	bundleCmd.AddCommand(NewBundleCreateCmd(globalFlags))
+	return bundleCmd
+}
+
+// BundleCreateCmd holds the bundle create cmd flags
+type BundleCreateCmd struct {
+	*flags.GlobalFlags
+	create.CreateOptions
+
+	Platform   string
+	OutputFile string
+
+	Log log.Logger
+}
+
+// NewBundleCreateCmd creates a new command
+func NewBundleCreateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &BundleCreateCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Creates a bundle for air-gapped installations",
+		Long: `
+#######################################################
+################ vcluster bundle create ###############
+#######################################################
+Creates a tar.gz with an OCI image layout that contains
+the vcluster chart for the given distro and version, the
+helm binary of the current platform and all images the
+rendered chart references. The bundle can be installed
+without internet access via:
+
+vcluster create test --bundle bundle.tar.gz --bundle-registry my-registry.com:5000
+
+Example:
+vcluster bundle create --distro k3s --kubernetes-version v1.26
+vcluster bundle create --distro k8s --kubernetes-version v1.26 -f values.yaml --platform linux/arm64
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ChartVersion, "chart-version", upgrade.GetVersion(), "The virtual cluster chart version to use (e.g. v0.9.1)")
+	cobraCmd.Flags().StringVar(&cmd.ChartName, "chart-name", "vcluster", "The virtual cluster chart name to use")
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", LoftChartRepo, "The virtual cluster chart repo to use")
+	cobraCmd.Flags().StringVar(&cmd.LocalChartDir, "local-chart-dir", "", "The path of a packaged virtual cluster chart to use")
+	cobraCmd.Flags().StringVar(&cmd.Distro, "distro", "k3s", fmt.Sprintf("Kubernetes distro to use for the virtual cluster. Allowed distros: %s", strings.Join(AllowedDistros, ", ")))
+	cobraCmd.Flags().StringVar(&cmd.KubernetesVersion, "kubernetes-version", "", "The kubernetes version of the virtual cluster (e.g. v1.26), which decides on the images of the bundle")
+	cobraCmd.Flags().StringSliceVarP(&cmd.ExtraValues, "extra-values", "f", []string{}, "Path where to load extra helm values from, images set within the values are bundled as well")
+	cobraCmd.Flags().BoolVar(&cmd.Isolate, "isolate", false, "If true the bundle contains the images for an isolated virtual cluster")
+	cobraCmd.Flags().StringVar(&cmd.Platform, "platform", "linux/amd64", "The platform of the host cluster nodes to bundle the images for")
+	cobraCmd.Flags().StringVar(&cmd.OutputFile, "output-file", "", "The path of the bundle, defaults to vcluster-bundle-DISTRO-VERSION.tar.gz")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *BundleCreateCmd) Run(ctx context.Context) error {
+	if cmd.KubernetesVersion == "" {
+		return fmt.Errorf("please specify the kubernetes version of the virtual cluster via --kubernetes-version")
+	}
+	platform, err := bundle.ParsePlatform(cmd.Platform)
+	if err != nil {
+		return err
+	}
+
+	// the values are rendered the same way vcluster create would render them
+	createCmd := &CreateCmd{
+		GlobalFlags:   cmd.GlobalFlags,
+		CreateOptions: cmd.CreateOptions,
+		log:           cmd.Log,
+	}
+	kubernetesVersion, err := createCmd.getKubernetesVersion()
+	if err != nil {
+		return err
+	}
+	chartOptions, err := createCmd.ToChartOptions(kubernetesVersion)
+	if err != nil {
+		return err
+	}
+	chartValues, err := values.GetDefaultReleaseValues(chartOptions, cmd.Log)
+	if err != nil {
+		return err
+	}
+	chartVersion := strings.TrimPrefix(createCmd.ChartVersion, "v")
+
+	cmd.Log.Infof("Loading chart %s %s...", createCmd.ChartName, chartVersion)
+	chart, err := loadChart(ctx, createCmd.ChartName, chartVersion, createCmd.ChartRepo, createCmd.LocalChartDir)
+	if err != nil {
+		return err
+	}
+	// the helm binary is added to the bundle, unless the sdk helm client is used
+	helmBinaryPath := ""
+	if helm.NeedsBinary(helm.ClientType(cmd.HelmClient)) {
+		helmBinaryPath, err = GetHelmBinaryPath(ctx, cmd.Log)
+		if err != nil {
+			return err
+		}
+	}
+	helmClient, err := helm.NewClientWithType(helm.ClientType(cmd.HelmClient), &clientcmdapi.Config{}, cmd.Log, helmBinaryPath)
+	if err != nil {
+		return err
+	}
+
+	images, err := renderImages(ctx, helmClient, chart, chartValues, cmd.ExtraValues)
+	if err != nil {
+		return err
+	}
+	coreDNSImage, ok := constants.CoreDNSVersionMap[kubernetesVersion.Major+"."+kubernetesVersion.Minor]
+	if !ok {
+		coreDNSImage = coredns.DefaultImage
+	}
+	images = append(images, coreDNSImage, syncercontext.DefaultHostsRewriteImage)
+
+	// build the bundle
+	tempDir, err := os.MkdirTemp("", "vcluster-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	builder, err := bundle.NewBuilder(tempDir, bundle.Metadata{
+		Distro:            cmd.Distro,
+		ChartName:         createCmd.ChartName,
+		ChartVersion:      chartVersion,
+		KubernetesVersion: createCmd.KubernetesVersion,
+	}, platform, bundle.NewClient())
+	if err != nil {
+		return err
+	}
+	err = builder.AddChart(chart, createCmd.ChartName, chartVersion)
+	if err != nil {
+		return err
+	}
+	if helmBinaryPath != "" {
+		err = builder.AddHelm(helmBinaryPath)
+		if err != nil {
+			return err
+		}
+	}
+	for _, image := range images {
+		cmd.Log.Infof("Pull image %s for %s", image, platform)
+		err = builder.AddImage(ctx, image)
+		if err != nil {
+			return err
+		}
+	}
+	err = builder.Close()
+	if err != nil {
+		return err
+	}
+
+	// write the archive
+	if cmd.OutputFile == "" {
+		cmd.OutputFile = fmt.Sprintf("vcluster-bundle-%s-%s.tar.gz", cmd.Distro, chartVersion)
+	}
+	file, err := os.OpenFile(cmd.OutputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "create bundle")
+	}
+	defer file.Close()
+
+	err = builder.Layout().Archive(file)
+	if err != nil {
+		return errors.Wrap(err, "write bundle")
+	}
+
+	cmd.Log.Donef("Successfully wrote bundle with %d images to %s", len(images), cmd.OutputFile)
+	return nil
+}
+
+// loadChart returns the packaged chart from the local path, the embedded charts or the loft chart repository
+func loadChart(ctx context.Context, chartName, chartVersion, chartRepo, localChart string) ([]byte, error) {
+	if localChart != "" {
+		if !strings.HasSuffix(localChart, ".tgz") {
+			return nil, fmt.Errorf("please package the chart via helm package and pass the path of the .tgz via --local-chart-dir")
+		}
+
+		return os.ReadFile(localChart)
+	}
+
+	if "v"+chartVersion == upgrade.GetVersion() || chartVersion == upgrade.GetVersion() {
+		// not using filepath.Join because the embed.FS separator is not OS specific
+		chart, err := embed.Charts.ReadFile(fmt.Sprintf("charts/%s-%s.tgz", chartName, upgrade.GetVersion()))
+		if err == nil {
+			return chart, nil
+		}
+	}
+
+	if chartRepo != LoftChartRepo {
+		return nil, fmt.Errorf("bundles can only be created from the chart repository %s, please use --local-chart-dir for other charts", LoftChartRepo)
+	}
+
+	chartURL := LoftChartRepo + "/charts/" + chartName + "-" + chartVersion + ".tgz"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, chartURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "download chart %s", chartURL)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download chart %s: unexpected status code %d", chartURL, response.StatusCode)
+	}
+
+	return io.ReadAll(response.Body)
+}
+
+// renderImages renders the chart with helm template and returns the referenced images
+func renderImages(ctx context.Context, helmClient helm.Client, chart []byte, chartValues string, extraValues []string) ([]string, error) {
+	chartFile, err := os.CreateTemp("", "vcluster-chart-*.tgz")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(chartFile.Name())
+
+	_, err = chartFile.Write(chart)
+	chartFile.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := helmClient.Template(ctx, "vcluster", "vcluster", helm.UpgradeOptions{
+		Path:        chartFile.Name(),
+		Values:      chartValues,
+		ValuesFiles: extraValues,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "render chart")
+	}
+
+	return bundle.ImagesFromManifests(string(manifests)), nil
+}
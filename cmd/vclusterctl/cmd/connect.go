@@ -28,6 +28,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/localkubernetes"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/proxydaemon"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
@@ -47,12 +48,14 @@ type ConnectCmd struct {
 	UpdateCurrent         bool
 	Print                 bool
 	BackgroundProxy       bool
+	Daemon                bool
 	LocalPort             int
 	Address               string
 
 	ServiceAccount            string
 	ServiceAccountClusterRole string
 	ServiceAccountExpiration  int
+	CredentialsPlugin         bool
 
 	Server   string
 	Insecure bool
@@ -64,6 +67,9 @@ type ConnectCmd struct {
 	restConfig       *rest.Config
 	rawConfig        api.Config
 
+	// tokenExpiration is when the created service account token expires
+	tokenExpiration time.Time
+
 	portForwarding bool
 	interruptChan  chan struct{}
 	errorChan      chan error
@@ -90,6 +96,8 @@ vcluster connect test --namespace test
 # Open a new bash with the vcluster KUBECONFIG defined
 vcluster connect test -n test -- bash
 vcluster connect test -n test -- kubectl get ns
+# Keep the port-forwarding alive in a background daemon
+vcluster connect test -n test --daemon
 #######################################################
 	`,
 		Args:              cobra.MinimumNArgs(1),
@@ -113,8 +121,10 @@ vcluster connect test -n test -- kubectl get ns
 	cobraCmd.Flags().StringVar(&cmd.ServiceAccount, "service-account", "", "If specified, vcluster will create a service account token to connect to the virtual cluster instead of using the default client cert / key. Service account must exist and can be used as namespace/name.")
 	cobraCmd.Flags().StringVar(&cmd.ServiceAccountClusterRole, "cluster-role", "", "If specified, vcluster will create the service account if it does not exist and also add a cluster role binding for the given cluster role to it. Requires --service-account to be set")
 	cobraCmd.Flags().IntVar(&cmd.ServiceAccountExpiration, "token-expiration", 0, "If specified, vcluster will create the service account token for the given duration in seconds. Defaults to eternal")
+	cobraCmd.Flags().BoolVar(&cmd.CredentialsPlugin, "credentials-plugin", false, "If specified, the kube config uses `vcluster credentials` as exec plugin to request short-lived service account tokens on demand instead of containing a token. Requires --service-account to be set")
 	cobraCmd.Flags().BoolVar(&cmd.Insecure, "insecure", false, "If specified, vcluster will create the kube config with insecure-skip-tls-verify")
 	cobraCmd.Flags().BoolVar(&cmd.BackgroundProxy, "background-proxy", false, "If specified, vcluster will create the background proxy in docker [its mainly used for vclusters with no nodeport service.]")
+	cobraCmd.Flags().BoolVar(&cmd.Daemon, "daemon", false, "If specified, vcluster will keep the port-forwarding alive in a background daemon process instead of this terminal. Use `vcluster proxies` to manage it")
 	return cobraCmd
 }
 
@@ -131,6 +141,15 @@ func (cmd *ConnectCmd) Run(ctx context.Context, args []string) error {
 func (cmd *ConnectCmd) Connect(ctx context.Context, vclusterName string, command []string) error {
 	if vclusterName == "" && cmd.PodName == "" {
 		return fmt.Errorf("please specify either --pod or a name for the vcluster")
+	} else if cmd.Daemon && cmd.BackgroundProxy {
+		return fmt.Errorf("--daemon cannot be used together with --background-proxy")
+	} else if cmd.Daemon && len(command) > 0 {
+		return fmt.Errorf("--daemon cannot be used together with a command")
+	} else if cmd.CredentialsPlugin && (cmd.ServiceAccount == "" || vclusterName == "") {
+		return fmt.Errorf("--credentials-plugin requires a vcluster name and --service-account to be set")
+	}
+	if cmd.CredentialsPlugin && cmd.ServiceAccountExpiration == 0 {
+		cmd.ServiceAccountExpiration = defaultCredentialsExpiration
 	}
 
 	// prepare clients and find vcluster
@@ -208,6 +227,9 @@ func (cmd *ConnectCmd) Connect(ctx context.Context, vclusterName string, command
 			cmd.Log.WriteString("- Use CTRL+C to return to your previous kube context\n")
 			cmd.Log.WriteString("- Use `kubectl get namespaces` in another terminal to access the vcluster\n")
 		} else {
+			if cmd.Daemon && cmd.Server == "" {
+				cmd.Log.WriteString(fmt.Sprintf("- Use `vcluster proxies stop %s -n %s` to stop the port-forwarding in the background\n", vclusterName, cmd.Namespace))
+			}
 			cmd.Log.WriteString("- Use `vcluster disconnect` to return to your previous kube context\n")
 			cmd.Log.WriteString("- Use `kubectl get namespaces` to access the vcluster\n")
 		}
@@ -396,7 +418,12 @@ func (cmd *ConnectCmd) getVClusterKubeConfig(ctx context.Context, vclusterName s
 	}
 
 	// start port forwarding
-	if cmd.ServiceAccount != "" || cmd.Server == "" || len(command) > 0 {
+	if cmd.Daemon && cmd.Server == "" && len(command) == 0 {
+		err = cmd.startDaemonProxy(ctx, vclusterName, port)
+		if err != nil {
+			return nil, err
+		}
+	} else if cmd.ServiceAccount != "" || cmd.Server == "" || len(command) > 0 {
 		cmd.portForwarding = true
 		cmd.interruptChan = make(chan struct{})
 		cmd.errorChan = make(chan error)
@@ -429,11 +456,102 @@ func (cmd *ConnectCmd) getVClusterKubeConfig(ctx context.Context, vclusterName s
 				ImpersonateUserExtra: make(map[string][]string),
 			}
 		}
+
+		// the token is only cached and requested again by the exec plugin when it expires
+		if cmd.CredentialsPlugin {
+			cachePath, err := credentialsCachePath(cmd.Context, cmd.Namespace, vclusterName, cmd.ServiceAccount)
+			if err != nil {
+				return nil, err
+			}
+			err = writeCachedCredential(cachePath, newExecCredential(token, cmd.tokenExpiration))
+			if err != nil {
+				cmd.Log.Warnf("Error caching service account token: %v", err)
+			}
+
+			for k := range kubeConfig.AuthInfos {
+				kubeConfig.AuthInfos[k] = &api.AuthInfo{
+					Exec:                 cmd.credentialsExecConfig(vclusterName),
+					Extensions:           make(map[string]runtime.Object),
+					ImpersonateUserExtra: make(map[string][]string),
+				}
+			}
+		}
 	}
 
 	return kubeConfig, nil
 }
 
+// startDaemonProxy hands the port-forwarding of the vcluster over to the proxy daemon
+func (cmd *ConnectCmd) startDaemonProxy(ctx context.Context, vclusterName, remotePort string) error {
+	hostKubeConfig, err := cmd.flattenHostKubeConfig()
+	if err != nil {
+		return err
+	}
+	out, err := clientcmd.Write(*hostKubeConfig)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(remotePort)
+	if err != nil {
+		return errors.Wrap(err, "parse vcluster port")
+	}
+
+	spec := &proxydaemon.Spec{
+		Name:       vclusterName,
+		Namespace:  cmd.Namespace,
+		Context:    hostKubeConfig.CurrentContext,
+		Pod:        cmd.PodName,
+		Address:    cmd.Address,
+		LocalPort:  cmd.LocalPort,
+		RemotePort: port,
+		KubeConfig: out,
+	}
+	if spec.Name == "" {
+		spec.Name = cmd.PodName
+	}
+
+	client, err := proxydaemon.EnsureRunning(ctx, cmd.Log)
+	if err != nil {
+		return err
+	}
+	_, err = client.Start(ctx, spec)
+	if err != nil {
+		return err
+	}
+	err = client.WaitForConnected(ctx, spec, time.Minute)
+	if err != nil {
+		return errors.Wrap(err, "wait for port-forwarding")
+	}
+
+	cmd.Log.Donef("Port-forwarding vcluster %s on port %d in the background", spec.Name, cmd.LocalPort)
+	return nil
+}
+
+// flattenHostKubeConfig returns the host kube config reduced to the current context with all referenced files inlined,
+// so the proxy daemon doesn't depend on the kube config of the terminal that started it
+func (cmd *ConnectCmd) flattenHostKubeConfig() (*api.Config, error) {
+	hostKubeConfig := cmd.rawConfig.DeepCopy()
+	if hostKubeConfig.CurrentContext == "" {
+		rawConfig, err := cmd.kubeClientConfig.RawConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		hostKubeConfig.CurrentContext = rawConfig.CurrentContext
+	}
+
+	err := api.MinifyConfig(hostKubeConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "minify host kube config")
+	}
+	err = api.FlattenConfig(hostKubeConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "flatten host kube config")
+	}
+
+	return hostKubeConfig, nil
+}
+
 func (cmd *ConnectCmd) setServerIfExposed(ctx context.Context, vClusterName string, vClusterConfig *api.Config) error {
 	printedWaiting := false
 	err := wait.PollUntilContextTimeout(ctx, time.Second*2, time.Minute*5, true, func(ctx context.Context) (done bool, err error) {
@@ -621,6 +739,42 @@ func (cmd *ConnectCmd) executeCommand(ctx context.Context, vKubeConfig api.Confi
 	}
 }
 
+// getVClusterClient returns a client for the virtual cluster that is reached through a silent port-forwarding.
+// The returned function stops the port-forwarding.
+func getVClusterClient(ctx context.Context, globalFlags *flags.GlobalFlags, vclusterName string, log log.Logger) (kubernetes.Interface, func(), error) {
+	connectCmd := &ConnectCmd{
+		GlobalFlags: globalFlags,
+		Log:         log,
+	}
+	err := connectCmd.prepare(ctx, vclusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// passing a command makes sure the virtual cluster is reached through a silent port-forwarding
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vclusterName, []string{vclusterName})
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := func() {
+		close(connectCmd.interruptChan)
+	}
+
+	err = connectCmd.waitForVCluster(ctx, *kubeConfig, connectCmd.errorChan)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	vKubeClient, err := connectCmd.getLocalVClusterClient(*kubeConfig)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	return vKubeClient, stop, nil
+}
+
 func (cmd *ConnectCmd) getLocalVClusterConfig(vKubeConfig api.Config) api.Config {
 	// wait until we can access the virtual cluster
 	vKubeConfig = *vKubeConfig.DeepCopy()
@@ -799,6 +953,7 @@ func (cmd *ConnectCmd) createServiceAccountToken(ctx context.Context, vKubeConfi
 		}
 
 		token = result.Status.Token
+		cmd.tokenExpiration = result.Status.ExpirationTimestamp.Time
 		return true, nil
 	})
 	if err != nil {
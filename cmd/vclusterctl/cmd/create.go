@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/bundle"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/doctor"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/localkubernetes"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/log/survey"
@@ -114,6 +116,9 @@ vcluster create test --namespace test
 	cobraCmd.Flags().BoolVar(&cmd.Connect, "connect", true, "If true will run vcluster connect directly after the vcluster was created")
 	cobraCmd.Flags().BoolVar(&cmd.Upgrade, "upgrade", false, "If true will try to upgrade the vcluster instead of failing if it already exists")
 	cobraCmd.Flags().BoolVar(&cmd.Isolate, "isolate", false, "If true vcluster and its workloads will run in an isolated environment")
+	cobraCmd.Flags().StringVar(&cmd.Bundle, "bundle", "", "The path of a bundle created by vcluster bundle create to install the vcluster from without internet access")
+	cobraCmd.Flags().StringVar(&cmd.BundleRegistry, "bundle-registry", "", "The private registry (e.g. my-registry.com:5000) to push the images of the bundle to. The vcluster will pull its images from there")
+	cobraCmd.Flags().BoolVar(&cmd.SkipPreflightChecks, "skip-preflight-checks", false, "If true the host cluster checks of vcluster doctor are not run before the vcluster is deployed")
 	return cobraCmd
 }
 
@@ -131,20 +136,51 @@ func validateDeprecated(createOptions *create.CreateOptions, log log.Logger) {
 
 // Run executes the functionality
 func (cmd *CreateCmd) Run(ctx context.Context, args []string) error {
-	helmBinaryPath, err := GetHelmBinaryPath(ctx, cmd.log)
-	if err != nil {
-		return err
+	// load the bundle for air-gapped installations
+	var (
+		installBundle  *bundle.Bundle
+		helmBinaryPath string
+	)
+	if cmd.Bundle != "" {
+		bundleDir, err := os.MkdirTemp("", "vcluster-bundle-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(bundleDir)
+
+		installBundle, helmBinaryPath, err = cmd.loadBundle(bundleDir)
+		if err != nil {
+			return err
+		}
+	} else if cmd.BundleRegistry != "" {
+		return fmt.Errorf("--bundle-registry can only be used together with --bundle")
 	}
 
-	output, err := exec.Command(helmBinaryPath, "version", "--client").CombinedOutput()
-	if errHelm := CheckHelmVersion(string(output)); errHelm != nil {
-		return errHelm
+	// the sdk helm client doesn't need the helm binary
+	var err error
+	if helm.NeedsBinary(helm.ClientType(cmd.HelmClient)) {
+		if helmBinaryPath == "" {
+			helmBinaryPath, err = GetHelmBinaryPath(ctx, cmd.log)
+			if err != nil {
+				return err
+			}
+		}
+
+		output, err := exec.Command(helmBinaryPath, "version", "--client").CombinedOutput()
+		if errHelm := CheckHelmVersion(string(output)); errHelm != nil {
+			return errHelm
+		}
+		if err != nil {
+			return err
+		}
 	}
+
+	// load the kube config, the namespace is only created after the preflight checks passed
+	err = cmd.loadKubeConfig()
 	if err != nil {
 		return err
 	}
-
-	err = cmd.prepare(ctx, args[0])
+	err = cmd.resolveNamespace(args[0])
 	if err != nil {
 		return err
 	}
@@ -155,16 +191,6 @@ func (cmd *CreateCmd) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	// load the default values
-	chartOptions, err := cmd.ToChartOptions(kubernetesVersion)
-	if err != nil {
-		return err
-	}
-	chartValues, err := values.GetDefaultReleaseValues(chartOptions, cmd.log)
-	if err != nil {
-		return err
-	}
-
 	var newExtraValues []string
 	for _, value := range cmd.ExtraValues {
 		decodedString, err := getBase64DecodedString(value)
@@ -226,6 +252,48 @@ func (cmd *CreateCmd) Run(ctx context.Context, args []string) error {
 		}
 	}
 
+	chartOptions, err := cmd.ToChartOptions(kubernetesVersion)
+	if err != nil {
+		return err
+	}
+
+	// check if the host cluster is ready for the vcluster
+	if !cmd.SkipPreflightChecks {
+		results, err := cmd.preflight(ctx, chartOptions)
+		if err != nil {
+			return err
+		}
+
+		logPreflightResults(cmd.log, results)
+		if doctor.HasErrors(results) {
+			return fmt.Errorf("preflight checks failed, use `vcluster doctor %s -n %s` for details or --skip-preflight-checks to deploy the vcluster anyway", args[0], cmd.Namespace)
+		}
+	}
+
+	// ensure the namespace and detect the service cidr
+	err = cmd.prepareNamespace(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	// load the default values
+	chartOptions.CIDR = cmd.CIDR
+	chartValues, err := values.GetDefaultReleaseValues(chartOptions, cmd.log)
+	if err != nil {
+		return err
+	}
+	if cmd.BundleRegistry != "" {
+		chartValues += "\ndefaultImageRegistry: " + strings.TrimSuffix(cmd.BundleRegistry, "/") + "/"
+	}
+
+	// push the images of the bundle to the private registry
+	if installBundle != nil && cmd.BundleRegistry != "" {
+		err = installBundle.PushImages(ctx, bundle.NewClient(), cmd.BundleRegistry, cmd.log)
+		if err != nil {
+			return err
+		}
+	}
+
 	// we have to upgrade / install the chart
 	err = cmd.deployChart(ctx, args[0], chartValues, helmBinaryPath)
 	if err != nil {
@@ -320,7 +388,11 @@ func (cmd *CreateCmd) deployChart(ctx context.Context, vClusterName, chartValues
 	}
 
 	// we have to upgrade / install the chart
-	err = helm.NewClient(&cmd.rawConfig, cmd.log, helmExecutablePath).Upgrade(ctx, vClusterName, cmd.Namespace, helm.UpgradeOptions{
+	helmClient, err := helm.NewClientWithType(helm.ClientType(cmd.HelmClient), &cmd.rawConfig, cmd.log, helmExecutablePath)
+	if err != nil {
+		return err
+	}
+	err = helmClient.Upgrade(ctx, vClusterName, cmd.Namespace, helm.UpgradeOptions{
 		Chart:       cmd.ChartName,
 		Path:        cmd.LocalChartDir,
 		Repo:        cmd.ChartRepo,
@@ -335,6 +407,76 @@ func (cmd *CreateCmd) deployChart(ctx context.Context, vClusterName, chartValues
 	return nil
 }
 
+// loadBundle extracts the bundle into the directory and configures the chart of the bundle. If helm is not
+// installed, the helm binary of the bundle is returned
+func (cmd *CreateCmd) loadBundle(bundleDir string) (*bundle.Bundle, string, error) {
+	installBundle, err := bundle.Open(cmd.Bundle, bundleDir)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "open bundle")
+	}
+
+	metadata := installBundle.Metadata
+	if cmd.KubernetesVersion != "" && semver.MajorMinor("v"+strings.TrimPrefix(cmd.KubernetesVersion, "v")) != semver.MajorMinor(metadata.KubernetesVersion) {
+		return nil, "", fmt.Errorf("the bundle was created for kubernetes version %s, please create a new bundle for kubernetes version %s", metadata.KubernetesVersion, cmd.KubernetesVersion)
+	} else if cmd.Distro != "k3s" && cmd.Distro != metadata.Distro {
+		return nil, "", fmt.Errorf("the bundle was created for distro %s, please create a new bundle for distro %s", metadata.Distro, cmd.Distro)
+	}
+	cmd.Distro = metadata.Distro
+	cmd.ChartName = metadata.ChartName
+	cmd.ChartVersion = metadata.ChartVersion
+	cmd.KubernetesVersion = metadata.KubernetesVersion
+	cmd.log.Infof("Using bundle with chart %s %s for kubernetes %s", metadata.ChartName, metadata.ChartVersion, metadata.KubernetesVersion)
+
+	chart, err := installBundle.Chart()
+	if err != nil {
+		return nil, "", err
+	}
+	cmd.LocalChartDir = filepath.Join(bundleDir, metadata.ChartName+"-"+metadata.ChartVersion+".tgz")
+	err = os.WriteFile(cmd.LocalChartDir, chart, 0644)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !helm.NeedsBinary(helm.ClientType(cmd.HelmClient)) {
+		return installBundle, "", nil
+	} else if _, err := exec.LookPath("helm"); err == nil {
+		return installBundle, "", nil
+	}
+	helmBinary, err := installBundle.Helm()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "helm is not installed")
+	}
+	helmBinaryPath := filepath.Join(bundleDir, "helm")
+	err = os.WriteFile(helmBinaryPath, helmBinary, 0755)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return installBundle, helmBinaryPath, nil
+}
+
+// preflight runs the checks of vcluster doctor with the values the vcluster would be deployed with
+func (cmd *CreateCmd) preflight(ctx context.Context, chartOptions *helmUtils.ChartOptions) ([]doctor.Result, error) {
+	chartValues, err := values.GetDefaultReleaseValues(chartOptions, log.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := doctor.LoadValues(chartValues, cmd.ExtraValues)
+	if err != nil {
+		return nil, err
+	}
+
+	return doctor.Run(ctx, cmd.kubeClient, &doctor.Options{
+		Namespace:   cmd.Namespace,
+		Distro:      cmd.Distro,
+		Values:      values,
+		CIDR:        cmd.CIDR,
+		ClusterType: localkubernetes.DetectClusterType(&cmd.rawConfig),
+		ExposeLocal: cmd.ExposeLocal,
+	}), nil
+}
+
 func (cmd *CreateCmd) ToChartOptions(kubernetesVersion *version.Info) (*helmUtils.ChartOptions, error) {
 	if !util.Contains(cmd.Distro, AllowedDistros) {
 		return nil, fmt.Errorf("unsupported distro %s, please select one of: %s", cmd.Distro, strings.Join(AllowedDistros, ", "))
@@ -389,7 +531,29 @@ func (cmd *CreateCmd) ToChartOptions(kubernetesVersion *version.Info) (*helmUtil
 	}, nil
 }
 
-func (cmd *CreateCmd) prepare(ctx context.Context, vClusterName string) error {
+func (cmd *CreateCmd) prepareNamespace(ctx context.Context, vClusterName string) error {
+	// ensure namespace
+	err := cmd.ensureNamespace(ctx, vClusterName)
+	if err != nil {
+		return err
+	}
+
+	cmd.detectServiceCIDR(ctx)
+	return nil
+}
+
+// detectServiceCIDR detects the service cidr of the host cluster, the namespace has to exist already
+func (cmd *CreateCmd) detectServiceCIDR(ctx context.Context) {
+	if cmd.CIDR == "" {
+		cidr, warning := servicecidr.GetServiceCIDR(ctx, cmd.kubeClient, cmd.Namespace)
+		if warning != "" {
+			cmd.log.Info(warning)
+		}
+		cmd.CIDR = cidr
+	}
+}
+
+func (cmd *CreateCmd) loadKubeConfig() error {
 	// first load the kube config
 	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
 		CurrentContext: cmd.Context,
@@ -451,26 +615,10 @@ func (cmd *CreateCmd) prepare(ctx context.Context, vClusterName string) error {
 	cmd.kubeClient = client
 	cmd.kubeClientConfig = kubeClientConfig
 	cmd.rawConfig = rawConfig
-
-	// ensure namespace
-	err = cmd.ensureNamespace(ctx, vClusterName)
-	if err != nil {
-		return err
-	}
-
-	// get service cidr
-	if cmd.CIDR == "" {
-		cidr, warning := servicecidr.GetServiceCIDR(ctx, cmd.kubeClient, cmd.Namespace)
-		if warning != "" {
-			cmd.log.Info(warning)
-		}
-		cmd.CIDR = cidr
-	}
-
 	return nil
 }
 
-func (cmd *CreateCmd) ensureNamespace(ctx context.Context, vClusterName string) error {
+func (cmd *CreateCmd) resolveNamespace(vClusterName string) error {
 	var err error
 	if cmd.Namespace == "" {
 		cmd.Namespace, _, err = cmd.kubeClientConfig.Namespace()
@@ -482,6 +630,15 @@ func (cmd *CreateCmd) ensureNamespace(ctx context.Context, vClusterName string)
 		}
 	}
 
+	return nil
+}
+
+func (cmd *CreateCmd) ensureNamespace(ctx context.Context, vClusterName string) error {
+	err := cmd.resolveNamespace(vClusterName)
+	if err != nil {
+		return err
+	}
+
 	// make sure namespace exists
 	namespace, err := cmd.kubeClient.CoreV1().Namespaces().Get(ctx, cmd.Namespace, metav1.GetOptions{})
 	if err != nil {
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthenticationv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/util/cliconfig"
+)
+
+const (
+	// defaultCredentialsExpiration is the lifetime in seconds of the tokens requested by the credentials plugin
+	defaultCredentialsExpiration = 3600
+
+	// credentialsRefreshBefore is how long before its expiration a cached token is replaced
+	credentialsRefreshBefore = time.Minute
+
+	credentialsFolder = "credentials"
+)
+
+// CredentialsCmd holds the credentials cmd flags
+type CredentialsCmd struct {
+	*flags.GlobalFlags
+
+	ServiceAccount  string
+	TokenExpiration int
+
+	Log log.Logger
+}
+
+// NewCredentialsCmd creates a new command
+func NewCredentialsCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &CredentialsCmd{
+		GlobalFlags: globalFlags,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "credentials [flags] vcluster_name",
+		Short: "Prints a short-lived service account token of a virtual cluster for kubectl",
+		Long: `
+#######################################################
+################ vcluster credentials #################
+#######################################################
+Requests a short-lived token for a service account of
+the virtual cluster through the host kube context and
+prints it as client.authentication.k8s.io/v1
+ExecCredential. Tokens are cached in
+~/.vcluster/credentials until shortly before they expire.
+
+This command is used as exec plugin in kube configs
+created by vcluster connect --credentials-plugin.
+
+Example:
+vcluster credentials test --namespace test --service-account kube-system/my-user
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			// stdout is reserved for the exec credential
+			level := logrus.WarnLevel
+			if cmd.Debug {
+				level = logrus.DebugLevel
+			}
+			log.SetInstance(log.NewStreamLogger(os.Stderr, level))
+			cmd.Log = log.GetInstance()
+
+			return cmd.Run(cobraCmd.Context(), args[0])
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ServiceAccount, "service-account", "", "The service account to request the token for. Can be used as namespace/name")
+	cobraCmd.Flags().IntVar(&cmd.TokenExpiration, "token-expiration", defaultCredentialsExpiration, "The lifetime of the requested token in seconds")
+	_ = cobraCmd.MarkFlagRequired("service-account")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *CredentialsCmd) Run(ctx context.Context, vclusterName string) error {
+	cachePath, err := credentialsCachePath(cmd.Context, cmd.Namespace, vclusterName, cmd.ServiceAccount)
+	if err != nil {
+		return err
+	}
+
+	credential := readCachedCredential(cachePath, time.Now())
+	if credential == nil {
+		credential, err = cmd.requestCredential(ctx, vclusterName)
+		if err != nil {
+			return err
+		}
+
+		err = writeCachedCredential(cachePath, credential)
+		if err != nil {
+			cmd.Log.Warnf("Error caching token: %v", err)
+		}
+	}
+
+	out, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(append(out, '\n'))
+	return err
+}
+
+// requestCredential creates a token for the service account through a silent port-forwarding to the virtual cluster
+func (cmd *CredentialsCmd) requestCredential(ctx context.Context, vclusterName string) (*clientauthenticationv1.ExecCredential, error) {
+	connectCmd := &ConnectCmd{
+		GlobalFlags:              cmd.GlobalFlags,
+		ServiceAccount:           cmd.ServiceAccount,
+		ServiceAccountExpiration: cmd.TokenExpiration,
+		Log:                      cmd.Log,
+	}
+	err := connectCmd.prepare(ctx, vclusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// passing a command makes sure the virtual cluster is reached through a silent port-forwarding
+	kubeConfig, err := connectCmd.getVClusterKubeConfig(ctx, vclusterName, []string{vclusterName})
+	if err != nil {
+		return nil, err
+	}
+	close(connectCmd.interruptChan)
+
+	for _, authInfo := range kubeConfig.AuthInfos {
+		if authInfo.Token != "" {
+			return newExecCredential(authInfo.Token, connectCmd.tokenExpiration), nil
+		}
+	}
+
+	return nil, fmt.Errorf("couldn't create a token for service account %s", cmd.ServiceAccount)
+}
+
+// credentialsExecConfig returns the exec plugin configuration that calls vcluster credentials for the virtual cluster
+func (cmd *ConnectCmd) credentialsExecConfig(vclusterName string) *api.ExecConfig {
+	// prefer the binary from the PATH, so the kube config keeps working after the cli was upgraded
+	command := "vcluster"
+	if _, err := exec.LookPath(command); err != nil {
+		executable, err := os.Executable()
+		if err == nil {
+			command = executable
+		}
+	}
+
+	return &api.ExecConfig{
+		APIVersion: clientauthenticationv1.SchemeGroupVersion.String(),
+		Command:    command,
+		Args: []string{
+			"credentials", vclusterName,
+			"--context", cmd.Context,
+			"--namespace", cmd.Namespace,
+			"--service-account", cmd.ServiceAccount,
+			"--token-expiration", strconv.Itoa(cmd.ServiceAccountExpiration),
+		},
+		InstallHint:     "The vcluster CLI is required to access this virtual cluster, see https://www.vcluster.com/docs/getting-started/setup",
+		InteractiveMode: api.NeverExecInteractiveMode,
+	}
+}
+
+func newExecCredential(token string, expiration time.Time) *clientauthenticationv1.ExecCredential {
+	expirationTimestamp := metav1.NewTime(expiration)
+	return &clientauthenticationv1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clientauthenticationv1.SchemeGroupVersion.String(),
+			Kind:       "ExecCredential",
+		},
+		Status: &clientauthenticationv1.ExecCredentialStatus{
+			Token:               token,
+			ExpirationTimestamp: &expirationTimestamp,
+		},
+	}
+}
+
+// credentialsCachePath returns the file the token of the service account is cached in
+func credentialsCachePath(kubeContext, namespace, vclusterName, serviceAccount string) (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Wrap(err, "detect home directory")
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join([]string{kubeContext, namespace, vclusterName, serviceAccount}, "/")))
+	return filepath.Join(home, cliconfig.VclusterFolder, credentialsFolder, hex.EncodeToString(hash[:])[:32]+".json"), nil
+}
+
+// readCachedCredential returns the cached credential or nil if there is none that is valid for a while
+func readCachedCredential(path string, now time.Time) *clientauthenticationv1.ExecCredential {
+	out, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	credential := &clientauthenticationv1.ExecCredential{}
+	err = json.Unmarshal(out, credential)
+	if err != nil || credential.Status == nil || credential.Status.Token == "" || credential.Status.ExpirationTimestamp == nil {
+		return nil
+	} else if credential.Status.ExpirationTimestamp.Time.Before(now.Add(credentialsRefreshBefore)) {
+		return nil
+	}
+
+	return credential
+}
+
+func writeCachedCredential(path string, credential *clientauthenticationv1.ExecCredential) error {
+	out, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, out, 0600)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestCachedCredential(t *testing.T) {
+	now := time.Now()
+	path := filepath.Join(t.TempDir(), "credentials", "token.json")
+
+	// nothing is cached yet
+	assert.Assert(t, readCachedCredential(path, now) == nil)
+
+	err := writeCachedCredential(path, newExecCredential("token", now.Add(time.Hour)))
+	assert.NilError(t, err)
+	credential := readCachedCredential(path, now)
+	assert.Assert(t, credential != nil)
+	assert.Equal(t, credential.Kind, "ExecCredential")
+	assert.Equal(t, credential.APIVersion, "client.authentication.k8s.io/v1")
+	assert.Equal(t, credential.Status.Token, "token")
+
+	// tokens that are about to expire are requested again
+	assert.Assert(t, readCachedCredential(path, now.Add(time.Hour-credentialsRefreshBefore/2)) == nil)
+}
+
+func TestCredentialsExecConfig(t *testing.T) {
+	connectCmd := &ConnectCmd{
+		GlobalFlags: &flags.GlobalFlags{
+			Context:   "kind-kind",
+			Namespace: "test",
+		},
+		ServiceAccount:           "kube-system/my-user",
+		ServiceAccountExpiration: 600,
+	}
+
+	execConfig := connectCmd.credentialsExecConfig("my-vcluster")
+	assert.Equal(t, execConfig.APIVersion, "client.authentication.k8s.io/v1")
+	assert.Equal(t, execConfig.InteractiveMode, api.NeverExecInteractiveMode)
+	assert.DeepEqual(t, execConfig.Args, []string{
+		"credentials", "my-vcluster",
+		"--context", "kind-kind",
+		"--namespace", "test",
+		"--service-account", "kube-system/my-user",
+		"--token-expiration", "600",
+	})
+
+	// the cache is separated per virtual cluster and service account
+	a, err := credentialsCachePath("kind-kind", "test", "my-vcluster", "kube-system/my-user")
+	assert.NilError(t, err)
+	b, err := credentialsCachePath("kind-kind", "test", "my-vcluster", "kube-system/other")
+	assert.NilError(t, err)
+	assert.Assert(t, a != b)
+}
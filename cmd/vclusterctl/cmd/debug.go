@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/debugbundle"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+)
+
+// NewDebugCmd creates a new command
+func NewDebugCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	debugCmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Collects debug information of a virtual cluster",
+		Long: `
+#######################################################
+#################### vcluster debug ###################
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	debugCmd.AddCommand(NewDebugBundleCmd(globalFlags))
+	return debugCmd
+}
+
+// DebugBundleCmd holds the debug bundle cmd flags
+type DebugBundleCmd struct {
+	*flags.GlobalFlags
+
+	OutputFile string
+	TailLines  int64
+
+	Log log.Logger
+}
+
+// NewDebugBundleCmd creates a new command
+func NewDebugBundleCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DebugBundleCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "bundle [flags] vcluster_name",
+		Short: "Collects the debug information of a virtual cluster into a tar.gz",
+		Long: `
+#######################################################
+################# vcluster debug bundle ###############
+#######################################################
+Collects the logs of the control plane containers, the
+helm release and values, the status of the init
+manifests, the events of the host namespace and of the
+virtual cluster into a tar.gz archive. If DEBUG is
+enabled for the syncer, pprof profiles and metrics are
+collected as well.
+
+Passwords, tokens and other credentials within the
+values, pod specs and init manifests are redacted, but
+logs are collected as they are, so please check the
+bundle before sharing it.
+
+Example:
+vcluster debug bundle test --namespace test
+vcluster debug bundle test --namespace test --output-file bundle.tar.gz
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.OutputFile, "output-file", "", "The path of the bundle, defaults to vcluster-NAME-TIMESTAMP.tar.gz")
+	cobraCmd.Flags().Int64Var(&cmd.TailLines, "tail", 10000, "The number of log lines to collect per container, -1 collects all lines")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *DebugBundleCmd) Run(ctx context.Context, args []string) error {
+	vCluster, err := find.GetVCluster(ctx, cmd.Context, args[0], cmd.Namespace)
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+	hostClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	collector := &debugbundle.Collector{
+		Name:       vCluster.Name,
+		Namespace:  vCluster.Namespace,
+		HostClient: hostClient,
+		TailLines:  cmd.TailLines,
+		Log:        cmd.Log,
+	}
+	if vCluster.Status == find.StatusRunning {
+		globalFlags := *cmd.GlobalFlags
+		globalFlags.Namespace = vCluster.Namespace
+		vKubeClient, stop, err := getVClusterClient(ctx, &globalFlags, vCluster.Name, cmd.Log)
+		if err != nil {
+			cmd.Log.Warnf("Error connecting to vcluster %s, virtual cluster information won't be collected: %v", vCluster.Name, err)
+		} else {
+			defer stop()
+			collector.VirtualClient = vKubeClient
+		}
+	} else {
+		cmd.Log.Warnf("vcluster %s is %s, virtual cluster information won't be collected", vCluster.Name, strings.ToLower(string(vCluster.Status)))
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	if cmd.OutputFile == "" {
+		cmd.OutputFile = fmt.Sprintf("vcluster-%s-%s.tar.gz", vCluster.Name, timestamp)
+	}
+	file, err := os.OpenFile(cmd.OutputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "create bundle")
+	}
+	defer file.Close()
+
+	cmd.Log.Infof("Collecting debug information of vcluster %s in namespace %s...", vCluster.Name, vCluster.Namespace)
+	writer := debugbundle.NewWriter(file, fmt.Sprintf("vcluster-%s-%s", vCluster.Name, timestamp))
+	err = collector.Collect(ctx, writer)
+	if err != nil {
+		return errors.Wrap(err, "collect debug information")
+	}
+	err = writer.Close()
+	if err != nil {
+		return errors.Wrap(err, "write bundle")
+	}
+
+	cmd.Log.Donef("Successfully wrote debug bundle to %s", cmd.OutputFile)
+	return nil
+}
@@ -76,18 +76,24 @@ vcluster delete test --namespace test
 func (cmd *DeleteCmd) Run(cobraCmd *cobra.Command, args []string) error {
 	ctx := cobraCmd.Context()
 
-	// test for helm
-	helmBinaryPath, err := GetHelmBinaryPath(ctx, cmd.log)
-	if err != nil {
-		return err
-	}
+	// test for helm, the sdk helm client doesn't need the helm binary
+	var (
+		helmBinaryPath string
+		err            error
+	)
+	if helm.NeedsBinary(helm.ClientType(cmd.HelmClient)) {
+		helmBinaryPath, err = GetHelmBinaryPath(ctx, cmd.log)
+		if err != nil {
+			return err
+		}
 
-	output, err := exec.Command(helmBinaryPath, "version", "--client").CombinedOutput()
-	if errHelm := CheckHelmVersion(string(output)); errHelm != nil {
-		return errHelm
-	}
-	if err != nil {
-		return fmt.Errorf("seems like there are issues with your helm client: \n\n%s", output)
+		output, err := exec.Command(helmBinaryPath, "version", "--client").CombinedOutput()
+		if errHelm := CheckHelmVersion(string(output)); errHelm != nil {
+			return errHelm
+		}
+		if err != nil {
+			return fmt.Errorf("seems like there are issues with your helm client: \n\n%s", output)
+		}
 	}
 
 	// prepare client
@@ -95,6 +101,10 @@ func (cmd *DeleteCmd) Run(cobraCmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	helmClient, err := helm.NewClientWithType(helm.ClientType(cmd.HelmClient), cmd.rawConfig, cmd.log, helmBinaryPath)
+	if err != nil {
+		return err
+	}
 
 	// check if namespace
 	if cmd.AutoDeleteNamespace {
@@ -108,7 +118,7 @@ func (cmd *DeleteCmd) Run(cobraCmd *cobra.Command, args []string) error {
 
 	// we have to delete the chart
 	cmd.log.Infof("Delete vcluster %s...", args[0])
-	err = helm.NewClient(cmd.rawConfig, cmd.log, helmBinaryPath).Delete(args[0], cmd.Namespace)
+	err = helmClient.Delete(args[0], cmd.Namespace)
 	if err != nil {
 		return err
 	}
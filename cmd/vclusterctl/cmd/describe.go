@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"sigs.k8s.io/yaml"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/describe"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/helm"
+)
+
+// DescribeCmd holds the describe cmd flags
+type DescribeCmd struct {
+	*flags.GlobalFlags
+
+	Output string
+
+	Log log.Logger
+}
+
+// NewDescribeCmd creates a new command
+func NewDescribeCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DescribeCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "describe [flags] vcluster_name",
+		Short: "Describes a virtual cluster",
+		Long: `
+#######################################################
+################### vcluster describe #################
+#######################################################
+Describes a virtual cluster based on its helm release
+and its objects within the host cluster: the chart,
+distro, kubernetes version, enabled syncers, plugins,
+endpoints, volumes, control plane pods and the number of
+host objects synced by the virtual cluster per kind.
+
+Example:
+vcluster describe test --namespace test
+vcluster describe test --namespace test -o yaml
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json|yaml]")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *DescribeCmd) Run(ctx context.Context, args []string) error {
+	if cmd.Output != "table" && cmd.Output != "json" && cmd.Output != "yaml" {
+		return fmt.Errorf("unsupported output %s, please use one of: table, json, yaml", cmd.Output)
+	}
+
+	vCluster, err := find.GetVCluster(ctx, cmd.Context, args[0], cmd.Namespace)
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	metadataClient, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	release, err := helm.NewSecrets(kubeClient).Get(ctx, vCluster.Name, vCluster.Namespace)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, "get helm release")
+	} else if release == nil {
+		cmd.Log.Warnf("Couldn't find the helm release of vcluster %s, the chart and values can't be described", vCluster.Name)
+	}
+
+	description := describe.New(vCluster, release)
+	err = description.Collect(ctx, kubeClient, metadataClient, cmd.Log)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Output {
+	case "json":
+		out, err := json.MarshalIndent(description, "", "    ")
+		if err != nil {
+			return errors.Wrap(err, "json marshal description")
+		}
+		cmd.Log.WriteString(string(out) + "\n")
+	case "yaml":
+		out, err := yaml.Marshal(description)
+		if err != nil {
+			return errors.Wrap(err, "yaml marshal description")
+		}
+		cmd.Log.WriteString(string(out))
+	default:
+		cmd.printDescription(description)
+	}
+
+	return nil
+}
+
+func (cmd *DescribeCmd) printDescription(description *describe.Description) {
+	chart := "<none>"
+	if description.Chart.Name != "" {
+		chart = fmt.Sprintf("%s %s (%s, revision %d)", description.Chart.Name, description.Chart.Version, description.Chart.Status, description.Chart.Revision)
+	}
+	mode := "single-namespace"
+	if description.MultiNamespace {
+		mode = "multi-namespace"
+	}
+
+	fields := [][]string{
+		{"Name", description.Name},
+		{"Namespace", description.Namespace},
+		{"Context", description.Context},
+		{"Status", description.Status},
+		{"Created", description.Created.String()},
+		{"Chart", chart},
+		{"Distro", description.Distro},
+		{"Kubernetes Version", description.KubernetesVersion},
+		{"Mode", mode},
+		{"Syncers", joinOrNone(description.Syncers)},
+		{"Plugins", joinOrNone(description.Plugins)},
+	}
+	for _, field := range fields {
+		value := field[1]
+		if value == "" {
+			value = "<unknown>"
+		}
+
+		cmd.Log.WriteString(fmt.Sprintf("%-20s%s\n", field[0]+":", value))
+	}
+
+	if len(description.Endpoints) > 0 {
+		values := [][]string{}
+		for _, endpoint := range description.Endpoints {
+			values = append(values, []string{endpoint.Type, endpoint.Name, endpoint.Address})
+		}
+		log.PrintTable(cmd.Log, []string{"ENDPOINT", "NAME", "ADDRESS"}, values)
+	}
+	if len(description.ControlPlane) > 0 {
+		values := [][]string{}
+		for _, pod := range description.ControlPlane {
+			values = append(values, []string{
+				pod.Name,
+				pod.Component,
+				pod.Ready,
+				pod.Status,
+				strconv.Itoa(int(pod.Restarts)),
+				pod.Node,
+				time.Since(pod.Created).Round(time.Second).String(),
+			})
+		}
+		log.PrintTable(cmd.Log, []string{"POD", "COMPONENT", "READY", "STATUS", "RESTARTS", "NODE", "AGE"}, values)
+	}
+	if len(description.Volumes) > 0 {
+		values := [][]string{}
+		for _, volume := range description.Volumes {
+			values = append(values, []string{volume.Name, volume.Status, volume.StorageClass, volume.Capacity, volume.Used})
+		}
+		log.PrintTable(cmd.Log, []string{"VOLUME", "STATUS", "STORAGE CLASS", "CAPACITY", "USED"}, values)
+	}
+	if len(description.Objects) > 0 {
+		values := [][]string{}
+		for _, objects := range description.Objects {
+			values = append(values, []string{objects.Kind, strconv.Itoa(objects.Count)})
+		}
+		log.PrintTable(cmd.Log, []string{"SYNCED KIND", "HOST OBJECTS"}, values)
+	}
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+
+	return strings.Join(values, ", ")
+}
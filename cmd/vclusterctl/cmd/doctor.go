@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/create"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/doctor"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+)
+
+// DoctorCmd holds the doctor cmd flags
+type DoctorCmd struct {
+	*flags.GlobalFlags
+	create.CreateOptions
+
+	Output string
+
+	Log log.Logger
+}
+
+// NewDoctorCmd creates a new command
+func NewDoctorCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DoctorCmd{
+		GlobalFlags: globalFlags,
+		CreateOptions: create.CreateOptions{
+			ChartName:    "vcluster",
+			ChartRepo:    LoftChartRepo,
+			ChartVersion: upgrade.GetVersion(),
+		},
+		Log: log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "doctor [flags] vcluster_name",
+		Short: "Checks if the host cluster is ready for a virtual cluster",
+		Long: `
+#######################################################
+#################### vcluster doctor ##################
+#######################################################
+Checks if a virtual cluster could be created with the
+given flags and values within the host cluster without
+creating anything. The checks cover the permissions the
+enabled syncers need, the apis they sync, the storage
+class of the control plane, the pod security standard
+of the namespace, the service CIDR and the local
+kubernetes distro. vcluster create runs the same checks
+before deploying the virtual cluster.
+
+Example:
+vcluster doctor test --namespace test
+vcluster doctor test --namespace test --distro k8s -f values.yaml
+#######################################################
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Distro, "distro", "k3s", fmt.Sprintf("Kubernetes distro to use for the virtual cluster. Allowed distros: %s", strings.Join(AllowedDistros, ", ")))
+	cobraCmd.Flags().StringVar(&cmd.KubernetesVersion, "kubernetes-version", "", "The kubernetes version to use (e.g. v1.20). Patch versions are not supported")
+	cobraCmd.Flags().StringSliceVarP(&cmd.ExtraValues, "extra-values", "f", []string{}, "Path where to load extra helm values from")
+	cobraCmd.Flags().StringVar(&cmd.CIDR, "cidr", "", "The service cidr of the host cluster, if empty it will be detected")
+	cobraCmd.Flags().BoolVar(&cmd.ExposeLocal, "expose-local", true, "If true and a local Kubernetes distro is detected, the vcluster would be deployed with a NodePort service")
+	cobraCmd.Flags().BoolVar(&cmd.Isolate, "isolate", false, "If true vcluster and its workloads would run in an isolated environment")
+	cobraCmd.Flags().StringVarP(&cmd.Output, "output", "o", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *DoctorCmd) Run(ctx context.Context, args []string) error {
+	if cmd.Output != "table" && cmd.Output != "json" {
+		return fmt.Errorf("unsupported output %s, please use one of: table, json", cmd.Output)
+	}
+
+	// the values are rendered the same way vcluster create would render them, but
+	// the namespace is not created and the service cidr is detected by the checks
+	createCmd := &CreateCmd{
+		GlobalFlags:   cmd.GlobalFlags,
+		CreateOptions: cmd.CreateOptions,
+		log:           log.Discard,
+	}
+	err := createCmd.loadKubeConfig()
+	if err != nil {
+		return err
+	}
+	err = createCmd.resolveNamespace(args[0])
+	if err != nil {
+		return err
+	}
+
+	results := []doctor.Result{}
+	kubernetesVersion, err := createCmd.getKubernetesVersion()
+	if err != nil {
+		results = append(results, doctor.Result{
+			Check:    "api-server",
+			Severity: doctor.SeverityError,
+			Message:  err.Error(),
+			Fix:      "Make sure the kube context is correct and `kubectl get namespaces` is working",
+		})
+	} else {
+		chartOptions, err := createCmd.ToChartOptions(kubernetesVersion)
+		if err != nil {
+			return err
+		}
+		results, err = createCmd.preflight(ctx, chartOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.Output == "json" {
+		out, err := json.MarshalIndent(results, "", "    ")
+		if err != nil {
+			return errors.Wrap(err, "json marshal results")
+		}
+		cmd.Log.WriteString(string(out) + "\n")
+	} else {
+		header := []string{"CHECK", "STATUS", "MESSAGE"}
+		rows := [][]string{}
+		for _, result := range results {
+			rows = append(rows, []string{result.Check, string(result.Severity), result.Message})
+		}
+		log.PrintTable(cmd.Log, header, rows)
+
+		for _, result := range results {
+			if result.Fix != "" {
+				cmd.Log.Infof("Fix %s: %s", result.Check, result.Fix)
+			}
+		}
+	}
+
+	if doctor.HasErrors(results) {
+		return fmt.Errorf("host cluster is not ready for vcluster %s in namespace %s", args[0], createCmd.Namespace)
+	}
+	if cmd.Output == "table" {
+		cmd.Log.Donef("Host cluster is ready for vcluster %s in namespace %s", args[0], createCmd.Namespace)
+	}
+
+	return nil
+}
+
+// logPreflightResults logs the failed checks together with their fixes
+func logPreflightResults(logger log.Logger, results []doctor.Result) {
+	for _, result := range results {
+		message := fmt.Sprintf("Preflight check %s: %s", result.Check, result.Message)
+		if result.Fix != "" {
+			message += "\n- " + result.Fix
+		}
+
+		switch result.Severity {
+		case doctor.SeverityError:
+			logger.Error(message)
+		case doctor.SeverityWarning:
+			logger.Warn(message)
+		}
+	}
+}
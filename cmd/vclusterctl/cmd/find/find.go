@@ -3,7 +3,9 @@ package find
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
@@ -99,6 +101,53 @@ func ListVClusters(ctx context.Context, context, name, namespace string) ([]VClu
 	return vclusters, nil
 }
 
+// ListVClustersInAllContexts searches all contexts of the kube config concurrently for vclusters. Each context is
+// searched for at most the given timeout, contexts that couldn't be searched are returned together with their error.
+func ListVClustersInAllContexts(ctx context.Context, name, namespace string, timeout time.Duration) ([]VCluster, map[string]error, error) {
+	_, rawConfig, err := CurrentContext()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		m             sync.Mutex
+		wg            sync.WaitGroup
+		vclusters     = []VCluster{}
+		contextErrors = map[string]error{}
+	)
+	for kubeContext := range rawConfig.Contexts {
+		wg.Add(1)
+		go func(kubeContext string) {
+			defer wg.Done()
+
+			contextCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			contextVClusters, err := findInContext(contextCtx, kubeContext, name, namespace, timeout, false)
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				contextErrors[kubeContext] = err
+				return
+			}
+
+			vclusters = append(vclusters, contextVClusters...)
+		}(kubeContext)
+	}
+	wg.Wait()
+
+	sort.SliceStable(vclusters, func(i, j int) bool {
+		if vclusters[i].Context != vclusters[j].Context {
+			return vclusters[i].Context < vclusters[j].Context
+		} else if vclusters[i].Namespace != vclusters[j].Namespace {
+			return vclusters[i].Namespace < vclusters[j].Namespace
+		}
+
+		return vclusters[i].Name < vclusters[j].Name
+	})
+	return vclusters, contextErrors, nil
+}
+
 func VClusterContextName(vClusterName string, vClusterNamespace string, currentContext string) string {
 	return "vcluster_" + vClusterName + "_" + vClusterNamespace + "_" + currentContext
 }
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/controllers/garbagecollector"
+)
+
+// GCCmd holds the gc cmd flags
+type GCCmd struct {
+	*flags.GlobalFlags
+
+	Delete      bool
+	GracePeriod int64
+	Output      string
+
+	Log log.Logger
+}
+
+// NewGCCmd creates a new command
+func NewGCCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &GCCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "gc [flags] vcluster_name",
+		Short: "Finds and deletes orphaned host objects of a virtual cluster",
+		Long: `
+#######################################################
+###################### vcluster gc ####################
+#######################################################
+Runs the garbage collector of the virtual cluster, which
+finds host objects that were synced by vcluster, but
+whose virtual object does not exist anymore. By default
+the orphaned objects are only reported, use --delete to
+delete them.
+
+Example:
+vcluster gc test --namespace test
+vcluster gc test --namespace test --delete --grace-period 0
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().BoolVar(&cmd.Delete, "delete", false, "If enabled, deletes the orphaned host objects that are orphaned for at least the grace period")
+	cobraCmd.Flags().Int64Var(&cmd.GracePeriod, "grace-period", -1, "Time in seconds a host object needs to be orphaned before it is deleted. If negative, the grace period of the virtual cluster is used")
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *GCCmd) Run(ctx context.Context, args []string) error {
+	vKubeClient, stop, err := getVClusterClient(ctx, cmd.GlobalFlags, args[0], cmd.Log)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	request := vKubeClient.Discovery().RESTClient().Post().AbsPath(garbagecollector.Path).Param("delete", strconv.FormatBool(cmd.Delete))
+	if cmd.GracePeriod >= 0 {
+		request = request.Param("gracePeriod", strconv.FormatInt(cmd.GracePeriod, 10))
+	}
+	out, err := request.DoRaw(ctx)
+	if err != nil {
+		return errors.Wrap(err, "run garbage collector")
+	}
+
+	if cmd.Output == "json" {
+		cmd.Log.WriteString(string(out) + "\n")
+		return nil
+	}
+
+	report := &garbagecollector.Report{}
+	err = json.Unmarshal(out, report)
+	if err != nil {
+		return errors.Wrap(err, "parse garbage collector report")
+	}
+
+	header := []string{"KIND", "NAMESPACE", "NAME", "VIRTUAL OBJECT", "ORPHANED SINCE", "STATUS"}
+	values := [][]string{}
+	for _, item := range report.Items {
+		virtualObject := item.VirtualName
+		if item.VirtualNamespace != "" {
+			virtualObject = item.VirtualNamespace + "/" + item.VirtualName
+		}
+
+		status := "Orphaned"
+		if item.Error != "" {
+			status = "Error: " + item.Error
+		} else if item.Deleted {
+			status = "Deleted"
+		} else if item.Renamed {
+			status = "Renamed"
+		}
+
+		values = append(values, []string{
+			item.Kind,
+			item.Namespace,
+			item.Name,
+			virtualObject,
+			time.Since(item.FirstSeen.Time).Round(time.Second).String(),
+			status,
+		})
+	}
+
+	log.PrintTable(cmd.Log, header, values)
+	for _, reportErr := range report.Errors {
+		cmd.Log.Warnf("Error collecting orphaned host objects: %s", reportErr)
+	}
+	if len(report.Items) == 0 {
+		cmd.Log.Donef("No orphaned host objects found")
+	} else if !cmd.Delete {
+		cmd.Log.Infof("Run `vcluster gc %s -n %s --delete` to delete the orphaned host objects", args[0], cmd.Namespace)
+	}
+
+	return nil
+}
@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/describe"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
 
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
@@ -24,14 +32,21 @@ type VCluster struct {
 	Context    string
 	Status     string
 	Connected  bool
+
+	// Parent is the context name of the vcluster this vcluster is nested in
+	Parent       string `json:",omitempty"`
+	Distro       string `json:",omitempty"`
+	ChartVersion string `json:",omitempty"`
 }
 
 // ListCmd holds the login cmd flags
 type ListCmd struct {
 	*flags.GlobalFlags
 
-	log    log.Logger
-	output string
+	log            log.Logger
+	output         string
+	allContexts    bool
+	contextTimeout time.Duration
 }
 
 // NewListCmd creates a new command
@@ -54,6 +69,7 @@ Example:
 vcluster list
 vcluster list --output json
 vcluster list --namespace test
+vcluster list --all-contexts --output wide
 #######################################################
 	`,
 		Args:    cobra.NoArgs,
@@ -63,13 +79,19 @@ vcluster list --namespace test
 		},
 	}
 
-	cobraCmd.Flags().StringVar(&cmd.output, "output", "table", "Choose the format of the output. [table|json]")
+	cobraCmd.Flags().StringVarP(&cmd.output, "output", "o", "table", "Choose the format of the output. [table|wide|json|yaml]")
+	cobraCmd.Flags().BoolVar(&cmd.allContexts, "all-contexts", false, "If true, all contexts of the kube config are searched for virtual clusters concurrently")
+	cobraCmd.Flags().DurationVar(&cmd.contextTimeout, "context-timeout", 10*time.Second, "The time after which searching a single context is aborted if --all-contexts is used")
 
 	return cobraCmd
 }
 
 // Run executes the functionality
 func (cmd *ListCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	if cmd.output != "table" && cmd.output != "wide" && cmd.output != "json" && cmd.output != "yaml" {
+		return fmt.Errorf("unsupported output %s, please use one of: table, wide, json, yaml", cmd.output)
+	}
+
 	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
 	if err != nil {
 		return err
@@ -85,52 +107,91 @@ func (cmd *ListCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		namespace = cmd.Namespace
 	}
 
-	vClusters, err := find.ListVClusters(cobraCmd.Context(), cmd.Context, "", namespace)
-	if err != nil {
-		return err
-	}
+	var vClusters []find.VCluster
+	if cmd.allContexts {
+		var contextErrors map[string]error
+		vClusters, contextErrors, err = find.ListVClustersInAllContexts(cobraCmd.Context(), "", namespace, cmd.contextTimeout)
+		if err != nil {
+			return err
+		}
 
-	if cmd.output == "json" {
-		var output []VCluster
-		for _, vcluster := range vClusters {
-			vclusterOutput := VCluster{
-				Name:       vcluster.Name,
-				Namespace:  vcluster.Namespace,
-				Created:    vcluster.Created.Time,
-				AgeSeconds: int(time.Since(vcluster.Created.Time).Round(time.Second).Seconds()),
-				Context:    vcluster.Context,
-				Status:     string(vcluster.Status),
-			}
-			vclusterOutput.Connected = currentContext == find.VClusterContextName(
-				vcluster.Name,
-				vcluster.Namespace,
-				vcluster.Context,
-			)
-			output = append(output, vclusterOutput)
+		unreachable := []string{}
+		for kubeContext, err := range contextErrors {
+			cmd.log.Debugf("Error searching context %s: %v", kubeContext, err)
+			unreachable = append(unreachable, kubeContext)
+		}
+		if len(unreachable) > 0 {
+			sort.Strings(unreachable)
+			cmd.log.Warnf("Skipped %d unreachable contexts: %s (use --debug for details)", len(unreachable), strings.Join(unreachable, ", "))
+		}
+	} else {
+		vClusters, err = find.ListVClusters(cobraCmd.Context(), cmd.Context, "", namespace)
+		if err != nil {
+			return err
 		}
+	}
+
+	nodes := vClusterTree(vClusters)
+	output := make([]VCluster, 0, len(nodes))
+	for _, node := range nodes {
+		vcluster := node.VCluster
+		output = append(output, VCluster{
+			Name:       vcluster.Name,
+			Namespace:  vcluster.Namespace,
+			Created:    vcluster.Created.Time,
+			AgeSeconds: int(time.Since(vcluster.Created.Time).Round(time.Second).Seconds()),
+			Context:    vcluster.Context,
+			Status:     string(vcluster.Status),
+			Connected:  currentContext == find.VClusterContextName(vcluster.Name, vcluster.Namespace, vcluster.Context),
+			Parent:     node.Parent,
+		})
+	}
+	if cmd.output != "table" {
+		cmd.addReleaseInfo(cobraCmd.Context(), nodes, output)
+	}
+
+	switch cmd.output {
+	case "json":
 		bytes, err := json.MarshalIndent(output, "", "    ")
 		if err != nil {
 			return errors.Wrap(err, "json marshal vclusters")
 		}
 		cmd.log.WriteString(string(bytes) + "\n")
-	} else {
+	case "yaml":
+		bytes, err := yaml.Marshal(output)
+		if err != nil {
+			return errors.Wrap(err, "yaml marshal vclusters")
+		}
+		cmd.log.WriteString(string(bytes))
+	default:
 		header := []string{"NAME", "NAMESPACE", "STATUS", "CONNECTED", "CREATED", "AGE", "CONTEXT"}
+		if cmd.output == "wide" {
+			header = append(header, "DISTRO", "CHART VERSION")
+		}
 		values := [][]string{}
-		for _, vcluster := range vClusters {
+		for i, vcluster := range output {
 			connected := ""
-			if currentContext == find.VClusterContextName(vcluster.Name, vcluster.Namespace, vcluster.Context) {
+			if vcluster.Connected {
 				connected = "True"
 			}
 
-			values = append(values, []string{
-				vcluster.Name,
+			name := vcluster.Name
+			if nodes[i].Depth > 0 {
+				name = strings.Repeat("  ", nodes[i].Depth-1) + "└─ " + name
+			}
+			row := []string{
+				name,
 				vcluster.Namespace,
-				string(vcluster.Status),
+				vcluster.Status,
 				connected,
-				vcluster.Created.String(),
-				time.Since(vcluster.Created.Time).Round(1 * time.Second).String(),
+				nodes[i].VCluster.Created.String(),
+				time.Since(vcluster.Created).Round(1 * time.Second).String(),
 				vcluster.Context,
-			})
+			}
+			if cmd.output == "wide" {
+				row = append(row, vcluster.Distro, vcluster.ChartVersion)
+			}
+			values = append(values, row)
 		}
 
 		log.PrintTable(cmd.log, header, values)
@@ -141,3 +202,85 @@ func (cmd *ListCmd) Run(cobraCmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// addReleaseInfo concurrently looks up the helm releases of the vclusters for their distro and chart version
+func (cmd *ListCmd) addReleaseInfo(ctx context.Context, nodes []vClusterNode, output []VCluster) {
+	wg := sync.WaitGroup{}
+	for i := range nodes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, cmd.contextTimeout)
+			defer cancel()
+
+			release, err := getRelease(ctx, &nodes[i].VCluster)
+			if err != nil {
+				cmd.log.Debugf("Error retrieving helm release of vcluster %s in namespace %s: %v", nodes[i].VCluster.Name, nodes[i].VCluster.Namespace, err)
+				return
+			} else if release == nil || release.Chart == nil || release.Chart.Metadata == nil {
+				return
+			}
+
+			output[i].Distro = describe.DistroFromChart(release.Chart.Metadata.Name)
+			output[i].ChartVersion = release.Chart.Metadata.Version
+		}(i)
+	}
+	wg.Wait()
+}
+
+func getRelease(ctx context.Context, vCluster *find.VCluster) (*helm.Release, error) {
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return helm.NewSecrets(kubeClient).Get(ctx, vCluster.Name, vCluster.Namespace)
+}
+
+type vClusterNode struct {
+	VCluster find.VCluster
+
+	// Parent is the context name of the vcluster the vcluster runs in and Depth its nesting level
+	Parent string
+	Depth  int
+}
+
+// vClusterTree orders the vclusters so that vclusters found within the context of another listed vcluster
+// directly follow that vcluster
+func vClusterTree(vClusters []find.VCluster) []vClusterNode {
+	contextNames := map[string]bool{}
+	for _, vCluster := range vClusters {
+		contextNames[find.VClusterContextName(vCluster.Name, vCluster.Namespace, vCluster.Context)] = true
+	}
+
+	roots := []find.VCluster{}
+	children := map[string][]find.VCluster{}
+	for _, vCluster := range vClusters {
+		if contextNames[vCluster.Context] {
+			children[vCluster.Context] = append(children[vCluster.Context], vCluster)
+		} else {
+			roots = append(roots, vCluster)
+		}
+	}
+
+	nodes := []vClusterNode{}
+	var add func(vCluster find.VCluster, parent string, depth int)
+	add = func(vCluster find.VCluster, parent string, depth int) {
+		nodes = append(nodes, vClusterNode{VCluster: vCluster, Parent: parent, Depth: depth})
+
+		contextName := find.VClusterContextName(vCluster.Name, vCluster.Namespace, vCluster.Context)
+		for _, child := range children[contextName] {
+			add(child, contextName, depth+1)
+		}
+	}
+	for _, root := range roots {
+		add(root, "", 0)
+	}
+
+	return nodes
+}
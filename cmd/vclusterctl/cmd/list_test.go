@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"gotest.tools/v3/assert"
+)
+
+func TestVClusterTree(t *testing.T) {
+	vClusters := []find.VCluster{
+		{Name: "a", Namespace: "vcluster-a", Context: "kind"},
+		{Name: "nested", Namespace: "default", Context: "vcluster_b_vcluster-b_kind"},
+		{Name: "b", Namespace: "vcluster-b", Context: "kind"},
+		{Name: "deeply-nested", Namespace: "default", Context: "vcluster_nested_default_vcluster_b_vcluster-b_kind"},
+		{Name: "orphan", Namespace: "default", Context: "vcluster_missing_default_kind"},
+		{Name: "c", Namespace: "vcluster-c", Context: "minikube"},
+	}
+
+	names := []string{}
+	depths := []int{}
+	parents := []string{}
+	for _, node := range vClusterTree(vClusters) {
+		names = append(names, node.VCluster.Name)
+		depths = append(depths, node.Depth)
+		parents = append(parents, node.Parent)
+	}
+
+	assert.DeepEqual(t, names, []string{"a", "b", "nested", "deeply-nested", "orphan", "c"})
+	assert.DeepEqual(t, depths, []int{0, 0, 1, 2, 0, 0})
+	assert.DeepEqual(t, parents, []string{"", "", "vcluster_b_vcluster-b_kind", "vcluster_nested_default_vcluster_b_vcluster-b_kind", "", ""})
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/proxydaemon"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+)
+
+// NewProxiesCmd creates a new command
+func NewProxiesCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	proxiesCmd := &cobra.Command{
+		Use:   "proxies",
+		Short: "Manages the port-forwardings of the vcluster proxy daemon",
+		Long: `
+#######################################################
+################## vcluster proxies ###################
+#######################################################
+The vcluster proxy daemon is started by
+vcluster connect --daemon and keeps the port-forwardings
+to the virtual clusters alive in the background.
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	proxiesCmd.AddCommand(NewProxiesListCmd(globalFlags))
+	proxiesCmd.AddCommand(NewProxiesStopCmd(globalFlags))
+	proxiesCmd.AddCommand(NewProxiesDaemonCmd())
+	return proxiesCmd
+}
+
+// ProxiesListCmd holds the proxies list cmd flags
+type ProxiesListCmd struct {
+	*flags.GlobalFlags
+
+	Output string
+
+	Log log.Logger
+}
+
+// NewProxiesListCmd creates a new command
+func NewProxiesListCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ProxiesListCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the port-forwardings of the vcluster proxy daemon",
+		Long: `
+#######################################################
+############### vcluster proxies list #################
+#######################################################
+Lists the port-forwardings of the vcluster proxy daemon
+
+Example:
+vcluster proxies list
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *ProxiesListCmd) Run(ctx context.Context) error {
+	statuses := []proxydaemon.Status{}
+	client, err := newProxyDaemonClient()
+	if err != nil {
+		return err
+	} else if client != nil {
+		statuses, err = client.List(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.Output == "json" {
+		out, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		cmd.Log.WriteString(string(out) + "\n")
+		return nil
+	}
+	if len(statuses) == 0 {
+		cmd.Log.Info("No port-forwardings are running")
+		return nil
+	}
+
+	header := []string{"NAME", "NAMESPACE", "CONTEXT", "LOCAL PORT", "POD", "STATUS", "RECONNECTS", "SINCE", "LAST ERROR"}
+	values := [][]string{}
+	for _, status := range statuses {
+		values = append(values, []string{
+			status.Name,
+			status.Namespace,
+			status.Context,
+			strconv.Itoa(status.LocalPort),
+			status.Pod,
+			string(status.Phase),
+			strconv.Itoa(status.Reconnects),
+			time.Since(status.Since).Round(time.Second).String(),
+			status.LastError,
+		})
+	}
+
+	log.PrintTable(cmd.Log, header, values)
+	return nil
+}
+
+// ProxiesStopCmd holds the proxies stop cmd flags
+type ProxiesStopCmd struct {
+	*flags.GlobalFlags
+
+	All bool
+
+	Log log.Logger
+}
+
+// NewProxiesStopCmd creates a new command
+func NewProxiesStopCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ProxiesStopCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "stop [flags] [vcluster_name]",
+		Short: "Stops port-forwardings of the vcluster proxy daemon",
+		Long: `
+#######################################################
+############### vcluster proxies stop #################
+#######################################################
+Stops the port-forwardings of the given virtual cluster.
+The daemon stops as soon as the last port-forwarding
+is stopped.
+
+Example:
+vcluster proxies stop test --namespace test
+vcluster proxies stop --all
+#######################################################
+	`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().BoolVar(&cmd.All, "all", false, "If enabled, stops all port-forwardings")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *ProxiesStopCmd) Run(ctx context.Context, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	} else if !cmd.All {
+		return fmt.Errorf("please specify either a vcluster name or --all")
+	}
+
+	client, err := newProxyDaemonClient()
+	if err != nil {
+		return err
+	} else if client == nil {
+		cmd.Log.Info("No port-forwardings are running")
+		return nil
+	}
+
+	stopped, err := client.Stop(ctx, name, cmd.Namespace, cmd.Context)
+	if err != nil {
+		return err
+	} else if len(stopped) == 0 {
+		return fmt.Errorf("couldn't find a port-forwarding for vcluster %s", name)
+	}
+
+	for _, status := range stopped {
+		cmd.Log.Donef("Stopped port-forwarding for vcluster %s in namespace %s on port %d", status.Name, status.Namespace, status.LocalPort)
+	}
+
+	return nil
+}
+
+// NewProxiesDaemonCmd creates a new command
+func NewProxiesDaemonCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "daemon",
+		Short:  "Runs the vcluster proxy daemon in the foreground",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cobraCmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			socketPath, err := proxydaemon.SocketPath()
+			if err != nil {
+				return err
+			}
+			listener, err := proxydaemon.Listen(socketPath)
+			if err != nil {
+				return err
+			}
+
+			logger := log.GetInstance()
+			server := &proxydaemon.Server{
+				Forwarder:     &proxydaemon.PortForwarder{Log: logger},
+				Log:           logger,
+				RetryInterval: time.Second * 2,
+			}
+
+			logger.Infof("Proxy daemon listening on %s", socketPath)
+			return server.Serve(ctx, listener)
+		},
+	}
+}
+
+// newProxyDaemonClient returns a client for the running proxy daemon or nil if the daemon is not running
+func newProxyDaemonClient() (*proxydaemon.Client, error) {
+	socketPath, err := proxydaemon.SocketPath()
+	if err != nil {
+		return nil, err
+	}
+
+	client := proxydaemon.NewClient(socketPath)
+	_, err = client.List(context.Background())
+	if err != nil {
+		return nil, nil
+	}
+
+	return client, nil
+}
@@ -66,12 +66,22 @@ func BuildRoot(log log.Logger) (*cobra.Command, error) {
 
 	// add top level commands
 	rootCmd.AddCommand(NewConnectCmd(globalFlags))
+	rootCmd.AddCommand(NewCredentialsCmd(globalFlags))
 	rootCmd.AddCommand(NewCreateCmd(globalFlags))
+	rootCmd.AddCommand(NewApplyCmd(globalFlags))
+	rootCmd.AddCommand(NewDoctorCmd(globalFlags))
+	rootCmd.AddCommand(NewBundleCmd(globalFlags))
 	rootCmd.AddCommand(NewListCmd(globalFlags))
+	rootCmd.AddCommand(NewDescribeCmd(globalFlags))
 	rootCmd.AddCommand(NewDeleteCmd(globalFlags))
 	rootCmd.AddCommand(NewPauseCmd(globalFlags))
 	rootCmd.AddCommand(NewResumeCmd(globalFlags))
 	rootCmd.AddCommand(NewDisconnectCmd(globalFlags))
+	rootCmd.AddCommand(NewTranslateCmd(globalFlags))
+	rootCmd.AddCommand(NewGCCmd(globalFlags))
+	rootCmd.AddCommand(NewSyncCmd(globalFlags))
+	rootCmd.AddCommand(NewDebugCmd(globalFlags))
+	rootCmd.AddCommand(NewProxiesCmd(globalFlags))
 	rootCmd.AddCommand(NewUpgradeCmd())
 	rootCmd.AddCommand(get.NewGetCmd(globalFlags))
 	rootCmd.AddCommand(telemetry.NewTelemetryCmd())
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncstatus"
+)
+
+// NewSyncCmd creates a new command
+func NewSyncCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Inspects the synchronization of a virtual cluster",
+		Long: `
+#######################################################
+#################### vcluster sync ####################
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	syncCmd.AddCommand(NewSyncStatusCmd(globalFlags))
+	return syncCmd
+}
+
+// SyncStatusCmd holds the sync status cmd flags
+type SyncStatusCmd struct {
+	*flags.GlobalFlags
+
+	Output string
+
+	Log log.Logger
+}
+
+// NewSyncStatusCmd creates a new command
+func NewSyncStatusCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &SyncStatusCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "status [flags] vcluster_name",
+		Short: "Shows the virtual objects that fail to sync or are paused",
+		Long: `
+#######################################################
+################ vcluster sync status #################
+#######################################################
+Shows all virtual objects across all syncers that
+currently fail to sync to the host cluster, e.g. because
+of a quota or an admission webhook in the host cluster,
+or whose syncing is paused through the
+vcluster.loft.sh/pause-sync annotation. The status of a single object can be found in its
+vcluster.loft.sh/sync-status annotation.
+
+Example:
+vcluster sync status test --namespace test
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *SyncStatusCmd) Run(ctx context.Context, args []string) error {
+	vKubeClient, stop, err := getVClusterClient(ctx, cmd.GlobalFlags, args[0], cmd.Log)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	out, err := vKubeClient.Discovery().RESTClient().Get().AbsPath(syncstatus.Path).DoRaw(ctx)
+	if err != nil {
+		return errors.Wrap(err, "retrieve sync status")
+	}
+
+	if cmd.Output == "json" {
+		cmd.Log.WriteString(string(out) + "\n")
+		return nil
+	}
+
+	report := &syncstatus.Report{}
+	err = json.Unmarshal(out, report)
+	if err != nil {
+		return errors.Wrap(err, "parse sync status")
+	}
+	if len(report.Items) == 0 {
+		cmd.Log.Donef("All objects are in sync")
+		return nil
+	}
+
+	header := []string{"SYNCER", "KIND", "NAMESPACE", "NAME", "HOST OBJECT", "PHASE", "SINCE", "ERROR"}
+	values := [][]string{}
+	for _, item := range report.Items {
+		values = append(values, []string{
+			item.Status.Syncer,
+			item.Kind,
+			item.Namespace,
+			item.Name,
+			item.Status.HostName,
+			string(item.Status.Phase),
+			time.Since(item.Status.LastTransitionTime.Time).Round(time.Second).String(),
+			item.Status.LastError,
+		})
+	}
+
+	log.PrintTable(cmd.Log, header, values)
+	return nil
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// TranslateCmd holds the translate cmd flags
+type TranslateCmd struct {
+	*flags.GlobalFlags
+
+	VirtualNamespace string
+	ToVirtual        bool
+
+	Log log.Logger
+}
+
+// NewTranslateCmd creates a new command
+func NewTranslateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &TranslateCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "translate [flags] vcluster_name resource name",
+		Short: "Translates names between the virtual and host cluster",
+		Long: `
+#######################################################
+################## vcluster translate #################
+#######################################################
+Translates the name of a synced object from the virtual
+cluster to the host cluster or the other way around. The
+name is resolved by looking up the synced object within
+the host cluster, so this works with any name template.
+Multi-namespace mode is not supported, as objects keep
+their names there.
+
+Example:
+vcluster translate test pod nginx --virtual-namespace default -n test
+vcluster translate test pod nginx-x-default-x-test --to-virtual -n test
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.VirtualNamespace, "virtual-namespace", "default", "The namespace of the object within the virtual cluster")
+	cobraCmd.Flags().BoolVar(&cmd.ToVirtual, "to-virtual", false, "If enabled, translates the given host name into the virtual name")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *TranslateCmd) Run(ctx context.Context, args []string) error {
+	vCluster, err := find.GetVCluster(ctx, cmd.Context, args[0], cmd.Namespace)
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	mapping, err := findRESTMapping(restMapper, args[1])
+	if err != nil {
+		return err
+	}
+
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	var result types.NamespacedName
+	if cmd.ToVirtual {
+		result, err = TranslateToVirtual(ctx, dynamicClient, mapping.Resource, namespaced, vCluster.Namespace, vCluster.Name, args[2])
+	} else {
+		if !namespaced {
+			cmd.VirtualNamespace = ""
+		}
+		result, err = TranslateToHost(ctx, dynamicClient, mapping.Resource, namespaced, vCluster.Namespace, vCluster.Name, cmd.VirtualNamespace, args[2])
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = cmd.Log.Write([]byte(result.String() + "\n"))
+	return err
+}
+
+func findRESTMapping(restMapper meta.RESTMapper, resource string) (*meta.RESTMapping, error) {
+	gvr, err := restMapper.ResourceFor(schema.ParseGroupResource(resource).WithVersion(""))
+	if err != nil {
+		return nil, errors.Wrapf(err, "find resource %s", resource)
+	}
+
+	gvk, err := restMapper.KindFor(gvr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find kind for resource %s", resource)
+	}
+
+	return restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// TranslateToVirtual resolves the virtual name of the given host object by its annotations
+func TranslateToVirtual(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespaced bool, hostNamespace, vClusterName, hostName string) (types.NamespacedName, error) {
+	var (
+		obj *unstructured.Unstructured
+		err error
+	)
+	if namespaced {
+		obj, err = client.Resource(gvr).Namespace(hostNamespace).Get(ctx, hostName, metav1.GetOptions{})
+	} else {
+		obj, err = client.Resource(gvr).Get(ctx, hostName, metav1.GetOptions{})
+	}
+	if err != nil {
+		return types.NamespacedName{}, err
+	}
+
+	if !isSyncedBy(obj, hostNamespace, vClusterName) {
+		return types.NamespacedName{}, fmt.Errorf("%s %s was not synced by vcluster %s", gvr.Resource, hostName, vClusterName)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil || annotations[translate.NameAnnotation] == "" {
+		return types.NamespacedName{}, fmt.Errorf("%s %s is missing the %s annotation", gvr.Resource, hostName, translate.NameAnnotation)
+	}
+
+	return types.NamespacedName{
+		Namespace: annotations[translate.NamespaceAnnotation],
+		Name:      annotations[translate.NameAnnotation],
+	}, nil
+}
+
+// TranslateToHost resolves the host name of the given virtual object by searching the synced host
+// objects for the one that points back to the virtual object
+func TranslateToHost(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespaced bool, hostNamespace, vClusterName, virtualNamespace, virtualName string) (types.NamespacedName, error) {
+	var (
+		list *unstructured.UnstructuredList
+		err  error
+	)
+	if namespaced {
+		list, err = client.Resource(gvr).Namespace(hostNamespace).List(ctx, metav1.ListOptions{LabelSelector: translate.MarkerLabel + "=" + vClusterName})
+	} else {
+		list, err = client.Resource(gvr).List(ctx, metav1.ListOptions{})
+	}
+	if err != nil {
+		return types.NamespacedName{}, err
+	}
+
+	found := findSyncedObjects(list, hostNamespace, vClusterName, virtualNamespace, virtualName)
+	if len(found) == 0 {
+		return types.NamespacedName{}, kerrors.NewNotFound(gvr.GroupResource(), virtualName)
+	} else if len(found) > 1 {
+		return types.NamespacedName{}, fmt.Errorf("found multiple host objects for %s %s", gvr.Resource, virtualName)
+	}
+
+	return types.NamespacedName{
+		Namespace: found[0].GetNamespace(),
+		Name:      found[0].GetName(),
+	}, nil
+}
+
+func findSyncedObjects(list *unstructured.UnstructuredList, hostNamespace, vClusterName, virtualNamespace, virtualName string) []unstructured.Unstructured {
+	found := []unstructured.Unstructured{}
+	for _, item := range list.Items {
+		annotations := item.GetAnnotations()
+		if annotations == nil || annotations[translate.NameAnnotation] != virtualName || annotations[translate.NamespaceAnnotation] != virtualNamespace {
+			continue
+		} else if !isSyncedBy(&item, hostNamespace, vClusterName) {
+			continue
+		}
+
+		found = append(found, item)
+	}
+
+	return found
+}
+
+func isSyncedBy(obj *unstructured.Unstructured, hostNamespace, vClusterName string) bool {
+	labels := obj.GetLabels()
+	if labels == nil {
+		return false
+	}
+
+	// cluster scoped objects are marked with the namespace and name of the vcluster
+	return labels[translate.MarkerLabel] == vClusterName || labels[translate.MarkerLabel] == translate.SafeConcatName(hostNamespace, "x", vClusterName)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestTranslate(t *testing.T) {
+	newPod := func(name, namespace, vClusterName, vName, vNamespace string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					translate.MarkerLabel: vClusterName,
+				},
+				Annotations: map[string]string{
+					translate.NameAnnotation:      vName,
+					translate.NamespaceAnnotation: vNamespace,
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		newPod("default-nginx", "test", "vcluster", "nginx", "default"),
+		newPod("other-nginx", "test", "vcluster", "nginx", "other"),
+		newPod("default-nginx-2", "test", "other-vcluster", "nginx", "default"),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "test"}},
+	}
+	gvr := corev1.SchemeGroupVersion.WithResource("pods")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, nil, objects...)
+
+	hostName, err := TranslateToHost(context.TODO(), client, gvr, true, "test", "vcluster", "default", "nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, hostName, types.NamespacedName{Namespace: "test", Name: "default-nginx"})
+
+	_, err = TranslateToHost(context.TODO(), client, gvr, true, "test", "vcluster", "default", "missing")
+	assert.ErrorContains(t, err, "not found")
+
+	virtualName, err := TranslateToVirtual(context.TODO(), client, gvr, true, "test", "vcluster", "other-nginx")
+	assert.NilError(t, err)
+	assert.Equal(t, virtualName, types.NamespacedName{Namespace: "other", Name: "nginx"})
+
+	_, err = TranslateToVirtual(context.TODO(), client, gvr, true, "test", "vcluster", "unmanaged")
+	assert.ErrorContains(t, err, "was not synced by vcluster")
+}
@@ -11,6 +11,8 @@ type GlobalFlags struct {
 	Config    string
 	Context   string
 	Namespace string
+
+	HelmClient string
 }
 
 // SetGlobalFlags applies the global flags
@@ -21,6 +23,7 @@ func SetGlobalFlags(flags *flag.FlagSet) *GlobalFlags {
 	flags.StringVar(&globalFlags.Context, "context", "", "The kubernetes config context to use")
 	flags.StringVarP(&globalFlags.Namespace, "namespace", "n", "", "The kubernetes namespace to use")
 	flags.BoolVarP(&globalFlags.Silent, "silent", "s", false, "Run in silent mode and prevents any vcluster log output except panics & fatals")
+	flags.StringVar(&globalFlags.HelmClient, "helm-client", "binary", "The helm client to use, either binary (executes the helm binary and downloads it if it's not installed) or sdk (runs helm in-process without the helm binary)")
 
 	return globalFlags
 }
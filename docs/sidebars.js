@@ -63,6 +63,7 @@ module.exports = {
         'operator/backup',
         'operator/security',
         'operator/cluster-api-provider',
+        'operator/declarative-vclusters',
       ],
     },
     {
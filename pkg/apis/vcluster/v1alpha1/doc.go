@@ -0,0 +1,5 @@
+// +groupName=vcluster.loft.sh
+
+// Package v1alpha1 contains the declarative VCluster spec that is applied
+// by vcluster apply. It is only read by the CLI and not served by any api server.
+package v1alpha1
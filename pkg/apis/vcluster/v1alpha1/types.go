@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"github.com/loft-sh/vcluster/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the VCluster spec
+var SchemeGroupVersion = schema.GroupVersion{Group: "vcluster.loft.sh", Version: "v1alpha1"}
+
+const Kind = "VCluster"
+
+// VCluster describes the desired state of a virtual cluster
+type VCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VClusterSpec `json:"spec,omitempty"`
+}
+
+type VClusterSpec struct {
+	// Distro is the kubernetes distro of the virtual cluster, one of k3s, k0s, k8s or eks. Defaults to k3s.
+	Distro string `json:"distro,omitempty"`
+
+	// KubernetesVersion is the major.minor kubernetes version of the virtual cluster, e.g. v1.27.
+	// Defaults to the version of the host cluster.
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Chart is the chart the virtual cluster is deployed with
+	Chart ChartSpec `json:"chart,omitempty"`
+
+	// Expose creates a load balancer service for the virtual cluster
+	Expose bool `json:"expose,omitempty"`
+
+	// Isolation runs the virtual cluster and its workloads in an isolated environment
+	Isolation IsolationSpec `json:"isolation,omitempty"`
+
+	// Sync configures the syncers of the virtual cluster
+	Sync SyncSpec `json:"sync,omitempty"`
+
+	// Init configures what is deployed into the virtual cluster when it starts
+	Init InitSpec `json:"init,omitempty"`
+
+	// Values are additional chart values, which take precedence over all other fields
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+type ChartSpec struct {
+	// Name of the chart. Defaults to vcluster, or vcluster-DISTRO for other distros than k3s.
+	Name string `json:"name,omitempty"`
+
+	// Repo of the chart. Defaults to https://charts.loft.sh
+	Repo string `json:"repo,omitempty"`
+
+	// Version of the chart. Defaults to the version of the cli.
+	Version string `json:"version,omitempty"`
+}
+
+type IsolationSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+type SyncSpec struct {
+	// Resources enables or disables the syncers by the name of their chart value, e.g. ingresses or nodes
+	Resources map[string]bool `json:"resources,omitempty"`
+
+	// Generic is the generic sync configuration for custom resources
+	Generic *config.Config `json:"generic,omitempty"`
+}
+
+type InitSpec struct {
+	// Manifests are applied in the virtual cluster when it starts
+	Manifests string `json:"manifests,omitempty"`
+}
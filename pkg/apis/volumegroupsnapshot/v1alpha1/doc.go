@@ -0,0 +1,8 @@
+// +k8s:deepcopy-gen=package
+// +groupName=groupsnapshot.storage.k8s.io
+
+// Package v1alpha1 contains the VolumeGroupSnapshot API types of the
+// groupsnapshot.storage.k8s.io group. The types mirror the upstream
+// external-snapshotter v1alpha1 API, which is not available in the
+// snapshot client version vcluster depends on.
+package v1alpha1
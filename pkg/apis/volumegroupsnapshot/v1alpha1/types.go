@@ -0,0 +1,231 @@
+package v1alpha1
+
+import (
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshot is a user's request for creating either a point-in-time
+// group snapshot or binding to a pre-existing group snapshot.
+type VolumeGroupSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired characteristics of a group snapshot requested by a user.
+	// Required.
+	Spec VolumeGroupSnapshotSpec `json:"spec"`
+
+	// Status represents the current information of a group snapshot.
+	// +optional
+	Status *VolumeGroupSnapshotStatus `json:"status,omitempty"`
+}
+
+// VolumeGroupSnapshotSpec describes the common attributes of group storage devices
+// and allows a Source for provider-specific attributes
+type VolumeGroupSnapshotSpec struct {
+	// Source specifies where a group snapshot will be created from.
+	// Required.
+	Source VolumeGroupSnapshotSource `json:"source"`
+
+	// VolumeGroupSnapshotClassName is the name of the VolumeGroupSnapshotClass
+	// requested by the VolumeGroupSnapshot.
+	// +optional
+	VolumeGroupSnapshotClassName *string `json:"volumeGroupSnapshotClassName,omitempty"`
+}
+
+// VolumeGroupSnapshotSource specifies whether the underlying group snapshot should be
+// dynamically taken upon creation or if a pre-existing VolumeGroupSnapshotContent
+// object should be used.
+// Exactly one of its members must be set.
+type VolumeGroupSnapshotSource struct {
+	// Selector is a label query over persistent volume claims that are to be
+	// grouped together for snapshotting.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// VolumeGroupSnapshotContentName specifies the name of a pre-existing VolumeGroupSnapshotContent
+	// object representing an existing volume group snapshot.
+	// +optional
+	VolumeGroupSnapshotContentName *string `json:"volumeGroupSnapshotContentName,omitempty"`
+}
+
+// VolumeGroupSnapshotStatus defines the observed state of volume group snapshot.
+type VolumeGroupSnapshotStatus struct {
+	// BoundVolumeGroupSnapshotContentName is the name of the VolumeGroupSnapshotContent
+	// object to which this VolumeGroupSnapshot object intends to bind to.
+	// +optional
+	BoundVolumeGroupSnapshotContentName *string `json:"boundVolumeGroupSnapshotContentName,omitempty"`
+
+	// CreationTime is the timestamp when the point-in-time group snapshot is taken
+	// by the underlying storage system.
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+
+	// ReadyToUse indicates if all the individual snapshots in the group are ready
+	// to be used to restore a group of volumes.
+	// +optional
+	ReadyToUse *bool `json:"readyToUse,omitempty"`
+
+	// Error is the last observed error during group snapshot creation, if any.
+	// +optional
+	Error *volumesnapshotv1.VolumeSnapshotError `json:"error,omitempty"`
+
+	// VolumeSnapshotRefList is the list of volume snapshot references for this
+	// group snapshot.
+	// +optional
+	VolumeSnapshotRefList []corev1.ObjectReference `json:"volumeSnapshotRefList,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotList contains a list of VolumeGroupSnapshot objects.
+type VolumeGroupSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of volume group snapshots.
+	Items []VolumeGroupSnapshot `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotClass specifies parameters that a underlying storage system
+// uses when creating a volume group snapshot. VolumeGroupSnapshotClasses are non-namespaced.
+type VolumeGroupSnapshotClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Driver is the name of the storage driver expected to handle this VolumeGroupSnapshotClass.
+	// Required.
+	Driver string `json:"driver"`
+
+	// Parameters is a key-value map with storage driver specific parameters for
+	// creating group snapshots.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// DeletionPolicy determines whether a VolumeGroupSnapshotContent created
+	// through the VolumeGroupSnapshotClass should be deleted when its bound
+	// VolumeGroupSnapshot is deleted.
+	// Required.
+	DeletionPolicy volumesnapshotv1.DeletionPolicy `json:"deletionPolicy"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotClassList is a collection of VolumeGroupSnapshotClasses.
+type VolumeGroupSnapshotClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of VolumeGroupSnapshotClasses.
+	Items []VolumeGroupSnapshotClass `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotContent represents the actual "on-disk" group snapshot object
+// in the underlying storage system
+type VolumeGroupSnapshotContent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines properties of a VolumeGroupSnapshotContent created by the underlying storage system.
+	// Required.
+	Spec VolumeGroupSnapshotContentSpec `json:"spec"`
+
+	// Status represents the current information of a group snapshot.
+	// +optional
+	Status *VolumeGroupSnapshotContentStatus `json:"status,omitempty"`
+}
+
+// VolumeGroupSnapshotContentSpec describes the common attributes of a group snapshot content
+type VolumeGroupSnapshotContentSpec struct {
+	// VolumeGroupSnapshotRef specifies the VolumeGroupSnapshot object to which this
+	// VolumeGroupSnapshotContent object is bound.
+	// Required.
+	VolumeGroupSnapshotRef corev1.ObjectReference `json:"volumeGroupSnapshotRef"`
+
+	// DeletionPolicy determines whether this VolumeGroupSnapshotContent and the
+	// physical group snapshot on the underlying storage system should be deleted
+	// when the bound VolumeGroupSnapshot is deleted.
+	// Required.
+	DeletionPolicy volumesnapshotv1.DeletionPolicy `json:"deletionPolicy"`
+
+	// Driver is the name of the CSI driver used to create the physical group snapshot on
+	// the underlying storage system.
+	// Required.
+	Driver string `json:"driver"`
+
+	// VolumeGroupSnapshotClassName is the name of the VolumeGroupSnapshotClass from
+	// which this group snapshot was (or will be) created.
+	// +optional
+	VolumeGroupSnapshotClassName *string `json:"volumeGroupSnapshotClassName,omitempty"`
+
+	// Source specifies whether the snapshot is (or should be) dynamically provisioned
+	// or already exists, and just requires a Kubernetes object representation.
+	// Required.
+	Source VolumeGroupSnapshotContentSource `json:"source"`
+}
+
+// VolumeGroupSnapshotContentSource represents the CSI source of a group snapshot.
+// Exactly one of its members must be set.
+type VolumeGroupSnapshotContentSource struct {
+	// PersistentVolumeNames is a list of names of PersistentVolumes to be snapshotted
+	// together. This field should be set if the group snapshot does not exist and
+	// needs to be created.
+	// +optional
+	PersistentVolumeNames []string `json:"persistentVolumeNames,omitempty"`
+
+	// VolumeGroupSnapshotHandle specifies the CSI "group_snapshot_id" of a pre-existing
+	// group snapshot on the underlying storage system for which a Kubernetes object
+	// representation was (or should be) created.
+	// +optional
+	VolumeGroupSnapshotHandle *string `json:"volumeGroupSnapshotHandle,omitempty"`
+}
+
+// VolumeGroupSnapshotContentStatus defines the observed state of a VolumeGroupSnapshotContent.
+type VolumeGroupSnapshotContentStatus struct {
+	// VolumeGroupSnapshotHandle is a unique id returned by the CSI driver
+	// to identify the VolumeGroupSnapshot on the storage system.
+	// +optional
+	VolumeGroupSnapshotHandle *string `json:"volumeGroupSnapshotHandle,omitempty"`
+
+	// CreationTime is the timestamp when the point-in-time group snapshot is taken
+	// by the underlying storage system.
+	// +optional
+	CreationTime *int64 `json:"creationTime,omitempty"`
+
+	// ReadyToUse indicates if all the individual snapshots in the group are ready to be
+	// used to restore a group of volumes.
+	// +optional
+	ReadyToUse *bool `json:"readyToUse,omitempty"`
+
+	// Error is the last observed error during group snapshot creation, if any.
+	// +optional
+	Error *volumesnapshotv1.VolumeSnapshotError `json:"error,omitempty"`
+
+	// VolumeSnapshotContentRefList is the list of volume snapshot content references
+	// for this group snapshot.
+	// +optional
+	VolumeSnapshotContentRefList []corev1.ObjectReference `json:"volumeSnapshotContentRefList,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotContentList is a list of VolumeGroupSnapshotContent objects
+type VolumeGroupSnapshotContentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of VolumeGroupSnapshotContents.
+	Items []VolumeGroupSnapshotContent `json:"items"`
+}
@@ -4,10 +4,22 @@ const (
 	SkipTranslationAnnotation = "vcluster.loft.sh/skip-translate"
 	SyncResourceAnnotation    = "vcluster.loft.sh/force-sync"
 
+	// SyncStatusAnnotation holds the json encoded sync status of a virtual object
+	SyncStatusAnnotation = "vcluster.loft.sh/sync-status"
+
+	// PauseSyncAnnotation pauses the syncing of a virtual object or all objects of a virtual namespace if set to true
+	PauseSyncAnnotation = "vcluster.loft.sh/pause-sync"
+
 	PausedAnnotation         = "loft.sh/paused"
 	PausedReplicasAnnotation = "loft.sh/paused-replicas"
 	PausedDateAnnotation     = "loft.sh/paused-date"
 
+	// SleepReasonAnnotation is set next to the paused annotations if the vcluster was put to sleep automatically
+	SleepReasonAnnotation = "loft.sh/sleep-reason"
+
+	// SleepSelectorAnnotation holds the original selector of the vcluster service while the vcluster sleeps
+	SleepSelectorAnnotation = "loft.sh/sleep-selector"
+
 	// NodeSuffix is the dns suffix for our nodes
 	NodeSuffix = "nodes.vcluster.com"
 
@@ -0,0 +1,518 @@
+package garbagecollector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Path is where the syncer serves the garbage collector within the virtual cluster api
+const Path = "/vcluster/gc"
+
+// DefaultResources are the namespaced host resources vcluster syncs to. They are always collected, so
+// leftovers of syncers that were enabled previously are found as well.
+var DefaultResources = []schema.GroupVersionKind{
+	corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+	corev1.SchemeGroupVersion.WithKind("Secret"),
+	corev1.SchemeGroupVersion.WithKind("Service"),
+	corev1.SchemeGroupVersion.WithKind("Endpoints"),
+	corev1.SchemeGroupVersion.WithKind("Pod"),
+	corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"),
+	corev1.SchemeGroupVersion.WithKind("ServiceAccount"),
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+	{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"},
+	{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"},
+}
+
+var defaultCollector atomic.Pointer[Collector]
+
+// Default returns the garbage collector of the syncer or nil if it was not started yet,
+// e.g. because this replica is not the leader
+func Default() *Collector {
+	return defaultCollector.Load()
+}
+
+// SetDefault sets the garbage collector that is returned by Default
+func SetDefault(collector *Collector) {
+	defaultCollector.Store(collector)
+}
+
+// Resource is a host resource the garbage collector looks for orphans of
+type Resource struct {
+	GroupVersionKind schema.GroupVersionKind
+
+	// Translator is the name translator of the enabled syncer for the resource. If nil,
+	// the virtual object is resolved through the name annotations of the host object.
+	Translator translator.NameTranslator
+}
+
+// RunOptions configure a single garbage collection run
+type RunOptions struct {
+	// Delete deletes orphans that were found for at least the grace period
+	Delete bool
+
+	// GracePeriod is how long a host object needs to be an orphan before it is deleted
+	GracePeriod time.Duration
+}
+
+// Report is the result of a garbage collection run
+type Report struct {
+	Delete              bool        `json:"delete"`
+	GracePeriodSeconds  int64       `json:"gracePeriodSeconds"`
+	StartTimestamp      metav1.Time `json:"startTimestamp"`
+	CompletionTimestamp metav1.Time `json:"completionTimestamp"`
+
+	Items  []*Orphan `json:"items"`
+	Errors []string  `json:"errors,omitempty"`
+}
+
+// Orphan is a managed host object without a virtual counterpart
+type Orphan struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+
+	VirtualNamespace string `json:"virtualNamespace,omitempty"`
+	VirtualName      string `json:"virtualName"`
+
+	// Renamed is true if the virtual object still exists, but is synced to a host object with a
+	// different name, e.g. because the name template was changed
+	Renamed bool `json:"renamed,omitempty"`
+
+	// FirstSeen is when the garbage collector found the host object as orphan for the first time
+	FirstSeen metav1.Time `json:"firstSeen"`
+
+	// Deleted is true if the host object was deleted in this run
+	Deleted bool   `json:"deleted,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Collector finds managed host objects whose virtual object does not exist anymore. This happens if
+// the syncer crashes during a delete or a syncer is disabled while objects are still synced. Host objects
+// that were synced under a previous name template are reported as well.
+type Collector struct {
+	// HostReader and VirtualReader should be uncached, so the collector doesn't start informers
+	// for all resources and doesn't act on stale caches
+	HostReader    client.Reader
+	HostClient    client.Client
+	VirtualReader client.Reader
+	Log           loghelper.Logger
+
+	Resources          []Resource
+	TargetNamespace    string
+	MultiNamespaceMode bool
+
+	// Interval is the period of the background runs, Options are used for them
+	Interval time.Duration
+	Options  RunOptions
+
+	m          sync.Mutex
+	firstSeen  map[string]metav1.Time
+	lastReport *Report
+	now        func() time.Time
+}
+
+// NewFromContext creates a garbage collector for the default resources and the given syncers
+func NewFromContext(ctx *synccontext.RegisterContext, syncers []syncer.Object) (*Collector, error) {
+	resources, err := resourcesFromSyncers(ctx, syncers)
+	if err != nil {
+		return nil, err
+	}
+
+	hostClient, err := client.New(ctx.PhysicalManager.GetConfig(), client.Options{
+		Scheme: ctx.PhysicalManager.GetScheme(),
+		Mapper: ctx.PhysicalManager.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Collector{
+		HostReader:    ctx.PhysicalManager.GetAPIReader(),
+		HostClient:    hostClient,
+		VirtualReader: ctx.VirtualManager.GetAPIReader(),
+		Log:           loghelper.New("garbage-collector"),
+
+		Resources:          resources,
+		TargetNamespace:    ctx.Options.TargetNamespace,
+		MultiNamespaceMode: ctx.Options.MultiNamespaceMode,
+
+		Interval: time.Duration(ctx.Options.GarbageCollectionInterval) * time.Second,
+		Options: RunOptions{
+			Delete:      ctx.Options.GarbageCollectionDelete,
+			GracePeriod: time.Duration(ctx.Options.GarbageCollectionGracePeriod) * time.Second,
+		},
+	}, nil
+}
+
+// resourcesFromSyncers returns the default resources and the namespaced resources of the given syncers.
+// If a syncer exists for a resource, its name translator is used.
+func resourcesFromSyncers(ctx *synccontext.RegisterContext, syncers []syncer.Object) ([]Resource, error) {
+	resources := []Resource{}
+	for _, gvk := range DefaultResources {
+		resources = append(resources, Resource{GroupVersionKind: gvk})
+	}
+
+	for _, s := range syncers {
+		realSyncer, ok := s.(syncer.Syncer)
+		if !ok {
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(s.Resource(), ctx.PhysicalManager.GetScheme())
+		if err != nil {
+			return nil, fmt.Errorf("retrieve gvk of %s syncer: %w", s.Name(), err)
+		}
+
+		namespaced, err := apiutil.IsGVKNamespaced(gvk, ctx.PhysicalManager.GetRESTMapper())
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("check if %s is namespaced: %w", gvk.String(), err)
+		} else if !namespaced {
+			continue
+		}
+
+		found := false
+		for i := range resources {
+			if resources[i].GroupVersionKind == gvk {
+				resources[i].Translator = realSyncer
+				found = true
+				break
+			}
+		}
+		if !found {
+			resources = append(resources, Resource{GroupVersionKind: gvk, Translator: realSyncer})
+		}
+	}
+
+	return resources, nil
+}
+
+// Start runs the garbage collector periodically until the context is done
+func (c *Collector) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		report, err := c.Run(ctx, c.Options)
+		if err != nil {
+			c.Log.Errorf("error collecting orphaned host objects: %v", err)
+			return
+		}
+
+		deleted := 0
+		for _, item := range report.Items {
+			if item.Deleted {
+				deleted++
+			}
+		}
+		if len(report.Items) > 0 {
+			c.Log.Infof("found %d orphaned host objects, deleted %d", len(report.Items), deleted)
+		}
+		for _, reportErr := range report.Errors {
+			c.Log.Errorf("error collecting orphaned host objects: %s", reportErr)
+		}
+	}, c.Interval)
+
+	return nil
+}
+
+// LastReport returns the report of the last run or nil if there was none yet
+func (c *Collector) LastReport() *Report {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.lastReport
+}
+
+// Run lists the managed host objects of all resources and reports the ones without virtual object.
+// If enabled, orphans are deleted after they were reported for the grace period.
+func (c *Collector) Run(ctx context.Context, options RunOptions) (*Report, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := c.getNow()
+	report := &Report{
+		Delete:             options.Delete,
+		GracePeriodSeconds: int64(options.GracePeriod / time.Second),
+		StartTimestamp:     metav1.NewTime(now),
+		Items:              []*Orphan{},
+	}
+
+	namespaces, err := c.hostNamespaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list host namespaces: %w", err)
+	}
+
+	firstSeen := map[string]metav1.Time{}
+	for _, resource := range c.Resources {
+		orphans, err := c.findOrphans(ctx, resource, namespaces)
+		if err != nil {
+			// keep the grace period of orphans we couldn't check in this run
+			c.keepFirstSeen(firstSeen, resource.GroupVersionKind)
+
+			// the resource doesn't exist in the host or virtual cluster
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", resource.GroupVersionKind.String(), err))
+			continue
+		}
+
+		for _, orphan := range orphans {
+			key := orphan.key
+			seen, ok := c.firstSeen[key]
+			if !ok {
+				seen = metav1.NewTime(now)
+			}
+			orphan.FirstSeen = seen
+			firstSeen[key] = seen
+
+			if options.Delete && now.Sub(seen.Time) >= options.GracePeriod {
+				err = c.delete(ctx, orphan)
+				if err != nil {
+					orphan.Error = err.Error()
+				} else {
+					orphan.Deleted = true
+					delete(firstSeen, key)
+				}
+			}
+
+			report.Items = append(report.Items, &orphan.Orphan)
+		}
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		} else if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+
+		return a.Name < b.Name
+	})
+	report.CompletionTimestamp = metav1.NewTime(c.getNow())
+	c.firstSeen = firstSeen
+	c.lastReport = report
+	return report, nil
+}
+
+func (c *Collector) keepFirstSeen(firstSeen map[string]metav1.Time, gvk schema.GroupVersionKind) {
+	prefix := gvk.String() + "/"
+	for key, seen := range c.firstSeen {
+		if strings.HasPrefix(key, prefix) {
+			firstSeen[key] = seen
+		}
+	}
+}
+
+type orphan struct {
+	Orphan
+
+	key string
+	gvk schema.GroupVersionKind
+	uid types.UID
+}
+
+func (c *Collector) findOrphans(ctx context.Context, resource Resource, namespaces []string) ([]*orphan, error) {
+	orphans := []*orphan{}
+	paused := map[string]bool{}
+	for _, namespace := range namespaces {
+		listOptions := []client.ListOption{client.InNamespace(namespace)}
+		if !c.MultiNamespaceMode {
+			listOptions = append(listOptions, client.MatchingLabels{translate.MarkerLabel: translate.Suffix})
+		}
+
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(resource.GroupVersionKind.GroupVersion().WithKind(resource.GroupVersionKind.Kind + "List"))
+		err := c.HostReader.List(ctx, list, listOptions...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			pObj := &list.Items[i]
+			pObj.SetGroupVersionKind(resource.GroupVersionKind)
+			if pObj.DeletionTimestamp != nil || !c.isManaged(pObj) {
+				continue
+			}
+
+			// host objects synced under a previous name template are resolved through their annotations
+			vName := types.NamespacedName{}
+			if resource.Translator != nil && translate.Default.IsManaged(pObj) {
+				managed, err := resource.Translator.IsManaged(ctx, pObj)
+				if err != nil {
+					return nil, err
+				} else if !managed {
+					continue
+				}
+
+				vName = resource.Translator.PhysicalToVirtual(ctx, pObj)
+			} else if pObj.Annotations != nil {
+				vName = types.NamespacedName{Namespace: pObj.Annotations[translate.NamespaceAnnotation], Name: pObj.Annotations[translate.NameAnnotation]}
+			}
+
+			// we can't tell which virtual object this belongs to, so we better leave it alone
+			if vName.Name == "" {
+				continue
+			}
+
+			exists, err := c.virtualObjectExists(ctx, resource.GroupVersionKind, vName)
+			if err != nil {
+				return nil, err
+			} else if exists && !renamed(ctx, resource, pObj, vName) {
+				continue
+			}
+
+			// host objects of paused virtual namespaces are kept until syncing is resumed
+			if vName.Namespace != "" {
+				if _, ok := paused[vName.Namespace]; !ok {
+					paused[vName.Namespace], err = c.virtualNamespacePaused(ctx, vName.Namespace)
+					if err != nil {
+						return nil, err
+					}
+				}
+				if paused[vName.Namespace] {
+					continue
+				}
+			}
+
+			apiVersion, kind := resource.GroupVersionKind.ToAPIVersionAndKind()
+			orphans = append(orphans, &orphan{
+				Orphan: Orphan{
+					APIVersion:       apiVersion,
+					Kind:             kind,
+					Namespace:        pObj.Namespace,
+					Name:             pObj.Name,
+					VirtualNamespace: vName.Namespace,
+					VirtualName:      vName.Name,
+					Renamed:          exists,
+				},
+				key: resource.GroupVersionKind.String() + "/" + pObj.Namespace + "/" + pObj.Name + "/" + string(pObj.UID),
+				gvk: resource.GroupVersionKind,
+				uid: pObj.UID,
+			})
+		}
+	}
+
+	return orphans, nil
+}
+
+// isManaged checks if the host object was synced by this vcluster. In contrast to translate.Default.IsManaged
+// this includes host objects that were synced under a different name template.
+func (c *Collector) isManaged(pObj client.Object) bool {
+	if c.MultiNamespaceMode {
+		return translate.Default.IsManaged(pObj)
+	}
+
+	return pObj.GetLabels()[translate.MarkerLabel] == translate.Suffix && pObj.GetAnnotations()[translate.NameAnnotation] != ""
+}
+
+// renamed returns true if the virtual object is synced to a different host object now, which
+// happens if the name template was changed
+func renamed(ctx context.Context, resource Resource, pObj client.Object, vName types.NamespacedName) bool {
+	pName := types.NamespacedName{Namespace: translate.Default.PhysicalNamespace(vName.Namespace), Name: translate.Default.PhysicalName(vName.Name, vName.Namespace)}
+	if resource.Translator != nil {
+		pName = resource.Translator.VirtualToPhysical(ctx, vName, nil)
+	}
+
+	return pName.Name != "" && pName != types.NamespacedName{Namespace: pObj.GetNamespace(), Name: pObj.GetName()}
+}
+
+func (c *Collector) virtualObjectExists(ctx context.Context, gvk schema.GroupVersionKind, name types.NamespacedName) (bool, error) {
+	vObj := &metav1.PartialObjectMetadata{}
+	vObj.SetGroupVersionKind(gvk)
+	err := c.VirtualReader.Get(ctx, name, vObj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *Collector) virtualNamespacePaused(ctx context.Context, name string) (bool, error) {
+	vNamespace := &metav1.PartialObjectMetadata{}
+	vNamespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	err := c.VirtualReader.Get(ctx, types.NamespacedName{Name: name}, vNamespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return syncer.IsPaused(vNamespace), nil
+}
+
+func (c *Collector) delete(ctx context.Context, orphan *orphan) error {
+	pObj := &metav1.PartialObjectMetadata{}
+	pObj.SetGroupVersionKind(orphan.gvk)
+	pObj.SetNamespace(orphan.Namespace)
+	pObj.SetName(orphan.Name)
+
+	// the uid precondition makes sure we don't delete an object that was recreated in the meantime
+	c.Log.Infof("delete orphaned host %s %s/%s", orphan.Kind, orphan.Namespace, orphan.Name)
+	err := c.HostClient.Delete(ctx, pObj, client.Preconditions{UID: &orphan.uid})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Collector) hostNamespaces(ctx context.Context) ([]string, error) {
+	if !c.MultiNamespaceMode {
+		return []string{c.TargetNamespace}, nil
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	err := c.HostReader.List(ctx, namespaceList, client.MatchingLabels{translate.MarkerLabel: translate.MarkerLabelCluster(translate.Default)})
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+	for _, namespace := range namespaceList.Items {
+		if translate.Default.IsTargetedNamespace(namespace.Name) {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+
+	return namespaces, nil
+}
+
+func (c *Collector) getNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,283 @@
+package garbagecollector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCollector(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	hostConfigMap := func(vName string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      translate.Default.PhysicalName(vName, "default"),
+				Namespace: generictesting.DefaultTestTargetNamespace,
+				UID:       types.UID(vName),
+				Labels: map[string]string{
+					translate.MarkerLabel:    translate.Suffix,
+					translate.NamespaceLabel: "default",
+				},
+				Annotations: map[string]string{
+					translate.NameAnnotation:      vName,
+					translate.NamespaceAnnotation: "default",
+				},
+			},
+		}
+	}
+	pOrphan := hostConfigMap("orphan")
+	pSynced := hostConfigMap("synced")
+	pUnmanaged := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unmanaged",
+			Namespace: generictesting.DefaultTestTargetNamespace,
+		},
+	}
+	vSynced := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "synced",
+			Namespace: "default",
+		},
+	}
+
+	scheme := testingutil.NewScheme()
+	pClient := testingutil.NewFakeClient(scheme, pOrphan, pSynced, pUnmanaged)
+	vClient := testingutil.NewFakeClient(scheme, vSynced)
+
+	now := time.Now()
+	collector := &Collector{
+		HostReader:      pClient,
+		HostClient:      pClient,
+		VirtualReader:   vClient,
+		Log:             loghelper.New("garbage-collector-test"),
+		Resources:       []Resource{{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap")}},
+		TargetNamespace: generictesting.DefaultTestTargetNamespace,
+		now: func() time.Time {
+			return now
+		},
+	}
+	options := RunOptions{Delete: true, GracePeriod: time.Minute}
+
+	// orphans are only reported within the grace period
+	ctx := context.Background()
+	report, err := collector.Run(ctx, options)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Errors), 0)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Name, pOrphan.Name)
+	assert.Equal(t, report.Items[0].VirtualName, "orphan")
+	assert.Equal(t, report.Items[0].VirtualNamespace, "default")
+	assert.Equal(t, report.Items[0].Deleted, false)
+	assert.Equal(t, collector.LastReport(), report)
+	err = pClient.Get(ctx, types.NamespacedName{Namespace: pOrphan.Namespace, Name: pOrphan.Name}, &corev1.ConfigMap{})
+	assert.NilError(t, err)
+
+	// report only mode never deletes
+	now = now.Add(2 * time.Minute)
+	report, err = collector.Run(ctx, RunOptions{GracePeriod: time.Minute})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Deleted, false)
+	assert.Equal(t, report.Items[0].FirstSeen.Time.Equal(now.Add(-2*time.Minute)), true)
+
+	// orphans are deleted after the grace period
+	report, err = collector.Run(ctx, options)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Deleted, true)
+	err = pClient.Get(ctx, types.NamespacedName{Namespace: pOrphan.Namespace, Name: pOrphan.Name}, &corev1.ConfigMap{})
+	assert.Assert(t, kerrors.IsNotFound(err))
+	err = pClient.Get(ctx, types.NamespacedName{Namespace: pSynced.Namespace, Name: pSynced.Name}, &corev1.ConfigMap{})
+	assert.NilError(t, err)
+	err = pClient.Get(ctx, types.NamespacedName{Namespace: pUnmanaged.Namespace, Name: pUnmanaged.Name}, &corev1.ConfigMap{})
+	assert.NilError(t, err)
+
+	report, err = collector.Run(ctx, options)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 0)
+}
+
+func TestCollectorPausedNamespace(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	pOrphan := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName("orphan", "paused"),
+			Namespace: generictesting.DefaultTestTargetNamespace,
+			Labels: map[string]string{
+				translate.MarkerLabel:    translate.Suffix,
+				translate.NamespaceLabel: "paused",
+			},
+			Annotations: map[string]string{
+				translate.NameAnnotation:      "orphan",
+				translate.NamespaceAnnotation: "paused",
+			},
+		},
+	}
+	vNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "paused",
+			Annotations: map[string]string{constants.PauseSyncAnnotation: "true"},
+		},
+	}
+
+	scheme := testingutil.NewScheme()
+	pClient := testingutil.NewFakeClient(scheme, pOrphan)
+	vClient := testingutil.NewFakeClient(scheme, vNamespace)
+	collector := &Collector{
+		HostReader:      pClient,
+		HostClient:      pClient,
+		VirtualReader:   vClient,
+		Log:             loghelper.New("garbage-collector-test"),
+		Resources:       []Resource{{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap")}},
+		TargetNamespace: generictesting.DefaultTestTargetNamespace,
+	}
+
+	// host objects of paused namespaces are not orphans
+	ctx := context.Background()
+	report, err := collector.Run(ctx, RunOptions{Delete: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 0)
+
+	// once resumed, they are collected again
+	vNamespace.Annotations = nil
+	err = vClient.Update(ctx, vNamespace)
+	assert.NilError(t, err)
+	report, err = collector.Run(ctx, RunOptions{Delete: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Deleted, true)
+}
+
+func TestCollectorRenamed(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	// the host object was synced with a previous name template
+	pRenamed := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default-renamed",
+			Namespace: generictesting.DefaultTestTargetNamespace,
+			Labels: map[string]string{
+				translate.MarkerLabel:    translate.Suffix,
+				translate.NamespaceLabel: "default",
+			},
+			Annotations: map[string]string{
+				translate.NameAnnotation:      "renamed",
+				translate.NamespaceAnnotation: "default",
+			},
+		},
+	}
+	vRenamed := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "renamed",
+			Namespace: "default",
+		},
+	}
+
+	scheme := testingutil.NewScheme()
+	pClient := testingutil.NewFakeClient(scheme, pRenamed)
+	vClient := testingutil.NewFakeClient(scheme, vRenamed)
+	collector := &Collector{
+		HostReader:      pClient,
+		HostClient:      pClient,
+		VirtualReader:   vClient,
+		Log:             loghelper.New("garbage-collector-test"),
+		Resources:       []Resource{{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap")}},
+		TargetNamespace: generictesting.DefaultTestTargetNamespace,
+	}
+
+	// renamed host objects are reported, but only deleted if enabled
+	ctx := context.Background()
+	report, err := collector.Run(ctx, RunOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Name, pRenamed.Name)
+	assert.Equal(t, report.Items[0].Renamed, true)
+	assert.Equal(t, report.Items[0].Deleted, false)
+
+	report, err = collector.Run(ctx, RunOptions{Delete: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Deleted, true)
+}
+
+type failingReader struct {
+	client.Reader
+}
+
+func (f *failingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return errors.New("list failed")
+}
+
+func TestCollectorKeepsFirstSeenOnError(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	pOrphan := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName("orphan", "default"),
+			Namespace: generictesting.DefaultTestTargetNamespace,
+			UID:       types.UID("orphan"),
+			Labels: map[string]string{
+				translate.MarkerLabel:    translate.Suffix,
+				translate.NamespaceLabel: "default",
+			},
+			Annotations: map[string]string{
+				translate.NameAnnotation:      "orphan",
+				translate.NamespaceAnnotation: "default",
+			},
+		},
+	}
+
+	scheme := testingutil.NewScheme()
+	pClient := testingutil.NewFakeClient(scheme, pOrphan)
+	vClient := testingutil.NewFakeClient(scheme)
+
+	now := time.Now()
+	collector := &Collector{
+		HostReader:      pClient,
+		HostClient:      pClient,
+		VirtualReader:   vClient,
+		Log:             loghelper.New("garbage-collector-test"),
+		Resources:       []Resource{{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap")}},
+		TargetNamespace: generictesting.DefaultTestTargetNamespace,
+		now: func() time.Time {
+			return now
+		},
+	}
+	options := RunOptions{Delete: true, GracePeriod: time.Minute}
+
+	ctx := context.Background()
+	report, err := collector.Run(ctx, options)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Deleted, false)
+
+	// a failing run doesn't reset the grace period
+	now = now.Add(2 * time.Minute)
+	collector.HostReader = &failingReader{Reader: pClient}
+	report, err = collector.Run(ctx, options)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Errors), 1)
+	assert.Equal(t, len(report.Items), 0)
+
+	collector.HostReader = pClient
+	report, err = collector.Run(ctx, options)
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Deleted, true)
+	assert.Equal(t, report.Items[0].FirstSeen.Time.Equal(now.Add(-2*time.Minute)), true)
+}
@@ -706,7 +706,7 @@ func (r *InitManifestsConfigMapReconciler) popFromStatus(cm *corev1.ConfigMap, c
 
 func (r *InitManifestsConfigMapReconciler) deleteHelmRelease(cm *corev1.ConfigMap, chartStatus ChartStatus) error {
 	err := r.HelmClient.Delete(chartStatus.Name, chartStatus.Namespace)
-	if err != nil {
+	if err != nil && !helm.IsReleaseNotFound(err) {
 		r.Log.Infof("error deleting helm release %s/%s: %v", chartStatus.Namespace, chartStatus.Name, err)
 		return r.setChartStatus(cm, &Chart{
 			Name:             chartStatus.Name,
@@ -0,0 +1,352 @@
+package networkisolation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// PolicyNamePrefix is the prefix of the baseline network policies created in the host cluster
+	PolicyNamePrefix = "vcluster-baseline"
+
+	// PolicyLabel marks the baseline network policies with the vcluster they belong to. We don't use the
+	// translate.MarkerLabel here, as the policies would otherwise be treated as synced objects.
+	PolicyLabel = "vcluster.loft.sh/baseline-policy"
+
+	// resyncPeriod is used to pick up changes of services that are not watched, such as mapped host services
+	resyncPeriod = time.Minute
+)
+
+// NetworkIsolationReconciler maintains a baseline network policy for the workloads of the
+// virtual cluster in every host namespace it syncs to. The policy is derived from the live state
+// of the host cluster, so it follows changes to the vcluster service, CoreDNS and mapped host services.
+type NetworkIsolationReconciler struct {
+	client.Client
+
+	// APIReader is used to look up objects outside the cached namespaces
+	APIReader client.Reader
+	Log       loghelper.Logger
+
+	CurrentNamespace   string
+	TargetNamespace    string
+	ServiceName        string
+	MultiNamespaceMode bool
+
+	// HostServices are the host services that the workloads are allowed to reach
+	HostServices []types.NamespacedName
+
+	// EgressCIDRs are the ip ranges the workloads are allowed to reach, EgressExceptCIDRs
+	// are excluded from them
+	EgressCIDRs       []string
+	EgressExceptCIDRs []string
+}
+
+// Validate checks the configured egress cidrs
+func (r *NetworkIsolationReconciler) Validate() error {
+	_, err := r.ipBlockRule()
+	return err
+}
+
+// PolicyName returns the name of the baseline network policy of this virtual cluster
+func PolicyName() string {
+	return translate.SafeConcatName(PolicyNamePrefix, translate.Suffix)
+}
+
+func (r *NetworkIsolationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	isTenantNamespace, err := r.isTenantNamespace(ctx, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !isTenantNamespace {
+		return ctrl.Result{}, nil
+	}
+
+	expected, err := r.buildPolicy(ctx, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	existing := &networkingv1.NetworkPolicy{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: expected.Name}, existing)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		r.Log.Infof("create baseline network policy %s/%s", expected.Namespace, expected.Name)
+		err = r.Client.Create(ctx, expected)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	if !equality.Semantic.DeepEqual(existing.Spec, expected.Spec) || !equality.Semantic.DeepEqual(existing.Labels, expected.Labels) {
+		r.Log.Infof("update baseline network policy %s/%s", expected.Namespace, expected.Name)
+		existing.Labels = expected.Labels
+		existing.Spec = expected.Spec
+		err = r.Client.Update(ctx, existing)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+}
+
+func (r *NetworkIsolationReconciler) isTenantNamespace(ctx context.Context, namespace string) (bool, error) {
+	if !r.MultiNamespaceMode {
+		return namespace == r.TargetNamespace, nil
+	}
+
+	ns := &corev1.Namespace{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return ns.DeletionTimestamp == nil && ns.Labels[translate.MarkerLabel] == translate.MarkerLabelCluster(translate.Default), nil
+}
+
+// workloadSelector selects the pods of the virtual cluster within a host namespace
+func (r *NetworkIsolationReconciler) workloadSelector() metav1.LabelSelector {
+	if r.MultiNamespaceMode {
+		return metav1.LabelSelector{}
+	}
+
+	return metav1.LabelSelector{MatchLabels: map[string]string{translate.MarkerLabel: translate.Suffix}}
+}
+
+// workloadPeer selects the pods of the virtual cluster across all host namespaces
+func (r *NetworkIsolationReconciler) workloadPeer() networkingv1.NetworkPolicyPeer {
+	if r.MultiNamespaceMode {
+		return networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{translate.MarkerLabel: translate.MarkerLabelCluster(translate.Default)}},
+		}
+	}
+
+	selector := r.workloadSelector()
+	return networkingv1.NetworkPolicyPeer{PodSelector: &selector}
+}
+
+// hostPeer selects all pods that don't belong to a virtual cluster. Pods of other virtual clusters
+// either carry the marker label themselves or live in a namespace that carries it.
+func hostPeer() networkingv1.NetworkPolicyPeer {
+	notManaged := metav1.LabelSelectorRequirement{Key: translate.MarkerLabel, Operator: metav1.LabelSelectorOpDoesNotExist}
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{notManaged}},
+		PodSelector:       &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{notManaged}},
+	}
+}
+
+func (r *NetworkIsolationReconciler) buildPolicy(ctx context.Context, namespace string) (*networkingv1.NetworkPolicy, error) {
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{To: []networkingv1.NetworkPolicyPeer{r.workloadPeer()}},
+	}
+
+	// allow the vcluster api server
+	apiRule, err := r.serviceRule(ctx, types.NamespacedName{Namespace: r.CurrentNamespace, Name: r.ServiceName})
+	if err != nil {
+		return nil, err
+	} else if apiRule != nil {
+		egress = append(egress, *apiRule)
+	}
+
+	// allow the vcluster CoreDNS
+	dnsRule, err := r.serviceRule(ctx, types.NamespacedName{
+		Namespace: translate.Default.PhysicalNamespace("kube-system"),
+		Name:      translate.Default.PhysicalName("kube-dns", "kube-system"),
+	})
+	if err != nil {
+		return nil, err
+	} else if dnsRule != nil {
+		egress = append(egress, *dnsRule)
+	}
+
+	// allow mapped host services
+	for _, hostService := range r.HostServices {
+		serviceRule, err := r.serviceRule(ctx, hostService)
+		if err != nil {
+			return nil, err
+		} else if serviceRule != nil {
+			egress = append(egress, *serviceRule)
+		}
+	}
+
+	// allow configured ip ranges
+	ipBlockRule, err := r.ipBlockRule()
+	if err != nil {
+		return nil, err
+	} else if ipBlockRule != nil {
+		egress = append(egress, *ipBlockRule)
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      PolicyName(),
+			Labels: map[string]string{
+				PolicyLabel: translate.Suffix,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: r.workloadSelector(),
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{r.workloadPeer(), hostPeer()}},
+			},
+			Egress:      egress,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}, nil
+}
+
+// serviceRule allows egress to the pods and target ports of the given service. Returns nil if
+// the service does not exist or has no selector.
+func (r *NetworkIsolationReconciler) serviceRule(ctx context.Context, name types.NamespacedName) (*networkingv1.NetworkPolicyEgressRule, error) {
+	service := &corev1.Service{}
+	err := r.APIReader.Get(ctx, name, service)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			r.Log.Debugf("service %s not found, skip adding it to the baseline network policy", name.String())
+			return nil, nil
+		}
+
+		return nil, err
+	} else if len(service.Spec.Selector) == 0 {
+		return nil, nil
+	}
+
+	ports := []networkingv1.NetworkPolicyPort{}
+	for _, servicePort := range service.Spec.Ports {
+		protocol := servicePort.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+
+		port := servicePort.TargetPort
+		if port.Type == intstr.Int && port.IntVal == 0 {
+			port = intstr.FromInt(int(servicePort.Port))
+		}
+
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+
+	return &networkingv1.NetworkPolicyEgressRule{
+		Ports: ports,
+		To: []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: service.Namespace}},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: service.Spec.Selector},
+			},
+		},
+	}, nil
+}
+
+func (r *NetworkIsolationReconciler) ipBlockRule() (*networkingv1.NetworkPolicyEgressRule, error) {
+	if len(r.EgressCIDRs) == 0 {
+		return nil, nil
+	}
+
+	rule := &networkingv1.NetworkPolicyEgressRule{}
+	for _, cidr := range r.EgressCIDRs {
+		_, allowed, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parse egress cidr %s: %w", cidr, err)
+		}
+
+		ipBlock := &networkingv1.IPBlock{CIDR: cidr}
+		for _, exceptCIDR := range r.EgressExceptCIDRs {
+			_, except, err := net.ParseCIDR(exceptCIDR)
+			if err != nil {
+				return nil, fmt.Errorf("parse egress except cidr %s: %w", exceptCIDR, err)
+			}
+
+			// except ranges have to be strictly within the allowed range
+			allowedOnes, _ := allowed.Mask.Size()
+			exceptOnes, _ := except.Mask.Size()
+			if allowed.Contains(except.IP) && exceptOnes > allowedOnes && len(allowed.IP) == len(except.IP) {
+				ipBlock.Except = append(ipBlock.Except, exceptCIDR)
+			}
+		}
+
+		rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{IPBlock: ipBlock})
+	}
+
+	return rule, nil
+}
+
+func (r *NetworkIsolationReconciler) tenantNamespaces(ctx context.Context) ([]string, error) {
+	if !r.MultiNamespaceMode {
+		return []string{r.TargetNamespace}, nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	err := r.Client.List(ctx, namespaces, client.MatchingLabels{translate.MarkerLabel: translate.MarkerLabelCluster(translate.Default)})
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+
+	return names, nil
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *NetworkIsolationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// reconcile all tenant namespaces if any watched service changes, this also
+	// creates the policies initially as all existing services are listed on startup
+	enqueueAll := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+		namespaces, err := r.tenantNamespaces(ctx)
+		if err != nil {
+			r.Log.Infof("error listing namespaces for baseline network policies: %v", err)
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, ns := range namespaces {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: PolicyName()}})
+		}
+		return requests
+	})
+
+	isPolicy := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetName() == PolicyName()
+	})
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		Named("network_isolation").
+		For(&networkingv1.NetworkPolicy{}, builder.WithPredicates(isPolicy)).
+		Watches(&corev1.Service{}, enqueueAll)
+	if r.MultiNamespaceMode {
+		controllerBuilder = controllerBuilder.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, object client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: object.GetName(), Name: PolicyName()}}}
+		}))
+	}
+
+	return controllerBuilder.Complete(r)
+}
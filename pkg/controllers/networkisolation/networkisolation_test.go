@@ -0,0 +1,115 @@
+package networkisolation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcile(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator("test")
+
+	vclusterService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "vcluster", Name: "my-vcluster"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"release": "my-vcluster"},
+			Ports:    []corev1.ServicePort{{Name: "https", Port: 443, TargetPort: intstr.FromInt(8443)}},
+		},
+	}
+	dnsService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: translate.Default.PhysicalName("kube-dns", "kube-system")},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"k8s-app": "vcluster-kube-dns"},
+			Ports: []corev1.ServicePort{
+				{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromInt(1053)},
+				{Name: "dns-tcp", Port: 53, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(1053)},
+			},
+		},
+	}
+	hostService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "databases", Name: "postgres"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "postgres"},
+			Ports:    []corev1.ServicePort{{Port: 5432}},
+		},
+	}
+
+	fakeClient := testingutil.NewFakeClient(testingutil.NewScheme(), vclusterService, dnsService, hostService)
+	reconciler := &NetworkIsolationReconciler{
+		Client:            fakeClient,
+		APIReader:         fakeClient,
+		Log:               loghelper.New("test"),
+		CurrentNamespace:  "vcluster",
+		TargetNamespace:   "test",
+		ServiceName:       "my-vcluster",
+		HostServices:      []types.NamespacedName{{Namespace: "databases", Name: "postgres"}, {Namespace: "databases", Name: "missing"}},
+		EgressCIDRs:       []string{"0.0.0.0/0"},
+		EgressExceptCIDRs: []string{"10.0.0.0/8", "fd00::/8"},
+	}
+	assert.NilError(t, reconciler.Validate())
+
+	// other namespaces are ignored
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "other", Name: PolicyName()}})
+	assert.NilError(t, err)
+	policies := &networkingv1.NetworkPolicyList{}
+	assert.NilError(t, fakeClient.List(context.TODO(), policies))
+	assert.Equal(t, len(policies.Items), 0)
+
+	_, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "test", Name: PolicyName()}})
+	assert.NilError(t, err)
+
+	policy := &networkingv1.NetworkPolicy{}
+	assert.NilError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: PolicyName()}, policy))
+
+	workloads := metav1.LabelSelector{MatchLabels: map[string]string{translate.MarkerLabel: translate.Suffix}}
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	port8443, port1053, port5432 := intstr.FromInt(8443), intstr.FromInt(1053), intstr.FromInt(5432)
+	assert.DeepEqual(t, policy.Spec, networkingv1.NetworkPolicySpec{
+		PodSelector: workloads,
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &workloads}, hostPeer()}},
+		},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			{To: []networkingv1.NetworkPolicyPeer{{PodSelector: &workloads}}},
+			{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port8443}},
+				To: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "vcluster"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"release": "my-vcluster"}},
+				}},
+			},
+			{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &port1053}, {Protocol: &tcp, Port: &port1053}},
+				To: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "test"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "vcluster-kube-dns"}},
+				}},
+			},
+			{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port5432}},
+				To: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "databases"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "postgres"}},
+				}},
+			},
+			{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: []string{"10.0.0.0/8"}}}},
+			},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	})
+
+	// invalid cidrs are rejected
+	reconciler.EgressCIDRs = []string{"0.0.0.0"}
+	assert.Assert(t, reconciler.Validate() != nil)
+}
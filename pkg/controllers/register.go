@@ -5,24 +5,33 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd"
 
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/generic"
 	"github.com/loft-sh/vcluster/pkg/controllers/servicesync"
+	"github.com/loft-sh/vcluster/pkg/controllers/sleep"
 	"github.com/loft-sh/vcluster/pkg/helm"
 	"github.com/loft-sh/vcluster/pkg/plugin"
+	"github.com/loft-sh/vcluster/pkg/server/activity"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/cachetransform"
 	util "github.com/loft-sh/vcluster/pkg/util/context"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/k8sdefaultendpoint"
 	"github.com/loft-sh/vcluster/pkg/controllers/manifests"
+	"github.com/loft-sh/vcluster/pkg/controllers/networkisolation"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/csidrivers"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/csinodes"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/csistoragecapacities"
@@ -33,6 +42,7 @@ import (
 	"github.com/loft-sh/vcluster/cmd/vcluster/context"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
 	"github.com/loft-sh/vcluster/pkg/controllers/coredns"
+	"github.com/loft-sh/vcluster/pkg/controllers/garbagecollector"
 	"github.com/loft-sh/vcluster/pkg/controllers/podsecurity"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/configmaps"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/endpoints"
@@ -45,9 +55,15 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/poddisruptionbudgets"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/pods"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/priorityclasses"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/resourceclaims"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/resourceclaimtemplates"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/runtimeclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/secrets"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/services"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/storageclasses"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumegroupsnapshotclasses"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumegroupsnapshotcontents"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumegroupsnapshots"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshotclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshotcontents"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumesnapshots"
@@ -71,10 +87,13 @@ var ResourceControllers = map[string][]func(*synccontext.RegisterContext) (synce
 	"storageclasses":         {storageclasses.New},
 	"hoststorageclasses":     {storageclasses.NewHostStorageClassSyncer},
 	"priorityclasses":        {priorityclasses.New},
+	"runtimeclasses":         {runtimeclasses.New},
+	"resourceclaims":         {resourceclaims.New, resourceclaimtemplates.New},
 	"nodes,fake-nodes":       {nodes.New},
 	"poddisruptionbudgets":   {poddisruptionbudgets.New},
 	"networkpolicies":        {networkpolicies.New},
 	"volumesnapshots":        {volumesnapshotclasses.New, volumesnapshots.New, volumesnapshotcontents.New},
+	"volumegroupsnapshots":   {volumegroupsnapshotclasses.New, volumegroupsnapshots.New, volumegroupsnapshotcontents.New},
 	"serviceaccounts":        {serviceaccounts.New},
 	"csinodes":               {csinodes.New},
 	"csidrivers":             {csidrivers.New},
@@ -148,8 +167,6 @@ func RegisterIndices(ctx *context.ControllerContext, syncers []syncer.Object) er
 }
 
 func RegisterControllers(ctx *context.ControllerContext, syncers []syncer.Object) error {
-	registerContext := util.ToRegisterContext(ctx)
-
 	err := k8sdefaultendpoint.Register(ctx)
 	if err != nil {
 		return err
@@ -181,17 +198,128 @@ func RegisterControllers(ctx *context.ControllerContext, syncers []syncer.Object
 		return err
 	}
 
+	// register controller that maintains the baseline network policies in the host cluster
+	if ctx.Options.EnforceNetworkIsolation {
+		err = RegisterNetworkIsolationController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = RegisterGenericSyncController(ctx)
 	if err != nil {
 		return err
 	}
 
+	// register garbage collector that finds managed host objects without a virtual object
+	err = RegisterGarbageCollector(ctx, syncers)
+	if err != nil {
+		return err
+	}
+
+	// register controller that puts the virtual cluster to sleep after inactivity or on the sleep schedule
+	if ctx.Options.SleepAfter > 0 || ctx.Options.SleepSchedule != "" {
+		err = RegisterSleepController(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// with sharding enabled, the namespaced resources are synced by the shards of all replicas
+	if ctx.Options.ShardNamespaces {
+		_, syncers, err = SplitSyncers(ctx, syncers)
+		if err != nil {
+			return err
+		}
+	}
+
 	// register controllers for resource synchronization
+	return RegisterSyncers(ctx, syncers)
+}
+
+// SplitSyncers returns the syncers of namespaced and of cluster scoped resources
+func SplitSyncers(ctx *context.ControllerContext, syncers []syncer.Object) ([]syncer.Object, []syncer.Object, error) {
+	namespaced := []syncer.Object{}
+	clusterScoped := []syncer.Object{}
+	for _, s := range syncers {
+		isNamespaced, err := apiutil.IsObjectNamespaced(s.Resource(), ctx.VirtualManager.GetScheme(), ctx.VirtualManager.GetRESTMapper())
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "check scope of %s syncer", s.Name())
+		}
+
+		if isNamespaced {
+			namespaced = append(namespaced, s)
+		} else {
+			clusterScoped = append(clusterScoped, s)
+		}
+	}
+
+	return namespaced, clusterScoped, nil
+}
+
+// RegisterGarbageCollector creates the garbage collector for orphaned host objects and starts it
+// periodically if enabled. It can be triggered on demand through the vcluster/gc endpoint.
+func RegisterGarbageCollector(ctx *context.ControllerContext, syncers []syncer.Object) error {
+	collector, err := garbagecollector.NewFromContext(util.ToRegisterContext(ctx), syncers)
+	if err != nil {
+		return errors.Wrap(err, "create garbage collector")
+	}
+
+	if collector.Interval > 0 {
+		err = ctx.LocalManager.Add(collector)
+		if err != nil {
+			return errors.Wrap(err, "start garbage collector")
+		}
+	}
+
+	garbagecollector.SetDefault(collector)
+	return nil
+}
+
+// RegisterSleepController creates the sleep controller and starts it
+func RegisterSleepController(ctx *context.ControllerContext) error {
+	var schedule *sleep.Schedule
+	if ctx.Options.SleepSchedule != "" {
+		var err error
+		schedule, err = sleep.ParseSchedule(ctx.Options.SleepSchedule)
+		if err != nil {
+			return errors.Wrap(err, "parse sleep schedule")
+		}
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(ctx.LocalManager.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "create host client")
+	}
+
+	err = ctx.LocalManager.Add(&sleep.Controller{
+		Client:            kubeClient,
+		Name:              ctx.Options.Name,
+		Namespace:         ctx.CurrentNamespace,
+		ServiceName:       ctx.Options.ServiceName,
+		Port:              ctx.Options.Port,
+		SleepAfter:        ctx.Options.SleepAfter,
+		Schedule:          schedule,
+		Tracker:           activity.Default,
+		Interval:          time.Second * 30,
+		WakeupGracePeriod: time.Minute * 2,
+		Log:               log.GetInstance(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "start sleep controller")
+	}
+
+	return nil
+}
+
+// RegisterSyncers starts the controllers of the given syncers
+func RegisterSyncers(ctx *context.ControllerContext, syncers []syncer.Object) error {
+	registerContext := util.ToRegisterContext(ctx)
 	for _, v := range syncers {
 		// fake syncer?
 		fakeSyncer, ok := v.(syncer.FakeSyncer)
 		if ok {
-			err = syncer.RegisterFakeSyncer(registerContext, fakeSyncer)
+			err := syncer.RegisterFakeSyncer(registerContext, fakeSyncer)
 			if err != nil {
 				return errors.Wrapf(err, "start %s syncer", v.Name())
 			}
@@ -199,7 +327,7 @@ func RegisterControllers(ctx *context.ControllerContext, syncers []syncer.Object
 			// real syncer?
 			realSyncer, ok := v.(syncer.Syncer)
 			if ok {
-				err = syncer.RegisterSyncer(registerContext, realSyncer)
+				err := syncer.RegisterSyncer(registerContext, realSyncer)
 				if err != nil {
 					return errors.Wrapf(err, "start %s syncer", v.Name())
 				}
@@ -213,9 +341,13 @@ func RegisterControllers(ctx *context.ControllerContext, syncers []syncer.Object
 }
 
 func RegisterGenericSyncController(ctx *context.ControllerContext) error {
+	return RegisterGenericSyncControllerFromConfig(ctx, os.Getenv(context.GenericConfig))
+}
+
+// RegisterGenericSyncControllerFromConfig creates the generic sync controllers for the given raw configuration
+func RegisterGenericSyncControllerFromConfig(ctx *context.ControllerContext, c string) error {
 	// first check if a generic CRD config is provided and we actually need
 	// to create any of these syncer controllers
-	c := os.Getenv(context.GenericConfig)
 	if strings.TrimSpace(c) == "" || strings.TrimSpace(c) == "---" {
 		// empty configuration, no need for creating any syncer controllers
 		loghelper.Infof("no generic config provided, skipping creating controllers")
@@ -256,7 +388,11 @@ func RegisterInitManifestsController(ctx *context.ControllerContext) error {
 			MetricsBindAddress: "0",
 			LeaderElection:     false,
 			Namespace:          ctx.CurrentNamespace,
-			NewClient:          pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
+			Cache: cache.Options{
+				ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripUnusedFields),
+				DefaultTransform: cachetransform.StripManagedFields,
+			},
+			NewClient: pluginhookclient.NewPhysicalPluginClientFactory(blockingcacheclient.NewCacheClient),
 		})
 		if err != nil {
 			return err
@@ -284,7 +420,15 @@ func RegisterInitManifestsController(ctx *context.ControllerContext) error {
 		return err
 	}
 
-	helmBinaryPath, err := cmd.GetHelmBinaryPath(ctx.Context, log.GetInstance())
+	// the sdk helm client doesn't need the helm binary
+	helmBinaryPath := ""
+	if helm.NeedsBinary(helm.ClientType(ctx.Options.HelmClient)) {
+		helmBinaryPath, err = cmd.GetHelmBinaryPath(ctx.Context, log.GetInstance())
+		if err != nil {
+			return err
+		}
+	}
+	helmClient, err := helm.NewClientWithType(helm.ClientType(ctx.Options.HelmClient), &vConfigRaw, log.GetInstance(), helmBinaryPath)
 	if err != nil {
 		return err
 	}
@@ -294,7 +438,7 @@ func RegisterInitManifestsController(ctx *context.ControllerContext) error {
 		Log:            loghelper.New("init-manifests-controller"),
 		VirtualManager: ctx.VirtualManager,
 
-		HelmClient: helm.NewClient(&vConfigRaw, log.GetInstance(), helmBinaryPath),
+		HelmClient: helmClient,
 	}
 
 	err = controller.SetupWithManager(currentNamespaceManager)
@@ -326,7 +470,11 @@ func RegisterServiceSyncControllers(ctx *context.ControllerContext) error {
 			},
 			MetricsBindAddress: "0",
 			LeaderElection:     false,
-			NewClient:          blockingcacheclient.NewCacheClient,
+			Cache: cache.Options{
+				ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripData(cachetransform.KeepManagedData(ctx.CurrentNamespace))),
+				DefaultTransform: cachetransform.StripManagedFields,
+			},
+			NewClient: blockingcacheclient.NewCacheClient,
 		})
 		if err != nil {
 			return err
@@ -445,6 +593,48 @@ func RegisterCoreDNSController(ctx *context.ControllerContext) error {
 	return nil
 }
 
+func RegisterNetworkIsolationController(ctx *context.ControllerContext) error {
+	hostNamespace := ctx.Options.TargetNamespace
+	if ctx.Options.MultiNamespaceMode {
+		hostNamespace = ctx.CurrentNamespace
+	}
+
+	mapping, err := parseMapping(ctx.Options.MapHostServices, hostNamespace, "")
+	if err != nil {
+		return errors.Wrap(err, "parse physical service mapping")
+	}
+
+	// sort the host services, so the generated policy is stable
+	hostServices := []types.NamespacedName{}
+	for _, key := range sets.List(sets.KeySet(mapping)) {
+		splitted := strings.Split(key, "/")
+		hostServices = append(hostServices, types.NamespacedName{Namespace: splitted[0], Name: splitted[1]})
+	}
+
+	controller := &networkisolation.NetworkIsolationReconciler{
+		Client:             ctx.LocalManager.GetClient(),
+		APIReader:          ctx.LocalManager.GetAPIReader(),
+		Log:                loghelper.New("network-isolation-controller"),
+		CurrentNamespace:   ctx.CurrentNamespace,
+		TargetNamespace:    ctx.Options.TargetNamespace,
+		ServiceName:        ctx.Options.ServiceName,
+		MultiNamespaceMode: ctx.Options.MultiNamespaceMode,
+		HostServices:       hostServices,
+		EgressCIDRs:        ctx.Options.NetworkIsolationEgressCIDRs,
+		EgressExceptCIDRs:  ctx.Options.NetworkIsolationEgressExcept,
+	}
+	err = controller.Validate()
+	if err != nil {
+		return errors.Wrap(err, "network isolation")
+	}
+
+	err = controller.SetupWithManager(ctx.LocalManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup network isolation controller: %v", err)
+	}
+	return nil
+}
+
 func RegisterPodSecurityController(ctx *context.ControllerContext) error {
 	controller := &podsecurity.PodSecurityReconciler{
 		Client:              ctx.VirtualManager.GetClient(),
@@ -30,7 +30,9 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 type csistoragecapacitySyncer struct {
 	storageClassSyncEnabled     bool
 	hostStorageClassSyncEnabled bool
-	physicalClient              client.Client
+
+	// physicalClient is only used for reads outside of a sync, host writes go through the sync context
+	physicalClient client.Reader
 }
 
 var _ syncer.UpSyncer = &csistoragecapacitySyncer{}
@@ -7,7 +7,6 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
-	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -51,16 +50,15 @@ func (s *csistoragecapacitySyncer) VirtualToPhysical(ctx context.Context, req ty
 		}
 	}
 
-	sc := &storagev1.CSIStorageCapacity{}
-	pObj := sc.DeepCopyObject().(client.Object)
-	err := clienthelper.GetByIndex(ctx, s.physicalClient, pObj, constants.IndexByVirtualName, req.Name)
-	if err != nil {
+	list := &storagev1.CSIStorageCapacityList{}
+	err := s.physicalClient.List(ctx, list, client.MatchingFields{constants.IndexByVirtualName: req.Name})
+	if err != nil || len(list.Items) != 1 {
 		return types.NamespacedName{}
 	}
 
 	return types.NamespacedName{
-		Namespace: pObj.GetNamespace(),
-		Name:      pObj.GetName(),
+		Namespace: list.Items[0].Namespace,
+		Name:      list.Items[0].Name,
 	}
 }
 
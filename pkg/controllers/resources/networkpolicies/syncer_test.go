@@ -4,14 +4,17 @@ import (
 	"testing"
 
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"gotest.tools/assert"
 
 	podstranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -391,3 +394,118 @@ func TestSync(t *testing.T) {
 		},
 	})
 }
+
+func newFakeMultiNamespaceRegisterContext(pClient *testingutil.FakeIndexClient, vClient *testingutil.FakeIndexClient) *synccontext.RegisterContext {
+	ctx := generictesting.NewFakeRegisterContext(pClient, vClient)
+	ctx.Options.MultiNamespaceMode = true
+	translate.Default = translate.NewMultiNamespaceTranslator(ctx.CurrentNamespace)
+	return ctx
+}
+
+func TestSyncMultiNamespace(t *testing.T) {
+	translate.Default = translate.NewMultiNamespaceTranslator(generictesting.DefaultTestCurrentNamespace)
+
+	ports := []networkingv1.NetworkPolicyPort{{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: 80}}}
+	podSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}
+	vNamespaceSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "a"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "environment", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"dev"}},
+		},
+	}
+	vNetworkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testnetworkpolicy",
+			Namespace: "test",
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "backend"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: ports,
+					From: []networkingv1.NetworkPolicyPeer{
+						{PodSelector: podSelector},
+						{NamespaceSelector: &metav1.LabelSelector{}},
+						{PodSelector: podSelector, NamespaceSelector: vNamespaceSelector},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24", Except: []string{"10.0.0.0/30"}}},
+					},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+
+	pNamespaceSelector := translate.Default.TranslateLabelSelectorCluster(vNamespaceSelector)
+	pNamespaceSelector.MatchLabels[translate.MarkerLabel] = translate.MarkerLabelCluster(translate.Default)
+	pNetworkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testnetworkpolicy",
+			Namespace: translate.Default.PhysicalNamespace("test"),
+			Annotations: map[string]string{
+				translate.NameAnnotation:      vNetworkPolicy.Name,
+				translate.NamespaceAnnotation: vNetworkPolicy.Namespace,
+				translate.UIDAnnotation:       "",
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: vNetworkPolicy.Spec.PodSelector,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: ports,
+					From: []networkingv1.NetworkPolicyPeer{
+						{PodSelector: podSelector},
+						{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{translate.MarkerLabel: translate.MarkerLabelCluster(translate.Default)}}},
+						{PodSelector: podSelector, NamespaceSelector: pNamespaceSelector},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24", Except: []string{"10.0.0.0/30"}}},
+					},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+
+	// the translated namespace selector has to match the host namespaces created by the namespaces syncer
+	matchingNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"team": "a", "environment": "prod"}}}
+	otherNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"team": "a", "environment": "dev"}}}
+	selector, err := metav1.LabelSelectorAsSelector(pNamespaceSelector)
+	assert.NilError(t, err)
+	assert.Assert(t, selector.Matches(labels.Set(translate.Default.TranslateLabelsCluster(matchingNamespace, nil, nil))))
+	assert.Assert(t, !selector.Matches(labels.Set(translate.Default.TranslateLabelsCluster(otherNamespace, nil, nil))))
+	assert.Assert(t, !selector.Matches(labels.Set(matchingNamespace.Labels)))
+
+	generictesting.RunTestsWithContext(t, newFakeMultiNamespaceRegisterContext, []*generictesting.SyncTest{
+		{
+			Name:                "Create forward",
+			InitialVirtualState: []runtime.Object{vNetworkPolicy.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {vNetworkPolicy.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {pNetworkPolicy.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*networkPolicySyncer).SyncDown(syncCtx, vNetworkPolicy.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update forward",
+			InitialVirtualState:  []runtime.Object{vNetworkPolicy.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{&networkingv1.NetworkPolicy{ObjectMeta: pNetworkPolicy.ObjectMeta}},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {vNetworkPolicy.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {pNetworkPolicy.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				pObj := &networkingv1.NetworkPolicy{ObjectMeta: pNetworkPolicy.ObjectMeta}
+				pObj.ResourceVersion = "999"
+				_, err := syncer.(*networkPolicySyncer).Sync(syncCtx, pObj, vNetworkPolicy.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
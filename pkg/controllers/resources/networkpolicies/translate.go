@@ -60,20 +60,20 @@ func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *netw
 		})
 	}
 
-	// TODO(Multi-Namespace): add support for multi-namespace translation
-	if !translate.Default.SingleNamespaceTarget() {
-		panic("Multi-Namespace Mode not supported for network policies yet!")
+	if translate.Default.SingleNamespaceTarget() {
+		outSpec.PodSelector = *translate.Default.TranslateLabelSelector(&spec.PodSelector)
+		if outSpec.PodSelector.MatchLabels == nil {
+			outSpec.PodSelector.MatchLabels = map[string]string{}
+		}
+		// add selector for namespace as NetworkPolicy podSelector applies to pods within it's namespace
+		outSpec.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
+		// add selector for the marker label to select only from pods belonging this vcluster instance
+		outSpec.PodSelector.MatchLabels[translate.MarkerLabel] = translate.Suffix
+	} else {
+		// in multi-namespace mode the host namespace only contains pods of the virtual namespace
+		outSpec.PodSelector = *spec.PodSelector.DeepCopy()
 	}
 
-	outSpec.PodSelector = *translate.Default.TranslateLabelSelector(&spec.PodSelector)
-	if outSpec.PodSelector.MatchLabels == nil {
-		outSpec.PodSelector.MatchLabels = map[string]string{}
-	}
-	// add selector for namespace as NetworkPolicy podSelector applies to pods within it's namespace
-	outSpec.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
-	// add selector for the marker label to select only from pods belonging this vcluster instance
-	outSpec.PodSelector.MatchLabels[translate.MarkerLabel] = translate.Suffix
-
 	outSpec.PolicyTypes = spec.PolicyTypes
 	return outSpec
 }
@@ -84,26 +84,55 @@ func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespa
 	}
 	out := []networkingv1.NetworkPolicyPeer{}
 	for _, peer := range peers {
-		newPeer := networkingv1.NetworkPolicyPeer{
-			PodSelector:       translate.Default.TranslateLabelSelector(peer.PodSelector),
-			NamespaceSelector: nil, // must be set to nil as all vcluster pods are in the same host namespace as the NetworkPolicy
-		}
-		if peer.IPBlock == nil {
-			translatedNamespaceSelectors := translate.TranslateLabelSelectorWithPrefix(podstranslate.NamespaceLabelPrefix, peer.NamespaceSelector)
-			newPeer.PodSelector = translate.MergeLabelSelectors(newPeer.PodSelector, translatedNamespaceSelectors)
-
-			if newPeer.PodSelector.MatchLabels == nil {
-				newPeer.PodSelector.MatchLabels = map[string]string{}
-			}
-			if peer.NamespaceSelector == nil {
-				newPeer.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
-			}
-			// add selector for the marker label to select only from pods belonging this vcluster instance
-			newPeer.PodSelector.MatchLabels[translate.MarkerLabel] = translate.Suffix
+		if peer.IPBlock != nil {
+			// ip blocks can't be combined with selectors, so there is nothing to translate
+			out = append(out, networkingv1.NetworkPolicyPeer{IPBlock: peer.IPBlock.DeepCopy()})
+		} else if translate.Default.SingleNamespaceTarget() {
+			out = append(out, translateSingleNamespacePeer(peer, namespace))
 		} else {
-			newPeer.IPBlock = peer.IPBlock.DeepCopy()
+			out = append(out, translateMultiNamespacePeer(peer))
 		}
-		out = append(out, newPeer)
 	}
 	return out
 }
+
+func translateSingleNamespacePeer(peer networkingv1.NetworkPolicyPeer, namespace string) networkingv1.NetworkPolicyPeer {
+	newPeer := networkingv1.NetworkPolicyPeer{
+		PodSelector:       translate.Default.TranslateLabelSelector(peer.PodSelector),
+		NamespaceSelector: nil, // must be set to nil as all vcluster pods are in the same host namespace as the NetworkPolicy
+	}
+
+	translatedNamespaceSelectors := translate.TranslateLabelSelectorWithPrefix(podstranslate.NamespaceLabelPrefix, peer.NamespaceSelector)
+	newPeer.PodSelector = translate.MergeLabelSelectors(newPeer.PodSelector, translatedNamespaceSelectors)
+
+	if newPeer.PodSelector.MatchLabels == nil {
+		newPeer.PodSelector.MatchLabels = map[string]string{}
+	}
+	if peer.NamespaceSelector == nil {
+		newPeer.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
+	}
+	// add selector for the marker label to select only from pods belonging this vcluster instance
+	newPeer.PodSelector.MatchLabels[translate.MarkerLabel] = translate.Suffix
+	return newPeer
+}
+
+// translateMultiNamespacePeer translates a peer in multi-namespace mode, where every virtual namespace
+// is synced to its own host namespace. The namespaces syncer projects the virtual namespace labels onto
+// the host namespaces, so namespace selectors are translated the same way as these labels.
+func translateMultiNamespacePeer(peer networkingv1.NetworkPolicyPeer) networkingv1.NetworkPolicyPeer {
+	newPeer := networkingv1.NetworkPolicyPeer{
+		PodSelector: peer.PodSelector.DeepCopy(),
+	}
+	if peer.NamespaceSelector == nil {
+		// pods in the same namespace as the network policy
+		return newPeer
+	}
+
+	newPeer.NamespaceSelector = translate.Default.TranslateLabelSelectorCluster(peer.NamespaceSelector)
+	if newPeer.NamespaceSelector.MatchLabels == nil {
+		newPeer.NamespaceSelector.MatchLabels = map[string]string{}
+	}
+	// add selector for the marker label to select only namespaces belonging this vcluster instance
+	newPeer.NamespaceSelector.MatchLabels[translate.MarkerLabel] = translate.MarkerLabelCluster(translate.Default)
+	return newPeer
+}
@@ -79,7 +79,8 @@ type nodeSyncer struct {
 	useFakeKubelets     bool
 	fakeKubeletIPs      bool
 
-	physicalClient client.Client
+	// physicalClient is only used for reads outside of a sync, host writes go through the sync context
+	physicalClient client.Reader
 	virtualClient  client.Client
 
 	podCache            client.Reader
@@ -306,7 +307,7 @@ func (s *nodeSyncer) shouldSync(ctx context.Context, pObj *corev1.Node) (bool, e
 	return isNodeNeededByPod(ctx, s.virtualClient, s.physicalClient, pObj.Name)
 }
 
-func isNodeNeededByPod(ctx context.Context, virtualClient client.Client, physicalClient client.Client, nodeName string) (bool, error) {
+func isNodeNeededByPod(ctx context.Context, virtualClient client.Client, physicalClient client.Reader, nodeName string) (bool, error) {
 	// search virtual cache
 	podList := &corev1.PodList{}
 	err := virtualClient.List(ctx, podList, client.MatchingFields{constants.IndexByAssigned: nodeName})
@@ -0,0 +1,107 @@
+package pods
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/dryrun"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// tokenManager points the virtual rest config to a server that only answers token requests
+type tokenManager struct {
+	ctrl.Manager
+
+	config *rest.Config
+}
+
+func (m *tokenManager) GetConfig() *rest.Config { return m.config }
+
+func TestDryRunSync(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/token") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&authenticationv1.TokenRequest{
+			TypeMeta: metav1.TypeMeta{APIVersion: authenticationv1.SchemeGroupVersion.String(), Kind: "TokenRequest"},
+			Status:   authenticationv1.TokenRequestStatus{Token: "token"},
+		})
+	}))
+	defer tokenServer.Close()
+
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "testpod", Namespace: "testns"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name: "token",
+				VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	scheme := testingutil.NewScheme()
+	pClient := testingutil.NewFakeClient(scheme,
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: generictesting.DefaultTestVclusterServiceName, Namespace: generictesting.DefaultTestCurrentNamespace},
+			Spec:       corev1.ServiceSpec{ClusterIP: "1.2.3.4"},
+		},
+	)
+	vClient := testingutil.NewFakeClient(scheme, vPod.DeepCopy(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "testns"}})
+
+	ctx := generictesting.NewFakeRegisterContext(pClient, vClient)
+	translate.Suffix = generictesting.DefaultTestVclusterName
+	err := pClient.Create(ctx.Context, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: translate.Default.PhysicalName("kube-dns", "kube-system"), Namespace: generictesting.DefaultTestTargetNamespace},
+		Spec:       corev1.ServiceSpec{ClusterIP: "2.2.2.2"},
+	})
+	assert.NilError(t, err)
+	ctx.Options.ServiceAccountTokenSecrets = true
+	ctx.VirtualManager = &tokenManager{Manager: ctx.VirtualManager, config: &rest.Config{Host: tokenServer.URL}}
+	syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+
+	// the syncer controller wraps the physical client the same way for dry run syncers
+	recorder := dryrun.NewRecorder()
+	syncCtx.PhysicalClient = dryrun.NewClient(syncCtx.PhysicalClient, pClient, recorder, syncer.Name(), vPod, nil)
+	_, err = syncer.(*podSyncer).SyncDown(syncCtx, vPod.DeepCopy())
+	assert.NilError(t, err)
+
+	// neither the token secret nor the pod were created in the host cluster
+	secrets := &corev1.SecretList{}
+	assert.NilError(t, pClient.List(ctx.Context, secrets))
+	assert.Equal(t, len(secrets.Items), 0)
+	pods := &corev1.PodList{}
+	assert.NilError(t, pClient.List(ctx.Context, pods))
+	assert.Equal(t, len(pods.Items), 0)
+
+	// but both writes were recorded
+	report := recorder.Report()
+	assert.Equal(t, len(report.Items), 2)
+	kinds := map[string]string{}
+	for _, item := range report.Items {
+		assert.Equal(t, item.Operation, dryrun.OperationCreate)
+		assert.Equal(t, item.Error, "")
+		kinds[item.Kind] = item.Name
+	}
+	assert.Equal(t, kinds["Secret"], podtranslate.SecretNameFromPodName(vPod.Name, vPod.Namespace))
+	assert.Equal(t, kinds["Pod"], translate.Default.PhysicalName(vPod.Name, vPod.Namespace))
+}
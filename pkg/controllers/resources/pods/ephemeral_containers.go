@@ -3,24 +3,27 @@ package pods
 import (
 	"encoding/json"
 	"fmt"
+
+	translatepods "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
-	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // AddEphemeralContainer runs an EphemeralContainer in the target Pod for use as a debug container
-func AddEphemeralContainer(ctx *synccontext.SyncContext, physicalClusterClient kubernetes.Interface, physicalPod *corev1.Pod, virtualPod *corev1.Pod) error {
+func AddEphemeralContainer(ctx *synccontext.SyncContext, physicalPod *corev1.Pod, virtualPod *corev1.Pod, imageTranslator translatepods.ImageTranslator) error {
 	if len(virtualPod.Spec.EphemeralContainers) > 0 {
 		podJS, err := json.Marshal(physicalPod)
 		if err != nil {
 			return fmt.Errorf("error creating JSON for physicalPod: %v", err)
 		}
-		debugPod, debugContainer, err := getEphemeralContainer(physicalPod, virtualPod)
+		debugPod, debugContainer, err := getEphemeralContainer(physicalPod, virtualPod, imageTranslator)
 		if err != nil {
 			return err
 		}
@@ -37,8 +40,8 @@ func AddEphemeralContainer(ctx *synccontext.SyncContext, physicalClusterClient k
 		}
 		ctx.Log.Debugf("generated strategic merge patch for debug container: %s", patch)
 
-		pods := physicalClusterClient.CoreV1().Pods(physicalPod.Namespace)
-		_, err = pods.Patch(ctx.Context, physicalPod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "ephemeralcontainers")
+		// the patch goes through the physical client of the sync context, so dry run syncers only record it
+		err = ctx.PhysicalClient.SubResource("ephemeralcontainers").Patch(ctx.Context, physicalPod.DeepCopy(), client.RawPatch(types.StrategicMergePatchType, patch))
 		if err != nil {
 			// The apiserver will return a 404 when the EphemeralContainers feature is disabled because the `/ephemeralcontainers` subresource
 			// is missing. Unlike the 404 returned by a missing physicalPod, the status details will be empty.
@@ -50,7 +53,7 @@ func AddEphemeralContainer(ctx *synccontext.SyncContext, physicalClusterClient k
 			// using the old API.
 			if runtime.IsNotRegisteredError(err) {
 				ctx.Log.Infof("Falling back to legacy API because server returned error: %v", err)
-				return addEphemeralContainerLegacy(ctx, physicalClusterClient, physicalPod, debugContainer)
+				return addEphemeralContainerLegacy(ctx, physicalPod, debugContainer)
 			}
 			return err
 		}
@@ -61,7 +64,7 @@ func AddEphemeralContainer(ctx *synccontext.SyncContext, physicalClusterClient k
 
 // addEphemeralContainerLegacy adds an ephemeral container using the pre-1.22 /ephemeralcontainers API
 // This may be removed when we no longer wish to support releases prior to 1.22.
-func addEphemeralContainerLegacy(ctx *synccontext.SyncContext, physicalClusterClient kubernetes.Interface, physicalPod *corev1.Pod, debugContainer *corev1.EphemeralContainer) error {
+func addEphemeralContainerLegacy(ctx *synccontext.SyncContext, physicalPod *corev1.Pod, debugContainer *corev1.EphemeralContainer) error {
 	// We no longer have the v1.EphemeralContainers Kind since it was removed in 1.22, but
 	// we can present a JSON 6902 patch that the api server will apply.
 	patch, err := json.Marshal([]map[string]interface{}{{
@@ -73,30 +76,26 @@ func addEphemeralContainerLegacy(ctx *synccontext.SyncContext, physicalClusterCl
 		return fmt.Errorf("error creating JSON 6902 patch for old /ephemeralcontainers API: %s", err)
 	}
 
-	result := physicalClusterClient.CoreV1().RESTClient().Patch(types.JSONPatchType).
-		Namespace(physicalPod.Namespace).
-		Resource("pods").
-		Name(physicalPod.Name).
-		SubResource("ephemeralcontainers").
-		Body(patch).
-		Do(ctx.Context)
-	if err := result.Error(); err != nil {
-		return err
-	}
-
-	_, err = physicalClusterClient.CoreV1().Pods(physicalPod.Namespace).Get(ctx.Context, physicalPod.Name, metav1.GetOptions{})
+	// the old api responds with an EphemeralContainers object, which can only be decoded as unstructured
+	pod := &unstructured.Unstructured{}
+	pod.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	pod.SetNamespace(physicalPod.Namespace)
+	pod.SetName(physicalPod.Name)
+	err = ctx.PhysicalClient.SubResource("ephemeralcontainers").Patch(ctx.Context, pod, client.RawPatch(types.JSONPatchType, patch))
 	if err != nil {
 		return err
 	}
+
 	return nil
 }
 
 // getEphemeralContainer returns a debugging pod and an EphemeralContainer suitable for use as a debug container
-// in the given pod.
-func getEphemeralContainer(physicalPod *corev1.Pod, virtualPod *corev1.Pod) (*corev1.Pod, *corev1.EphemeralContainer, error) {
-	ephemeralContainer := virtualPod.Spec.EphemeralContainers[len(virtualPod.Spec.EphemeralContainers)-1]
+// in the given pod. The image of the container is rewritten the same way as the images of regular containers.
+func getEphemeralContainer(physicalPod *corev1.Pod, virtualPod *corev1.Pod, imageTranslator translatepods.ImageTranslator) (*corev1.Pod, *corev1.EphemeralContainer, error) {
+	ephemeralContainer := *virtualPod.Spec.EphemeralContainers[len(virtualPod.Spec.EphemeralContainers)-1].DeepCopy()
 	copied := physicalPod.DeepCopy()
 	ephemeralContainer.TargetContainerName = ""
+	ephemeralContainer.Image = imageTranslator.Translate(ephemeralContainer.Image)
 	copied.Spec.EphemeralContainers = append(copied.Spec.EphemeralContainers, ephemeralContainer)
 	return copied, &ephemeralContainer, nil
 }
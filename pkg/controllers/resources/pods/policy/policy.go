@@ -0,0 +1,318 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+const Version = "v1beta1"
+
+// Policy mutates the translated host pods of a virtual cluster
+type Policy struct {
+	// Version is the policy version
+	Version string `json:"version,omitempty"`
+
+	// Rules are evaluated in order and all matching rules are applied,
+	// so later rules can override earlier ones
+	Rules []*Rule `json:"rules,omitempty"`
+}
+
+type Rule struct {
+	// Name of the rule, only used for error messages
+	Name string `json:"name,omitempty"`
+
+	// NamespaceSelector selects the virtual namespaces whose pods this rule applies to.
+	// If empty, the rule applies to all pods.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Override replaces fields that are already set on the pod. By default, the rule
+	// only fills fields that are not set.
+	Override bool `json:"override,omitempty"`
+
+	// Labels are added to the host pod
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the host pod
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// SecurityContext is merged into the pod security context, this includes the seccomp profile
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// ContainerSecurityContext is merged into the security context of every container
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// RuntimeClassName is the host runtime class to use
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// PriorityClass limits the priority of pods
+	PriorityClass *PriorityClass `json:"priorityClass,omitempty"`
+
+	// TopologySpreadConstraints are set on the pod
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Sidecars are added to the pod, containers with the same name are not replaced
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	selector labels.Selector
+}
+
+type PriorityClass struct {
+	// Name is the host priority class that is used for pods that exceed MaxPriority
+	Name string `json:"name,omitempty"`
+
+	// MaxPriority is the highest priority a virtual pod can have. If unset, Name is
+	// used for all pods.
+	MaxPriority *int32 `json:"maxPriority,omitempty"`
+}
+
+// Load reads and parses the policy file at the given path
+func Load(path string) (*Policy, error) {
+	out, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read pod mutation policy: %w", err)
+	}
+
+	return Parse(out)
+}
+
+func Parse(rawPolicy []byte) (*Policy, error) {
+	p := &Policy{}
+	err := yaml.UnmarshalStrict(rawPolicy, p)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Policy) validate() error {
+	if p.Version != Version {
+		return fmt.Errorf("unsupported pod mutation policy version. Only %s is supported currently", Version)
+	}
+
+	for idx, rule := range p.Rules {
+		if rule == nil {
+			return fmt.Errorf("rules[%d] is required", idx)
+		}
+
+		var err error
+		rule.selector = labels.Everything()
+		if rule.NamespaceSelector != nil {
+			rule.selector, err = metav1.LabelSelectorAsSelector(rule.NamespaceSelector)
+			if err != nil {
+				return fmt.Errorf("rules[%d].namespaceSelector: %w", idx, err)
+			}
+		}
+
+		if rule.PriorityClass != nil && rule.PriorityClass.Name == "" {
+			return fmt.Errorf("rules[%d].priorityClass.name is required", idx)
+		}
+
+		names := map[string]bool{}
+		for containerIdx, container := range rule.Sidecars {
+			if container.Name == "" || container.Image == "" {
+				return fmt.Errorf("rules[%d].sidecars[%d]: name and image are required", idx, containerIdx)
+			} else if names[container.Name] {
+				return fmt.Errorf("rules[%d].sidecars[%d]: duplicate name %s", idx, containerIdx, container.Name)
+			}
+
+			names[container.Name] = true
+		}
+	}
+
+	return nil
+}
+
+// Apply mutates the translated host pod with all rules that match the virtual namespace
+func (p *Policy) Apply(vNamespace *corev1.Namespace, vPod, pPod *corev1.Pod) error {
+	for _, rule := range p.matchingRules(vNamespace) {
+		err := rule.apply(vPod, pPod)
+		if err != nil {
+			return fmt.Errorf("apply pod mutation policy rule %s: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ApplyMetadata adds the labels and annotations of all rules that match the virtual namespace
+// to the given maps. This is used to keep the metadata in sync when the host pod is updated.
+func (p *Policy) ApplyMetadata(vNamespace *corev1.Namespace, labels, annotations map[string]string) {
+	for _, rule := range p.matchingRules(vNamespace) {
+		rule.applyMetadata(labels, annotations)
+	}
+}
+
+func (p *Policy) matchingRules(vNamespace *corev1.Namespace) []*Rule {
+	if p == nil {
+		return nil
+	}
+
+	rules := []*Rule{}
+	for _, rule := range p.Rules {
+		if rule.selector.Matches(labels.Set(vNamespace.Labels)) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func (r *Rule) apply(vPod, pPod *corev1.Pod) error {
+	if pPod.Labels == nil {
+		pPod.Labels = map[string]string{}
+	}
+	if pPod.Annotations == nil {
+		pPod.Annotations = map[string]string{}
+	}
+	r.applyMetadata(pPod.Labels, pPod.Annotations)
+
+	if r.SecurityContext != nil {
+		if pPod.Spec.SecurityContext == nil {
+			pPod.Spec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+
+		err := merge(pPod.Spec.SecurityContext, r.SecurityContext, r.Override)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.RuntimeClassName != nil && (r.Override || pPod.Spec.RuntimeClassName == nil) {
+		runtimeClassName := *r.RuntimeClassName
+		pPod.Spec.RuntimeClassName = &runtimeClassName
+	}
+
+	if len(r.TopologySpreadConstraints) > 0 && (r.Override || len(pPod.Spec.TopologySpreadConstraints) == 0) {
+		pPod.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{}
+		for _, constraint := range r.TopologySpreadConstraints {
+			pPod.Spec.TopologySpreadConstraints = append(pPod.Spec.TopologySpreadConstraints, *constraint.DeepCopy())
+		}
+	}
+
+	// the priority of the virtual pod was resolved by the virtual cluster
+	if r.PriorityClass != nil && (r.PriorityClass.MaxPriority == nil || vPod.Spec.Priority == nil || *vPod.Spec.Priority > *r.PriorityClass.MaxPriority) {
+		pPod.Spec.PriorityClassName = r.PriorityClass.Name
+		pPod.Spec.Priority = nil
+		pPod.Spec.PreemptionPolicy = nil
+	}
+
+	for _, sidecar := range r.Sidecars {
+		if !hasContainer(pPod.Spec.Containers, sidecar.Name) {
+			pPod.Spec.Containers = append(pPod.Spec.Containers, *sidecar.DeepCopy())
+		}
+	}
+
+	if r.ContainerSecurityContext != nil {
+		for i := range pPod.Spec.InitContainers {
+			err := mergeContainerSecurityContext(&pPod.Spec.InitContainers[i], r.ContainerSecurityContext, r.Override)
+			if err != nil {
+				return err
+			}
+		}
+		for i := range pPod.Spec.Containers {
+			err := mergeContainerSecurityContext(&pPod.Spec.Containers[i], r.ContainerSecurityContext, r.Override)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) applyMetadata(labels, annotations map[string]string) {
+	for k, v := range r.Labels {
+		if _, ok := labels[k]; r.Override || !ok {
+			labels[k] = v
+		}
+	}
+	for k, v := range r.Annotations {
+		if _, ok := annotations[k]; r.Override || !ok {
+			annotations[k] = v
+		}
+	}
+}
+
+func mergeContainerSecurityContext(container *corev1.Container, securityContext *corev1.SecurityContext, override bool) error {
+	if container.SecurityContext == nil {
+		container.SecurityContext = &corev1.SecurityContext{}
+	}
+
+	return merge(container.SecurityContext, securityContext, override)
+}
+
+func hasContainer(containers []corev1.Container, name string) bool {
+	for _, container := range containers {
+		if container.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// merge merges src into dst. If override is false, only fields that are not set in dst are taken from src.
+func merge(dst, src interface{}, override bool) error {
+	dstMap, err := toMap(dst)
+	if err != nil {
+		return err
+	}
+	srcMap, err := toMap(src)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.Marshal(mergeMaps(dstMap, srcMap, override))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(out, dst)
+}
+
+func mergeMaps(dst, src map[string]interface{}, override bool) map[string]interface{} {
+	for k, srcValue := range src {
+		dstValue, ok := dst[k]
+		if !ok {
+			dst[k] = srcValue
+			continue
+		}
+
+		dstChild, dstIsMap := dstValue.(map[string]interface{})
+		srcChild, srcIsMap := srcValue.(map[string]interface{})
+		if dstIsMap && srcIsMap {
+			dst[k] = mergeMaps(dstChild, srcChild, override)
+		} else if override {
+			dst[k] = srcValue
+		}
+	}
+
+	return dst
+}
+
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]interface{}{}
+	err = json.Unmarshal(out, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,146 @@
+package policy
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+const rawPolicy = `version: v1beta1
+rules:
+- name: defaults
+  labels:
+    team: platform
+  securityContext:
+    runAsNonRoot: true
+    seccompProfile:
+      type: RuntimeDefault
+  containerSecurityContext:
+    allowPrivilegeEscalation: false
+  priorityClass:
+    name: tenant-default
+    maxPriority: 1000
+- name: sandboxed
+  namespaceSelector:
+    matchLabels:
+      sandbox: "true"
+  override: true
+  runtimeClassName: gvisor
+  labels:
+    team: sandbox
+  sidecars:
+  - name: log-shipper
+    image: fluent/fluent-bit:2.1
+`
+
+func TestApply(t *testing.T) {
+	p, err := Parse([]byte(rawPolicy))
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name      string
+		namespace *corev1.Namespace
+		vPod      *corev1.Pod
+		pPod      *corev1.Pod
+		expected  *corev1.Pod
+	}{
+		{
+			name:      "defaults",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			vPod: &corev1.Pod{
+				Spec: corev1.PodSpec{Priority: pointer.Int32(2000)},
+			},
+			pPod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					PriorityClassName: "high-x-vcluster",
+					Priority:          pointer.Int32(2000),
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: pointer.Bool(false),
+						RunAsUser:    pointer.Int64(1000),
+					},
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+				},
+			},
+			expected: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"team": "platform"},
+					Annotations: map[string]string{},
+				},
+				Spec: corev1.PodSpec{
+					PriorityClassName: "tenant-default",
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   pointer.Bool(false),
+						RunAsUser:      pointer.Int64(1000),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Containers: []corev1.Container{{
+						Name:            "nginx",
+						Image:           "nginx",
+						SecurityContext: &corev1.SecurityContext{AllowPrivilegeEscalation: pointer.Bool(false)},
+					}},
+				},
+			},
+		},
+		{
+			name:      "sandboxed",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"sandbox": "true"}}},
+			vPod: &corev1.Pod{
+				Spec: corev1.PodSpec{Priority: pointer.Int32(10)},
+			},
+			pPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"team": "tenant"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+				},
+			},
+			expected: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"team": "sandbox"},
+					Annotations: map[string]string{},
+				},
+				Spec: corev1.PodSpec{
+					RuntimeClassName: pointer.String("gvisor"),
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   pointer.Bool(true),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            "nginx",
+							Image:           "nginx",
+							SecurityContext: &corev1.SecurityContext{AllowPrivilegeEscalation: pointer.Bool(false)},
+						},
+						{
+							Name:  "log-shipper",
+							Image: "fluent/fluent-bit:2.1",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := p.Apply(testCase.namespace, testCase.vPod, testCase.pPod)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		assert.DeepEqual(t, testCase.pPod, testCase.expected)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, raw := range []string{
+		"rules: []",
+		"version: v1beta1\nrules:\n- priorityClass:\n    maxPriority: 10",
+		"version: v1beta1\nrules:\n- sidecars:\n  - name: test",
+		"version: v1beta1\nrules:\n- namespaceSelector:\n    matchExpressions:\n    - key: a\n      operator: Foo",
+		"version: v1beta1\nunknown: true",
+	} {
+		_, err := Parse([]byte(raw))
+		assert.Assert(t, err != nil, "expected error for policy %s", raw)
+	}
+}
@@ -42,10 +42,6 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 	if err != nil {
 		return nil, err
 	}
-	physicalClusterClient, err := kubernetes.NewForConfig(ctx.PhysicalManager.GetConfig())
-	if err != nil {
-		return nil, err
-	}
 
 	// parse node selector
 	var nodeSelector *metav1.LabelSelector
@@ -80,6 +76,12 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		return nil, errors.Wrap(err, "create pod translator")
 	}
 
+	// create image translator for ephemeral containers
+	imageTranslator, err := translatepods.NewImageTranslator(ctx.Options.TranslateImages, ctx.Options.ImageRewriteRules, ctx.Options.ImagePullSecrets)
+	if err != nil {
+		return nil, errors.Wrap(err, "create image translator")
+	}
+
 	return &podSyncer{
 		NamespacedTranslator: namespacedTranslator,
 
@@ -87,9 +89,9 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		enableScheduler: ctx.Options.EnableScheduler,
 
 		virtualClusterClient:  virtualClusterClient,
-		physicalClusterClient: physicalClusterClient,
 		physicalClusterConfig: ctx.PhysicalManager.GetConfig(),
 		podTranslator:         podTranslator,
+		imageTranslator:       imageTranslator,
 		nodeSelector:          nodeSelector,
 		tolerations:           tolerations,
 
@@ -104,8 +106,8 @@ type podSyncer struct {
 	enableScheduler bool
 
 	podTranslator         translatepods.Translator
+	imageTranslator       translatepods.ImageTranslator
 	virtualClusterClient  kubernetes.Interface
-	physicalClusterClient kubernetes.Interface
 	physicalClusterConfig *rest.Config
 	nodeSelector          *metav1.LabelSelector
 	tolerations           []*corev1.Toleration
@@ -309,7 +311,7 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 	}
 
 	// sync ephemeral containers
-	if syncEphemeralContainers(vPod, strippedPod) {
+	if syncEphemeralContainers(vPod, strippedPod, s.imageTranslator) {
 		kubeIP, _, ptrServiceList, err := s.getK8sIPDNSIPServiceList(ctx, vPod)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -324,7 +326,7 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		}
 
 		// add ephemeralContainers subresource to physical pod
-		err = AddEphemeralContainer(ctx, s.physicalClusterClient, pPod, vPod)
+		err = AddEphemeralContainer(ctx, pPod, vPod, s.imageTranslator)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -351,7 +353,7 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 	return s.SyncDownUpdate(ctx, vPod, updatedPod)
 }
 
-func syncEphemeralContainers(vPod *corev1.Pod, pPod *corev1.Pod) bool {
+func syncEphemeralContainers(vPod *corev1.Pod, pPod *corev1.Pod, imageTranslator translatepods.ImageTranslator) bool {
 	if vPod.Spec.EphemeralContainers == nil {
 		return false
 	}
@@ -359,7 +361,7 @@ func syncEphemeralContainers(vPod *corev1.Pod, pPod *corev1.Pod) bool {
 		return true
 	}
 	for i := range vPod.Spec.EphemeralContainers {
-		if vPod.Spec.EphemeralContainers[i].Image != pPod.Spec.EphemeralContainers[i].Image {
+		if imageTranslator.Translate(vPod.Spec.EphemeralContainers[i].Image) != pPod.Spec.EphemeralContainers[i].Image {
 			return true
 		}
 		if vPod.Spec.EphemeralContainers[i].Name != pPod.Spec.EphemeralContainers[i].Name {
@@ -19,7 +19,7 @@ func (s *podSyncer) translate(ctx *synccontext.SyncContext, vPod *corev1.Pod) (*
 		return nil, err
 	}
 
-	pPod, err := s.podTranslator.Translate(ctx.Context, vPod, ptrServiceList, dnsIP, kubeIP)
+	pPod, err := s.podTranslator.Translate(ctx.Context, ctx.PhysicalClient, vPod, ptrServiceList, dnsIP, kubeIP)
 	if err != nil {
 		return nil, err
 	}
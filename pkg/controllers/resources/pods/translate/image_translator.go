@@ -2,18 +2,47 @@ package translate
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+const (
+	// RegexRewriteRulePrefix marks a rewrite rule source as go regular expression
+	RegexRewriteRulePrefix = "regex:"
+
+	defaultRegistry        = "docker.io"
+	defaultRegistryLibrary = "library"
+)
+
 type ImageTranslator interface {
+	// Translate returns the host image for the given virtual image
 	Translate(image string) string
+
+	// PullSecrets returns the host image pull secrets needed to pull the given host image
+	PullSecrets(image string) []string
 }
 
 type imageTranslator struct {
 	translateImages map[string]string
+	rewriteRules    []*rewriteRule
+	pullSecrets     []*pullSecret
+}
+
+type rewriteRule struct {
+	source *regexp.Regexp
+	target string
+}
+
+type pullSecret struct {
+	registry string
+	name     string
 }
 
-func NewImageTranslator(translateImages []string) (ImageTranslator, error) {
+// NewImageTranslator creates a new image translator. translateImages are exact image remaps in the
+// form image1=image2, rewriteRules are ordered rules in the form docker.io/*=mirror.corp/dockerhub/*
+// or regex:^docker\.io/(.*)$=mirror.corp/dockerhub/$1 and pullSecrets are host secrets in the
+// form registry=secret or secret that are added to every pod pulling from that registry.
+func NewImageTranslator(translateImages, rewriteRules, pullSecrets []string) (ImageTranslator, error) {
 	translateImagesMap := map[string]string{}
 	for _, t := range translateImages {
 		i := strings.Split(strings.TrimSpace(t), "=")
@@ -24,8 +53,69 @@ func NewImageTranslator(translateImages []string) (ImageTranslator, error) {
 		translateImagesMap[i[0]] = i[1]
 	}
 
+	parsedRules := []*rewriteRule{}
+	for _, r := range rewriteRules {
+		rule, err := parseRewriteRule(r)
+		if err != nil {
+			return nil, err
+		}
+
+		parsedRules = append(parsedRules, rule)
+	}
+
+	parsedPullSecrets := []*pullSecret{}
+	for _, s := range pullSecrets {
+		i := strings.Split(strings.TrimSpace(s), "=")
+		if len(i) == 1 && i[0] != "" {
+			parsedPullSecrets = append(parsedPullSecrets, &pullSecret{name: i[0]})
+		} else if len(i) == 2 && i[0] != "" && i[1] != "" {
+			parsedPullSecrets = append(parsedPullSecrets, &pullSecret{registry: i[0], name: i[1]})
+		} else {
+			return nil, fmt.Errorf("error parsing image pull secret '%s': bad format expected registry=secret or secret", s)
+		}
+	}
+
 	return &imageTranslator{
 		translateImages: translateImagesMap,
+		rewriteRules:    parsedRules,
+		pullSecrets:     parsedPullSecrets,
+	}, nil
+}
+
+func parseRewriteRule(rule string) (*rewriteRule, error) {
+	i := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+	if len(i) != 2 || i[0] == "" || i[1] == "" {
+		return nil, fmt.Errorf("error parsing image rewrite rule '%s': bad format expected source=target", rule)
+	}
+
+	// regular expression rule
+	if strings.HasPrefix(i[0], RegexRewriteRulePrefix) {
+		source, err := regexp.Compile(strings.TrimPrefix(i[0], RegexRewriteRulePrefix))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing image rewrite rule '%s': %w", rule, err)
+		}
+
+		return &rewriteRule{source: source, target: i[1]}, nil
+	}
+
+	// wildcard rule, each * in the target is replaced with the matching * of the source
+	sourceParts := strings.Split(i[0], "*")
+	if strings.Count(i[1], "*") > len(sourceParts)-1 {
+		return nil, fmt.Errorf("error parsing image rewrite rule '%s': target has more wildcards than source", rule)
+	}
+	for idx := range sourceParts {
+		sourceParts[idx] = regexp.QuoteMeta(sourceParts[idx])
+	}
+
+	targetParts := strings.Split(strings.ReplaceAll(i[1], "$", "$$"), "*")
+	target := targetParts[0]
+	for idx := 1; idx < len(targetParts); idx++ {
+		target += fmt.Sprintf("${%d}", idx) + targetParts[idx]
+	}
+
+	return &rewriteRule{
+		source: regexp.MustCompile("^" + strings.Join(sourceParts, "(.*)") + "$"),
+		target: target,
 	}, nil
 }
 
@@ -35,5 +125,62 @@ func (i *imageTranslator) Translate(image string) string {
 		return out
 	}
 
+	// the first matching rule wins, we check the image as specified and fully qualified
+	// so that rules for docker.io/* also match images like nginx:latest
+	for _, rule := range i.rewriteRules {
+		for _, candidate := range imageCandidates(image) {
+			if rule.source.MatchString(candidate) {
+				return rule.source.ReplaceAllString(candidate, rule.target)
+			}
+		}
+	}
+
 	return image
 }
+
+func (i *imageTranslator) PullSecrets(image string) []string {
+	secrets := []string{}
+	registry := imageRegistry(image)
+	for _, s := range i.pullSecrets {
+		if s.registry == "" || s.registry == registry {
+			secrets = append(secrets, s.name)
+		}
+	}
+
+	return secrets
+}
+
+func imageCandidates(image string) []string {
+	normalized := normalizeImage(image)
+	if normalized == image {
+		return []string{image}
+	}
+
+	return []string{image, normalized}
+}
+
+// normalizeImage returns the fully qualified name of an image, e.g. nginx becomes docker.io/library/nginx
+func normalizeImage(image string) string {
+	if !hasRegistry(image) {
+		if !strings.Contains(image, "/") {
+			image = defaultRegistryLibrary + "/" + image
+		}
+
+		image = defaultRegistry + "/" + image
+	}
+
+	return image
+}
+
+func imageRegistry(image string) string {
+	if !hasRegistry(image) {
+		return defaultRegistry
+	}
+
+	return strings.SplitN(image, "/", 2)[0]
+}
+
+func hasRegistry(image string) bool {
+	i := strings.SplitN(image, "/", 2)
+	return len(i) == 2 && (strings.ContainsAny(i[0], ".:") || i[0] == "localhost")
+}
@@ -0,0 +1,98 @@
+package translate
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestImageTranslator(t *testing.T) {
+	imageTranslator, err := NewImageTranslator(
+		[]string{"coredns/coredns=mirror.io/coredns/coredns"},
+		[]string{
+			"quay.io/*=mirror.corp/quay/*",
+			`regex:^docker\.io/library/(.*)$=mirror.corp/library/$1`,
+			"docker.io/*=mirror.corp/dockerhub/*",
+		},
+		[]string{"mirror.corp=mirror-pull-secret", "global-pull-secret"},
+	)
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name        string
+		image       string
+		expected    string
+		pullSecrets []string
+	}{
+		{
+			name:        "exact remap wins",
+			image:       "coredns/coredns",
+			expected:    "mirror.io/coredns/coredns",
+			pullSecrets: []string{"global-pull-secret"},
+		},
+		{
+			name:        "wildcard",
+			image:       "quay.io/prometheus/prometheus:v2.44.0",
+			expected:    "mirror.corp/quay/prometheus/prometheus:v2.44.0",
+			pullSecrets: []string{"mirror-pull-secret", "global-pull-secret"},
+		},
+		{
+			name:        "regex on normalized image",
+			image:       "nginx:latest",
+			expected:    "mirror.corp/library/nginx:latest",
+			pullSecrets: []string{"mirror-pull-secret", "global-pull-secret"},
+		},
+		{
+			name:        "wildcard on normalized image",
+			image:       "bitnami/redis",
+			expected:    "mirror.corp/dockerhub/bitnami/redis",
+			pullSecrets: []string{"mirror-pull-secret", "global-pull-secret"},
+		},
+		{
+			name:        "no match",
+			image:       "registry.k8s.io/pause:3.9",
+			expected:    "registry.k8s.io/pause:3.9",
+			pullSecrets: []string{"global-pull-secret"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		translated := imageTranslator.Translate(testCase.image)
+		assert.Equal(t, translated, testCase.expected, "unexpected image in test case %s", testCase.name)
+		assert.DeepEqual(t, imageTranslator.PullSecrets(translated), testCase.pullSecrets)
+	}
+}
+
+func TestImageTranslatorInvalidRules(t *testing.T) {
+	for _, rule := range []string{"docker.io/*", "=mirror.corp/*", "docker.io/=mirror.corp/*", "regex:(=mirror.corp"} {
+		_, err := NewImageTranslator(nil, []string{rule}, nil)
+		assert.Assert(t, err != nil, "expected error for rule %s", rule)
+	}
+
+	_, err := NewImageTranslator(nil, nil, []string{"a=b=c"})
+	assert.Assert(t, err != nil)
+}
+
+func TestImagePullSecretInjection(t *testing.T) {
+	imageTranslator, err := NewImageTranslator(nil, []string{"docker.io/*=mirror.corp/dockerhub/*"}, []string{"mirror.corp=mirror-pull-secret"})
+	assert.NilError(t, err)
+
+	tr := &translator{imageTranslator: imageTranslator}
+	pPod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "my-secret-x-default-x-suffix"}},
+			InitContainers:   []corev1.Container{{Name: "init", Image: imageTranslator.Translate("busybox")}},
+			Containers:       []corev1.Container{{Name: "nginx", Image: imageTranslator.Translate("nginx")}},
+		},
+	}
+	tr.injectImagePullSecrets(pPod)
+	assert.DeepEqual(t, pPod.Spec.ImagePullSecrets, []corev1.LocalObjectReference{
+		{Name: "my-secret-x-default-x-suffix"},
+		{Name: "mirror-pull-secret"},
+	})
+
+	// rewritten images should not be seen as changed
+	vContainers := []corev1.Container{{Name: "nginx", Image: "nginx"}}
+	assert.Assert(t, calcContainerImageDiff(pPod.Spec.Containers, vContainers, imageTranslator, nil) == nil)
+}
@@ -86,6 +86,8 @@ func SetPodAsOwner(ctx context.Context, pPod *corev1.Pod, pClient client.Client,
 		UID:        pPod.GetUID(),
 	}
 
+	// the host cache only keeps the metadata of the secret, so it is patched instead of updated
+	originalSecret := secret.DeepCopy()
 	owners := secret.GetOwnerReferences()
 
 	if translate.Owner != nil {
@@ -111,5 +113,5 @@ func SetPodAsOwner(ctx context.Context, pPod *corev1.Pod, pClient client.Client,
 		secret.ObjectMeta.OwnerReferences = append(secret.ObjectMeta.OwnerReferences, podOwnerReference)
 	}
 
-	return pClient.Update(ctx, secret)
+	return pClient.Patch(ctx, secret, client.MergeFrom(originalSecret))
 }
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/configmaps"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/pods/policy"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/priorityclasses"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
@@ -49,16 +50,15 @@ var (
 	FieldPathLabelRegEx      = regexp.MustCompile(`^metadata\.labels\['(.+)'\]$`)
 	FieldPathAnnotationRegEx = regexp.MustCompile(`^metadata\.annotations\['(.+)'\]$`)
 	False                    = false
-	maxPriority              = int32(1000000000)
 )
 
 type Translator interface {
-	Translate(ctx context.Context, vPod *corev1.Pod, services []*corev1.Service, dnsIP string, kubeIP string) (*corev1.Pod, error)
+	Translate(ctx context.Context, pClient client.Client, vPod *corev1.Pod, services []*corev1.Service, dnsIP string, kubeIP string) (*corev1.Pod, error)
 	Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.Pod, error)
 }
 
 func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventRecorder) (Translator, error) {
-	imageTranslator, err := NewImageTranslator(ctx.Options.TranslateImages)
+	imageTranslator, err := NewImageTranslator(ctx.Options.TranslateImages, ctx.Options.ImageRewriteRules, ctx.Options.ImagePullSecrets)
 	if err != nil {
 		return nil, err
 	}
@@ -72,11 +72,23 @@ func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventR
 	virtualLogsPath := path.Join(virtualPath, "log")
 	virtualKubeletPath := path.Join(virtualPath, "kubelet")
 
+	priorityClassMapper, err := priorityclasses.NewMapperFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mutationPolicy *policy.Policy
+	if ctx.Options.PodMutationPolicy != "" {
+		mutationPolicy, err = policy.Load(ctx.Options.PodMutationPolicy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &translator{
 		vClientConfig: ctx.VirtualManager.GetConfig(),
 		vClient:       ctx.VirtualManager.GetClient(),
 
-		pClient:         ctx.PhysicalManager.GetClient(),
 		imageTranslator: imageTranslator,
 		eventRecorder:   eventRecorder,
 		log:             loghelper.New("pods-syncer-translator"),
@@ -90,8 +102,10 @@ func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventR
 		overrideHostsImage:           ctx.Options.OverrideHostsContainerImage,
 		serviceAccountsEnabled:       ctx.Controllers.Has("serviceaccounts"),
 		priorityClassesEnabled:       ctx.Controllers.Has("priorityclasses"),
+		priorityClassMapper:          priorityClassMapper,
 		enableScheduler:              ctx.Options.EnableScheduler,
 		syncedLabels:                 ctx.Options.SyncLabels,
+		mutationPolicy:               mutationPolicy,
 
 		mountPhysicalHostPaths:   ctx.Options.MountPhysicalHostPaths,
 		hostpathMountPropagation: true,
@@ -106,7 +120,6 @@ func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventR
 type translator struct {
 	vClientConfig   *rest.Config
 	vClient         client.Client
-	pClient         client.Client
 	imageTranslator ImageTranslator
 	eventRecorder   record.EventRecorder
 	log             loghelper.Logger
@@ -120,8 +133,10 @@ type translator struct {
 	overrideHosts                bool
 	overrideHostsImage           string
 	priorityClassesEnabled       bool
+	priorityClassMapper          *priorityclasses.Mapper
 	enableScheduler              bool
 	syncedLabels                 []string
+	mutationPolicy               *policy.Policy
 
 	mountPhysicalHostPaths   bool
 	hostpathMountPropagation bool
@@ -132,7 +147,7 @@ type translator struct {
 	projectedVolumeSAToken map[string]string
 }
 
-func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services []*corev1.Service, dnsIP string, kubeIP string) (*corev1.Pod, error) {
+func (t *translator) Translate(ctx context.Context, pClient client.Client, vPod *corev1.Pod, services []*corev1.Service, dnsIP string, kubeIP string) (*corev1.Pod, error) {
 	// get Namespace resource in order to have access to its labels
 	vNamespace := &corev1.Namespace{}
 	err := t.vClient.Get(ctx, client.ObjectKey{Name: vPod.ObjectMeta.GetNamespace()}, vNamespace)
@@ -164,11 +179,8 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 	if !t.priorityClassesEnabled {
 		pPod.Spec.PriorityClassName = ""
 		pPod.Spec.Priority = nil
-	} else if pPod.Spec.PriorityClassName != "" {
-		pPod.Spec.PriorityClassName = priorityclasses.NewPriorityClassTranslator()(pPod.Spec.PriorityClassName, nil)
-		if pPod.Spec.Priority != nil && *pPod.Spec.Priority > maxPriority {
-			pPod.Spec.Priority = &maxPriority
-		}
+	} else {
+		t.priorityClassMapper.TranslatePodSpec(&pPod.Spec)
 	}
 
 	// Add an annotation for namespace, name and uid
@@ -286,8 +298,14 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 		pPod.Spec.ImagePullSecrets[i].Name = translate.Default.PhysicalName(pPod.Spec.ImagePullSecrets[i].Name, vPod.Namespace)
 	}
 
+	// add host image pull secrets, these are not visible within the virtual cluster
+	t.injectImagePullSecrets(pPod)
+
+	// translate resource claims
+	translateResourceClaims(pPod, vPod)
+
 	// translate volumes
-	err = t.translateVolumes(ctx, pPod, vPod)
+	err = t.translateVolumes(ctx, pClient, pPod, vPod)
 	if err != nil {
 		return nil, err
 	}
@@ -327,6 +345,14 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 		}
 	}
 
+	// apply the pod mutation policy last, so it can override any of the above
+	if t.mutationPolicy != nil {
+		err = t.mutationPolicy.Apply(vNamespace, vPod, pPod)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return pPod, nil
 }
 
@@ -350,7 +376,7 @@ func TranslateLabelsAnnotation(obj client.Object) string {
 	return strings.Join(labelsString, "\n")
 }
 
-func (t *translator) translateVolumes(ctx context.Context, pPod *corev1.Pod, vPod *corev1.Pod) error {
+func (t *translator) translateVolumes(ctx context.Context, pClient client.Client, pPod *corev1.Pod, vPod *corev1.Pod) error {
 	var shouldCreateTokenSecret bool
 
 	for i := range pPod.Spec.Volumes {
@@ -421,7 +447,7 @@ func (t *translator) translateVolumes(ctx context.Context, pPod *corev1.Pod, vPo
 
 	if shouldCreateTokenSecret {
 		// create the service account token holder secret
-		err := SATokenSecret(ctx, t.pClient, vPod, t.projectedVolumeSAToken)
+		err := SATokenSecret(ctx, pClient, vPod, t.projectedVolumeSAToken)
 		if err != nil {
 			return nil
 		}
@@ -435,6 +461,49 @@ func (t *translator) translateVolumes(ctx context.Context, pPod *corev1.Pod, vPo
 	return nil
 }
 
+func (t *translator) injectImagePullSecrets(pPod *corev1.Pod) {
+	images := []string{}
+	for _, container := range pPod.Spec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pPod.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pPod.Spec.EphemeralContainers {
+		images = append(images, container.Image)
+	}
+
+	for _, image := range images {
+		for _, secret := range t.imageTranslator.PullSecrets(image) {
+			if !hasImagePullSecret(pPod.Spec.ImagePullSecrets, secret) {
+				pPod.Spec.ImagePullSecrets = append(pPod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+			}
+		}
+	}
+}
+
+func hasImagePullSecret(imagePullSecrets []corev1.LocalObjectReference, name string) bool {
+	for _, imagePullSecret := range imagePullSecrets {
+		if imagePullSecret.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func translateResourceClaims(pPod *corev1.Pod, vPod *corev1.Pod) {
+	for i := range pPod.Spec.ResourceClaims {
+		source := &pPod.Spec.ResourceClaims[i].Source
+		if source.ResourceClaimName != nil {
+			source.ResourceClaimName = pointer.String(translate.Default.PhysicalName(*source.ResourceClaimName, vPod.Namespace))
+		}
+		if source.ResourceClaimTemplateName != nil {
+			source.ResourceClaimTemplateName = pointer.String(translate.Default.PhysicalName(*source.ResourceClaimTemplateName, vPod.Namespace))
+		}
+	}
+}
+
 func (t *translator) translateProjectedVolume(ctx context.Context, projectedVolume *corev1.ProjectedVolumeSource, volumeName string, pPod *corev1.Pod, vPod *corev1.Pod, shouldCreateTokenSecret *bool) error {
 	for i := range projectedVolume.Sources {
 		if projectedVolume.Sources[i].Secret != nil {
@@ -869,6 +938,7 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 	}
 
 	updatedAnnotations[LabelsAnnotation] = TranslateLabelsAnnotation(vPod)
+	t.mutationPolicy.ApplyMetadata(vNamespace, updatedLabels, updatedAnnotations)
 	if !equality.Semantic.DeepEqual(updatedAnnotations, pPod.Annotations) {
 		if updatedPod == nil {
 			updatedPod = pPod.DeepCopy()
@@ -978,6 +1048,7 @@ func calcContainerImageDiff(pContainers, vContainers []corev1.Container, transla
 			continue
 		}
 
+		found := false
 		for _, v := range vContainers {
 			if p.Name == v.Name {
 				if p.Image != translateImages.Translate(v.Image) {
@@ -989,9 +1060,15 @@ func calcContainerImageDiff(pContainers, vContainers []corev1.Container, transla
 					newContainers = append(newContainers, p)
 				}
 
+				found = true
 				break
 			}
 		}
+
+		// keep containers that only exist in the host pod, e.g. sidecars
+		if !found {
+			newContainers = append(newContainers, p)
+		}
 	}
 
 	if !changed {
@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -200,11 +201,10 @@ func TestVolumeTranslation(t *testing.T) {
 		tr := &translator{
 			eventRecorder: fakeRecorder,
 			log:           loghelper.New("pods-syncer-translator-test"),
-			pClient:       fake.NewClientBuilder().Build(),
 		}
 
 		pPod := testCase.vPod.DeepCopy()
-		err := tr.translateVolumes(context.Background(), pPod, &testCase.vPod)
+		err := tr.translateVolumes(context.Background(), fake.NewClientBuilder().Build(), pPod, &testCase.vPod)
 		assert.NilError(t, err)
 		assert.Assert(t, cmp.DeepEqual(pPod.Spec.Volumes, testCase.expectedVolumes), "Unexpected translation of the Volumes in the '%s' test case", testCase.name)
 	}
@@ -216,6 +216,50 @@ type translatePodVolumesTestCase struct {
 	expectedVolumes []corev1.Volume
 }
 
+func TestResourceClaimsTranslation(t *testing.T) {
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-name",
+			Namespace: "test-ns",
+		},
+		Spec: corev1.PodSpec{
+			ResourceClaims: []corev1.PodResourceClaim{
+				{
+					Name: "gpu",
+					Source: corev1.ClaimSource{
+						ResourceClaimName: pointer.String("my-claim"),
+					},
+				},
+				{
+					Name: "fpga",
+					Source: corev1.ClaimSource{
+						ResourceClaimTemplateName: pointer.String("my-template"),
+					},
+				},
+			},
+		},
+	}
+	expectedResourceClaims := []corev1.PodResourceClaim{
+		{
+			Name: "gpu",
+			Source: corev1.ClaimSource{
+				ResourceClaimName: pointer.String(translate.Default.PhysicalName("my-claim", "test-ns")),
+			},
+		},
+		{
+			Name: "fpga",
+			Source: corev1.ClaimSource{
+				ResourceClaimTemplateName: pointer.String(translate.Default.PhysicalName("my-template", "test-ns")),
+			},
+		},
+	}
+
+	pPod := vPod.DeepCopy()
+	translateResourceClaims(pPod, vPod)
+	assert.Assert(t, cmp.DeepEqual(pPod.Spec.ResourceClaims, expectedResourceClaims))
+	assert.Equal(t, *vPod.Spec.ResourceClaims[0].Source.ResourceClaimName, "my-claim", "virtual pod must not be modified")
+}
+
 func appendToMatchLabels(source *metav1.LabelSelector, k, v string) *metav1.LabelSelector {
 	ls := source.DeepCopy()
 	if ls.MatchLabels == nil {
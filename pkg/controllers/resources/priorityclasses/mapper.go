@@ -0,0 +1,163 @@
+package priorityclasses
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// MaxValue is the highest value a user defined priority class can have
+	MaxValue = int32(1000000000)
+
+	// DefaultMapping is the virtual priority class name that matches all unmapped classes
+	DefaultMapping = "*"
+)
+
+// Mapper translates virtual priority classes and priority values into the host cluster
+type Mapper struct {
+	maxValue        int32
+	valueRange      *valueRange
+	preemptionNever bool
+
+	// hostClasses maps virtual priority classes to existing host priority classes
+	hostClasses map[string]string
+}
+
+type valueRange struct {
+	min int32
+	max int32
+}
+
+func NewMapperFromContext(ctx *synccontext.RegisterContext) (*Mapper, error) {
+	return NewMapper(ctx.Options.PriorityClassMaxValue, ctx.Options.PriorityClassValueRange, ctx.Options.PriorityClassPreemptionNever, ctx.Options.PriorityClassMappings)
+}
+
+// NewMapper creates a new mapper. maxValue caps all host values, hostRange is an optional range
+// in the form min:max the virtual values are compressed into and mappings are virtual to host
+// priority class mappings in the form virtual=host
+func NewMapper(maxValue int32, hostRange string, preemptionNever bool, mappings []string) (*Mapper, error) {
+	if maxValue == 0 || maxValue > MaxValue {
+		maxValue = MaxValue
+	}
+
+	mapper := &Mapper{
+		maxValue:        maxValue,
+		preemptionNever: preemptionNever,
+		hostClasses:     map[string]string{},
+	}
+	if hostRange != "" {
+		r, err := parseValueRange(hostRange)
+		if err != nil {
+			return nil, err
+		}
+
+		mapper.valueRange = r
+	}
+
+	for _, m := range mappings {
+		i := strings.Split(strings.TrimSpace(m), "=")
+		if len(i) != 2 || i[0] == "" || i[1] == "" {
+			return nil, fmt.Errorf("error parsing priority class mapping '%s': bad format expected virtual=host", m)
+		} else if _, ok := mapper.hostClasses[i[0]]; ok {
+			return nil, fmt.Errorf("error parsing priority class mapping '%s': virtual priority class %s is mapped twice", m, i[0])
+		}
+
+		mapper.hostClasses[i[0]] = i[1]
+	}
+
+	return mapper, nil
+}
+
+func parseValueRange(hostRange string) (*valueRange, error) {
+	i := strings.Split(hostRange, ":")
+	if len(i) != 2 {
+		return nil, fmt.Errorf("error parsing priority class value range '%s': bad format expected min:max", hostRange)
+	}
+
+	lower, err := strconv.ParseInt(strings.TrimSpace(i[0]), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing priority class value range '%s': %w", hostRange, err)
+	}
+	upper, err := strconv.ParseInt(strings.TrimSpace(i[1]), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing priority class value range '%s': %w", hostRange, err)
+	}
+	if lower > upper || upper > int64(MaxValue) {
+		return nil, fmt.Errorf("error parsing priority class value range '%s': expected min <= max <= %d", hostRange, MaxValue)
+	}
+
+	return &valueRange{min: int32(lower), max: int32(upper)}, nil
+}
+
+// MapsToHostClasses returns true if virtual priority classes are mapped to existing host priority
+// classes, in which case no priority classes are created in the host cluster
+func (m *Mapper) MapsToHostClasses() bool {
+	return len(m.hostClasses) > 0
+}
+
+// TranslateValue returns the host priority value for the given virtual priority value
+func (m *Mapper) TranslateValue(value int32) int32 {
+	if m.valueRange != nil {
+		// compress the user defined virtual range 0..MaxValue into the host range
+		virtualValue := int64(value)
+		if virtualValue < 0 {
+			virtualValue = 0
+		} else if virtualValue > int64(MaxValue) {
+			virtualValue = int64(MaxValue)
+		}
+
+		value = int32(int64(m.valueRange.min) + virtualValue*int64(m.valueRange.max-m.valueRange.min)/int64(MaxValue))
+	}
+	if value > m.maxValue {
+		value = m.maxValue
+	}
+
+	return value
+}
+
+// TranslatePreemptionPolicy returns the host preemption policy for the given virtual preemption policy
+func (m *Mapper) TranslatePreemptionPolicy(preemptionPolicy *corev1.PreemptionPolicy) *corev1.PreemptionPolicy {
+	if m.preemptionNever {
+		never := corev1.PreemptNever
+		return &never
+	}
+
+	return preemptionPolicy
+}
+
+// TranslatePodSpec translates the priority fields of a host pod that were copied from the virtual pod
+func (m *Mapper) TranslatePodSpec(spec *corev1.PodSpec) {
+	if m.MapsToHostClasses() {
+		// the host cluster resolves priority and preemption policy from the existing host class
+		spec.PriorityClassName = m.hostClassName(spec.PriorityClassName)
+		spec.Priority = nil
+		spec.PreemptionPolicy = nil
+		return
+	}
+
+	// pods without a priority class are resolved by the host cluster as before
+	if spec.PriorityClassName == "" {
+		return
+	}
+
+	spec.PriorityClassName = translatePriorityClassName(spec.PriorityClassName)
+	spec.PreemptionPolicy = m.TranslatePreemptionPolicy(spec.PreemptionPolicy)
+	if spec.Priority != nil {
+		priority := m.TranslateValue(*spec.Priority)
+		spec.Priority = &priority
+	}
+}
+
+func (m *Mapper) hostClassName(name string) string {
+	if name != "" {
+		if hostClass, ok := m.hostClasses[name]; ok {
+			return hostClass
+		}
+	}
+
+	return m.hostClasses[DefaultMapping]
+}
@@ -0,0 +1,132 @@
+package priorityclasses
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestTranslateValue(t *testing.T) {
+	testCases := []struct {
+		name       string
+		maxValue   int32
+		valueRange string
+		values     map[int32]int32
+	}{
+		{
+			name: "default",
+			values: map[int32]int32{
+				-10:        -10,
+				1000:       1000,
+				2000000000: MaxValue,
+			},
+		},
+		{
+			name:     "ceiling",
+			maxValue: 1000,
+			values: map[int32]int32{
+				-10:     -10,
+				1000:    1000,
+				1000000: 1000,
+			},
+		},
+		{
+			name:       "range",
+			valueRange: "100:1100",
+			values: map[int32]int32{
+				-10:        100,
+				0:          100,
+				500000000:  600,
+				MaxValue:   1100,
+				2000000000: 1100,
+			},
+		},
+		{
+			name:       "range with ceiling",
+			maxValue:   500,
+			valueRange: "0:1000",
+			values: map[int32]int32{
+				100000000: 100,
+				MaxValue:  500,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		mapper, err := NewMapper(testCase.maxValue, testCase.valueRange, false, nil)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		for value, expected := range testCase.values {
+			assert.Equal(t, mapper.TranslateValue(value), expected, "unexpected value for %d in test case %s", value, testCase.name)
+		}
+	}
+}
+
+func TestTranslatePodSpec(t *testing.T) {
+	preemptLowerPriority := corev1.PreemptLowerPriority
+	preemptNever := corev1.PreemptNever
+
+	testCases := []struct {
+		name            string
+		valueRange      string
+		preemptionNever bool
+		mappings        []string
+		spec            *corev1.PodSpec
+		expected        *corev1.PodSpec
+	}{
+		{
+			name:            "synced class",
+			valueRange:      "0:1000",
+			preemptionNever: true,
+			spec:            &corev1.PodSpec{PriorityClassName: "high", Priority: pointer.Int32(MaxValue), PreemptionPolicy: &preemptLowerPriority},
+			expected:        &corev1.PodSpec{PriorityClassName: translatePriorityClassName("high"), Priority: pointer.Int32(1000), PreemptionPolicy: &preemptNever},
+		},
+		{
+			name:            "no class",
+			preemptionNever: true,
+			spec:            &corev1.PodSpec{Priority: pointer.Int32(0), PreemptionPolicy: &preemptLowerPriority},
+			expected:        &corev1.PodSpec{Priority: pointer.Int32(0), PreemptionPolicy: &preemptLowerPriority},
+		},
+		{
+			name:     "mapped class",
+			mappings: []string{"high=tenant-high", "*=tenant-default"},
+			spec:     &corev1.PodSpec{PriorityClassName: "high", Priority: pointer.Int32(1000), PreemptionPolicy: &preemptLowerPriority},
+			expected: &corev1.PodSpec{PriorityClassName: "tenant-high"},
+		},
+		{
+			name:     "default mapping",
+			mappings: []string{"high=tenant-high", "*=tenant-default"},
+			spec:     &corev1.PodSpec{PriorityClassName: "low", Priority: pointer.Int32(10)},
+			expected: &corev1.PodSpec{PriorityClassName: "tenant-default"},
+		},
+		{
+			name:     "unmapped class",
+			mappings: []string{"high=tenant-high"},
+			spec:     &corev1.PodSpec{PriorityClassName: "low", Priority: pointer.Int32(10)},
+			expected: &corev1.PodSpec{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		mapper, err := NewMapper(0, testCase.valueRange, testCase.preemptionNever, testCase.mappings)
+		assert.NilError(t, err, "unexpected error in test case %s", testCase.name)
+		mapper.TranslatePodSpec(testCase.spec)
+		assert.DeepEqual(t, testCase.spec, testCase.expected)
+	}
+}
+
+func TestNewMapperInvalid(t *testing.T) {
+	for _, valueRange := range []string{"1000", "a:b", "1000:0", "0:2000000000"} {
+		_, err := NewMapper(0, valueRange, false, nil)
+		assert.Assert(t, err != nil, "expected error for value range %s", valueRange)
+	}
+
+	for _, mapping := range []string{"high", "=tenant-high", "high=", "high=a=b"} {
+		_, err := NewMapper(0, "", false, []string{mapping})
+		assert.Assert(t, err != nil, "expected error for mapping %s", mapping)
+	}
+
+	_, err := NewMapper(0, "", false, []string{"high=a", "high=b"})
+	assert.Assert(t, err != nil)
+}
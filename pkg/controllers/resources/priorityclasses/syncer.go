@@ -12,13 +12,22 @@ import (
 )
 
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	mapper, err := NewMapperFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return &priorityClassSyncer{
 		Translator: translator.NewClusterTranslator(ctx, "priorityclass", &schedulingv1.PriorityClass{}, NewPriorityClassTranslator()),
+
+		mapper: mapper,
 	}, nil
 }
 
 type priorityClassSyncer struct {
 	translator.Translator
+
+	mapper *Mapper
 }
 
 var _ syncer.IndicesRegisterer = &priorityClassSyncer{}
@@ -32,6 +41,11 @@ func (s *priorityClassSyncer) RegisterIndices(ctx *synccontext.RegisterContext)
 var _ syncer.Syncer = &priorityClassSyncer{}
 
 func (s *priorityClassSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	// virtual priority classes are mapped to existing host priority classes
+	if s.mapper.MapsToHostClasses() {
+		return ctrl.Result{}, nil
+	}
+
 	newPriorityClass := s.translate(ctx.Context, vObj.(*schedulingv1.PriorityClass))
 	ctx.Log.Infof("create physical priority class %s", newPriorityClass.Name)
 	err := ctx.PhysicalClient.Create(ctx.Context, newPriorityClass)
@@ -13,9 +13,8 @@ func (s *priorityClassSyncer) translate(ctx context.Context, vObj client.Object)
 	// translate the priority class
 	priorityClass := s.TranslateMetadata(ctx, vObj).(*schedulingv1.PriorityClass)
 	priorityClass.GlobalDefault = false
-	if priorityClass.Value > 1000000000 {
-		priorityClass.Value = 1000000000
-	}
+	priorityClass.Value = s.mapper.TranslateValue(priorityClass.Value)
+	priorityClass.PreemptionPolicy = s.mapper.TranslatePreemptionPolicy(priorityClass.PreemptionPolicy)
 	return priorityClass
 }
 
@@ -23,9 +22,10 @@ func (s *priorityClassSyncer) translateUpdate(ctx context.Context, pObj, vObj *s
 	var updated *schedulingv1.PriorityClass
 
 	// check subsets
-	if !equality.Semantic.DeepEqual(vObj.PreemptionPolicy, pObj.PreemptionPolicy) {
+	translatedPreemptionPolicy := s.mapper.TranslatePreemptionPolicy(vObj.PreemptionPolicy)
+	if !equality.Semantic.DeepEqual(translatedPreemptionPolicy, pObj.PreemptionPolicy) {
 		updated = translator.NewIfNil(updated, pObj)
-		updated.PreemptionPolicy = vObj.PreemptionPolicy
+		updated.PreemptionPolicy = translatedPreemptionPolicy
 	}
 
 	// check annotations
@@ -43,10 +43,7 @@ func (s *priorityClassSyncer) translateUpdate(ctx context.Context, pObj, vObj *s
 	}
 
 	// check value
-	translatedValue := vObj.Value
-	if translatedValue > 1000000000 {
-		translatedValue = 1000000000
-	}
+	translatedValue := s.mapper.TranslateValue(vObj.Value)
 	if translatedValue != pObj.Value {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Value = translatedValue
@@ -0,0 +1,52 @@
+package resourceclaims
+
+import (
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	resourcev1alpha2 "k8s.io/api/resource/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &resourceClaimSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "resourceclaim", &resourcev1alpha2.ResourceClaim{}),
+	}, nil
+}
+
+type resourceClaimSyncer struct {
+	translator.NamespacedTranslator
+}
+
+var _ syncer.Syncer = &resourceClaimSyncer{}
+
+func (s *resourceClaimSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*resourcev1alpha2.ResourceClaim)))
+}
+
+func (s *resourceClaimSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	vResourceClaim := vObj.(*resourcev1alpha2.ResourceClaim)
+	pResourceClaim := pObj.(*resourcev1alpha2.ResourceClaim)
+
+	// allocation happens in the host cluster, so sync the status back
+	vStatus, err := s.translateStatusBackwards(ctx, pResourceClaim)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !equality.Semantic.DeepEqual(vResourceClaim.Status, *vStatus) {
+		updated := vResourceClaim.DeepCopy()
+		updated.Status = *vStatus
+		ctx.Log.Infof("update virtual resource claim %s/%s, because the status has changed", vResourceClaim.Namespace, vResourceClaim.Name)
+		translator.PrintChanges(vObj, updated, ctx.Log)
+		return ctrl.Result{}, ctx.VirtualClient.Status().Update(ctx.Context, updated)
+	}
+
+	// the spec of a resource claim is immutable, so only the metadata can change
+	updated := s.translateUpdate(ctx.Context, pResourceClaim, vResourceClaim)
+	if updated != nil {
+		translator.PrintChanges(pObj, updated, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, updated)
+}
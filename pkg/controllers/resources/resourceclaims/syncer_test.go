@@ -0,0 +1,166 @@
+package resourceclaims
+
+import (
+	"testing"
+
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	resourcev1alpha2 "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+	vObjectMeta := metav1.ObjectMeta{
+		Name:            "test-claim",
+		Namespace:       "default",
+		ResourceVersion: generictesting.FakeClientResourceVersion,
+	}
+	pObjectMeta := metav1.ObjectMeta{
+		Name:      translate.Default.PhysicalName("test-claim", vObjectMeta.Namespace),
+		Namespace: generictesting.DefaultTestTargetNamespace,
+		Annotations: map[string]string{
+			translate.NameAnnotation:      vObjectMeta.Name,
+			translate.NamespaceAnnotation: vObjectMeta.Namespace,
+			translate.UIDAnnotation:       "",
+		},
+		Labels: map[string]string{
+			translate.NamespaceLabel: vObjectMeta.Namespace,
+			translate.MarkerLabel:    translate.Suffix,
+		},
+		ResourceVersion: generictesting.FakeClientResourceVersion,
+	}
+
+	vResourceClaim := &resourcev1alpha2.ResourceClaim{
+		ObjectMeta: vObjectMeta,
+		Spec: resourcev1alpha2.ResourceClaimSpec{
+			ResourceClassName: "gpu.example.com",
+			ParametersRef: &resourcev1alpha2.ResourceClaimParametersReference{
+				Kind: "ConfigMap",
+				Name: "gpu-parameters",
+			},
+		},
+	}
+	pResourceClaim := &resourcev1alpha2.ResourceClaim{
+		ObjectMeta: pObjectMeta,
+		Spec: resourcev1alpha2.ResourceClaimSpec{
+			ResourceClassName: "gpu.example.com",
+			ParametersRef: &resourcev1alpha2.ResourceClaimParametersReference{
+				Kind: "ConfigMap",
+				Name: translate.Default.PhysicalName("gpu-parameters", vObjectMeta.Namespace),
+			},
+		},
+	}
+
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: vObjectMeta.Namespace,
+			UID:       "virtual-uid",
+		},
+	}
+	pPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName("test-pod", vObjectMeta.Namespace),
+			Namespace: generictesting.DefaultTestTargetNamespace,
+			UID:       "physical-uid",
+			Annotations: map[string]string{
+				podtranslate.NameAnnotation:      vPod.Name,
+				podtranslate.NamespaceAnnotation: vPod.Namespace,
+			},
+		},
+	}
+	pAllocatedResourceClaim := pResourceClaim.DeepCopy()
+	pAllocatedResourceClaim.Status = resourcev1alpha2.ResourceClaimStatus{
+		DriverName: "gpu.example.com",
+		Allocation: &resourcev1alpha2.AllocationResult{
+			Shareable: true,
+		},
+		ReservedFor: []resourcev1alpha2.ResourceClaimConsumerReference{
+			{
+				Resource: "pods",
+				Name:     pPod.Name,
+				UID:      pPod.UID,
+			},
+		},
+	}
+	vAllocatedResourceClaim := vResourceClaim.DeepCopy()
+	vAllocatedResourceClaim.ResourceVersion = "1000"
+	vAllocatedResourceClaim.Status = resourcev1alpha2.ResourceClaimStatus{
+		DriverName: "gpu.example.com",
+		Allocation: &resourcev1alpha2.AllocationResult{
+			Shareable: true,
+		},
+		ReservedFor: []resourcev1alpha2.ResourceClaimConsumerReference{
+			{
+				Resource: "pods",
+				Name:     vPod.Name,
+				UID:      vPod.UID,
+			},
+		},
+	}
+
+	vLabeledResourceClaim := vResourceClaim.DeepCopy()
+	vLabeledResourceClaim.Labels = map[string]string{
+		"test": "true",
+	}
+	pLabeledResourceClaim := pResourceClaim.DeepCopy()
+	pLabeledResourceClaim.Labels[translate.Default.ConvertLabelKey("test")] = "true"
+	pLabeledResourceClaim.ResourceVersion = "1000"
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                "Create host resource claim",
+			InitialVirtualState: []runtime.Object{vResourceClaim.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaim"): {vResourceClaim.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaim"): {pResourceClaim.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*resourceClaimSyncer).SyncDown(syncCtx, vResourceClaim.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync allocation status back",
+			InitialVirtualState:  []runtime.Object{vResourceClaim.DeepCopy(), vPod.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pAllocatedResourceClaim.DeepCopy(), pPod.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaim"): {vAllocatedResourceClaim.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaim"): {pAllocatedResourceClaim.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*resourceClaimSyncer).Sync(syncCtx, pAllocatedResourceClaim.DeepCopy(), vResourceClaim.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update host resource claim metadata",
+			InitialVirtualState:  []runtime.Object{vLabeledResourceClaim.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pResourceClaim.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaim"): {vLabeledResourceClaim.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaim"): {pLabeledResourceClaim.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*resourceClaimSyncer).Sync(syncCtx, pResourceClaim.DeepCopy(), vLabeledResourceClaim.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package resourceclaims
+
+import (
+	"context"
+
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	resourcev1alpha2 "k8s.io/api/resource/v1alpha2"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func (s *resourceClaimSyncer) translate(ctx context.Context, vResourceClaim *resourcev1alpha2.ResourceClaim) *resourcev1alpha2.ResourceClaim {
+	pResourceClaim := s.TranslateMetadata(ctx, vResourceClaim).(*resourcev1alpha2.ResourceClaim)
+	pResourceClaim.Spec = *TranslateSpec(&vResourceClaim.Spec, vResourceClaim.Namespace)
+	pResourceClaim.Status = resourcev1alpha2.ResourceClaimStatus{}
+	return pResourceClaim
+}
+
+func (s *resourceClaimSyncer) translateUpdate(ctx context.Context, pObj, vObj *resourcev1alpha2.ResourceClaim) *resourcev1alpha2.ResourceClaim {
+	var updated *resourcev1alpha2.ResourceClaim
+
+	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Annotations = updatedAnnotations
+		updated.Labels = updatedLabels
+	}
+
+	return updated
+}
+
+func (s *resourceClaimSyncer) translateStatusBackwards(ctx *synccontext.SyncContext, pResourceClaim *resourcev1alpha2.ResourceClaim) (*resourcev1alpha2.ResourceClaimStatus, error) {
+	vStatus := pResourceClaim.Status.DeepCopy()
+	vStatus.ReservedFor = nil
+
+	// the claim is reserved for host pods, rewrite these references to the virtual pods
+	for _, reservedFor := range pResourceClaim.Status.ReservedFor {
+		if reservedFor.APIGroup != "" || reservedFor.Resource != "pods" {
+			continue
+		}
+
+		pPod := &corev1.Pod{}
+		err := ctx.PhysicalClient.Get(ctx.Context, client.ObjectKey{Namespace: pResourceClaim.Namespace, Name: reservedFor.Name}, pPod)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		} else if pPod.Annotations == nil || pPod.Annotations[podtranslate.NameAnnotation] == "" {
+			continue
+		}
+
+		vPod := &corev1.Pod{}
+		err = ctx.VirtualClient.Get(ctx.Context, client.ObjectKey{Namespace: pPod.Annotations[podtranslate.NamespaceAnnotation], Name: pPod.Annotations[podtranslate.NameAnnotation]}, vPod)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		vStatus.ReservedFor = append(vStatus.ReservedFor, resourcev1alpha2.ResourceClaimConsumerReference{
+			Resource: "pods",
+			Name:     vPod.Name,
+			UID:      vPod.UID,
+		})
+	}
+
+	return vStatus, nil
+}
+
+// TranslateSpec translates the given virtual resource claim spec into a host spec.
+// The referenced resource class is cluster scoped and used as is, while config maps
+// used as claim parameters are synced by vcluster and therefore need to be rewritten.
+func TranslateSpec(vSpec *resourcev1alpha2.ResourceClaimSpec, vNamespace string) *resourcev1alpha2.ResourceClaimSpec {
+	pSpec := vSpec.DeepCopy()
+	if pSpec.ParametersRef != nil && pSpec.ParametersRef.APIGroup == "" && pSpec.ParametersRef.Kind == "ConfigMap" {
+		pSpec.ParametersRef.Name = translate.Default.PhysicalName(pSpec.ParametersRef.Name, vNamespace)
+	}
+	return pSpec
+}
@@ -0,0 +1,35 @@
+package resourceclaimtemplates
+
+import (
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	resourcev1alpha2 "k8s.io/api/resource/v1alpha2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &resourceClaimTemplateSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "resourceclaimtemplate", &resourcev1alpha2.ResourceClaimTemplate{}),
+	}, nil
+}
+
+type resourceClaimTemplateSyncer struct {
+	translator.NamespacedTranslator
+}
+
+var _ syncer.Syncer = &resourceClaimTemplateSyncer{}
+
+func (s *resourceClaimTemplateSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncDownCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*resourcev1alpha2.ResourceClaimTemplate)))
+}
+
+func (s *resourceClaimTemplateSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	updated := s.translateUpdate(ctx.Context, pObj.(*resourcev1alpha2.ResourceClaimTemplate), vObj.(*resourcev1alpha2.ResourceClaimTemplate))
+	if updated != nil {
+		translator.PrintChanges(pObj, updated, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vObj, updated)
+}
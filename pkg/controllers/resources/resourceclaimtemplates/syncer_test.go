@@ -0,0 +1,80 @@
+package resourceclaimtemplates
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	resourcev1alpha2 "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+	vObjectMeta := metav1.ObjectMeta{
+		Name:            "test-template",
+		Namespace:       "default",
+		ResourceVersion: generictesting.FakeClientResourceVersion,
+	}
+	pObjectMeta := metav1.ObjectMeta{
+		Name:      translate.Default.PhysicalName("test-template", vObjectMeta.Namespace),
+		Namespace: generictesting.DefaultTestTargetNamespace,
+		Annotations: map[string]string{
+			translate.NameAnnotation:      vObjectMeta.Name,
+			translate.NamespaceAnnotation: vObjectMeta.Namespace,
+			translate.UIDAnnotation:       "",
+		},
+		Labels: map[string]string{
+			translate.NamespaceLabel: vObjectMeta.Namespace,
+			translate.MarkerLabel:    translate.Suffix,
+		},
+		ResourceVersion: generictesting.FakeClientResourceVersion,
+	}
+
+	vTemplate := &resourcev1alpha2.ResourceClaimTemplate{
+		ObjectMeta: vObjectMeta,
+		Spec: resourcev1alpha2.ResourceClaimTemplateSpec{
+			Spec: resourcev1alpha2.ResourceClaimSpec{
+				ResourceClassName: "gpu.example.com",
+				ParametersRef: &resourcev1alpha2.ResourceClaimParametersReference{
+					Kind: "ConfigMap",
+					Name: "gpu-parameters",
+				},
+			},
+		},
+	}
+	pTemplate := &resourcev1alpha2.ResourceClaimTemplate{
+		ObjectMeta: pObjectMeta,
+		Spec: resourcev1alpha2.ResourceClaimTemplateSpec{
+			Spec: resourcev1alpha2.ResourceClaimSpec{
+				ResourceClassName: "gpu.example.com",
+				ParametersRef: &resourcev1alpha2.ResourceClaimParametersReference{
+					Kind: "ConfigMap",
+					Name: translate.Default.PhysicalName("gpu-parameters", vObjectMeta.Namespace),
+				},
+			},
+		},
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                "Create host resource claim template",
+			InitialVirtualState: []runtime.Object{vTemplate.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaimTemplate"): {vTemplate.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				resourcev1alpha2.SchemeGroupVersion.WithKind("ResourceClaimTemplate"): {pTemplate.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*resourceClaimTemplateSyncer).SyncDown(syncCtx, vTemplate.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package resourceclaimtemplates
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/resourceclaims"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	resourcev1alpha2 "k8s.io/api/resource/v1alpha2"
+)
+
+func (s *resourceClaimTemplateSyncer) translate(ctx context.Context, vObj *resourcev1alpha2.ResourceClaimTemplate) *resourcev1alpha2.ResourceClaimTemplate {
+	pObj := s.TranslateMetadata(ctx, vObj).(*resourcev1alpha2.ResourceClaimTemplate)
+	pObj.Spec.Spec = *resourceclaims.TranslateSpec(&vObj.Spec.Spec, vObj.Namespace)
+	return pObj
+}
+
+func (s *resourceClaimTemplateSyncer) translateUpdate(ctx context.Context, pObj, vObj *resourcev1alpha2.ResourceClaimTemplate) *resourcev1alpha2.ResourceClaimTemplate {
+	var updated *resourcev1alpha2.ResourceClaimTemplate
+
+	// the spec of a resource claim template is immutable, so only the metadata can change
+	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	if changed {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Annotations = updatedAnnotations
+		updated.Labels = updatedLabels
+	}
+
+	return updated
+}
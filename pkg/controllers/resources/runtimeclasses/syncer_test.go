@@ -0,0 +1,113 @@
+package runtimeclasses
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+)
+
+func TestSync(t *testing.T) {
+	vObjectMeta := metav1.ObjectMeta{
+		Name: "gvisor",
+		Annotations: map[string]string{
+			translate.NameAnnotation: "gvisor",
+			translate.UIDAnnotation:  "",
+		},
+	}
+	vObj := &nodev1.RuntimeClass{
+		ObjectMeta: vObjectMeta,
+		Handler:    "runsc",
+	}
+	pObj := &nodev1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: vObjectMeta.Name,
+			Labels: map[string]string{
+				translate.MarkerLabel: translate.Suffix,
+			},
+			Annotations: map[string]string{
+				translate.NameAnnotation: "gvisor",
+				translate.UIDAnnotation:  "",
+			},
+		},
+		Handler: "runsc",
+	}
+
+	overhead := &nodev1.Overhead{
+		PodFixed: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("250m"),
+		},
+	}
+	scheduling := &nodev1.Scheduling{
+		NodeSelector: map[string]string{
+			"runtime": "gvisor",
+		},
+	}
+	vObjUpdated := &nodev1.RuntimeClass{
+		ObjectMeta: vObjectMeta,
+		Handler:    "runsc",
+		Overhead:   overhead,
+		Scheduling: scheduling,
+	}
+	pObjUpdated := &nodev1.RuntimeClass{
+		ObjectMeta: pObj.ObjectMeta,
+		Handler:    "runsc",
+		Overhead:   overhead,
+		Scheduling: scheduling,
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Sync Up",
+			InitialVirtualState:  []runtime.Object{},
+			InitialPhysicalState: []runtime.Object{pObj},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {vObj},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {pObj},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*runtimeClassSyncer).SyncUp(syncCtx, pObj)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                  "Sync Down",
+			InitialVirtualState:   []runtime.Object{vObj},
+			ExpectedVirtualState:  map[schema.GroupVersionKind][]runtime.Object{},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*runtimeClassSyncer).SyncDown(syncCtx, vObj)
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync",
+			InitialVirtualState:  []runtime.Object{vObj},
+			InitialPhysicalState: []runtime.Object{pObjUpdated},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {vObjUpdated},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				nodev1.SchemeGroupVersion.WithKind("RuntimeClass"): {pObjUpdated},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*runtimeClassSyncer).Sync(syncCtx, pObjUpdated, vObj)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
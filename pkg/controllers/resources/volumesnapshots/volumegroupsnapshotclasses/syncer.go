@@ -0,0 +1,73 @@
+package volumegroupsnapshotclasses
+
+import (
+	"path"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util"
+
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// file path relative to the manifests folder in the container
+	crdPath = "volumegroupsnapshots/groupsnapshot.storage.k8s.io_volumegroupsnapshotclasses.yaml"
+)
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &volumeGroupSnapshotClassSyncer{
+		Translator: translator.NewMirrorPhysicalTranslator("volumegroupsnapshotclass", &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass{}),
+	}, nil
+}
+
+type volumeGroupSnapshotClassSyncer struct {
+	translator.Translator
+}
+
+var _ syncer.Initializer = &volumeGroupSnapshotClassSyncer{}
+
+func (s *volumeGroupSnapshotClassSyncer) Init(registerContext *synccontext.RegisterContext) error {
+	return util.EnsureCRDFromFile(registerContext.Context, registerContext.VirtualManager.GetConfig(), path.Join(constants.ContainerManifestsFolder, crdPath), volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotClass"))
+}
+
+var _ syncer.UpSyncer = &volumeGroupSnapshotClassSyncer{}
+
+func (s *volumeGroupSnapshotClassSyncer) SyncUp(ctx *synccontext.SyncContext, pObj client.Object) (ctrl.Result, error) {
+	pVolumeGroupSnapshotClass := pObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass)
+	vObj := s.translateBackwards(ctx.Context, pVolumeGroupSnapshotClass)
+	ctx.Log.Infof("create VolumeGroupSnapshotClass %s, because it does not exist in the virtual cluster", vObj.Name)
+	return ctrl.Result{}, ctx.VirtualClient.Create(ctx.Context, vObj)
+}
+
+var _ syncer.Syncer = &volumeGroupSnapshotClassSyncer{}
+
+func (s *volumeGroupSnapshotClassSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	// We are not doing any syncing Forward for the VolumeGroupSnapshotClasses
+	// if this method is called it means that VolumeGroupSnapshotClass was deleted in host or
+	// a new VolumeGroupSnapshotClass was created in vcluster, and it should be deleted to avoid confusion
+	ctx.Log.Infof("delete VolumeGroupSnapshotClass %s, because it does not exist in the host cluster", vObj.GetName())
+	err := ctx.VirtualClient.Delete(ctx.Context, vObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (s *volumeGroupSnapshotClassSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	updated := s.translateUpdateBackwards(ctx.Context, pObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass), vObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass))
+	if updated != nil {
+		ctx.Log.Infof("updating virtual VolumeGroupSnapshotClass %s, because it differs from the physical one", updated.Name)
+		translator.PrintChanges(vObj, updated, ctx.Log)
+		err := ctx.VirtualClient.Update(ctx.Context, updated)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
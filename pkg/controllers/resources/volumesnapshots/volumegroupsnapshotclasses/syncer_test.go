@@ -0,0 +1,96 @@
+package volumegroupsnapshotclasses
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+
+	vObjectMeta := metav1.ObjectMeta{
+		Name:            "testclass",
+		ResourceVersion: "999",
+	}
+	vBaseVGSC := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass{
+		ObjectMeta:     vObjectMeta,
+		Driver:         "hostpath.csi.k8s.io",
+		Parameters:     map[string]string{"random": "one"},
+		DeletionPolicy: volumesnapshotv1.VolumeSnapshotContentRetain,
+	}
+	vMoreParamsVGSC := vBaseVGSC.DeepCopy()
+	vMoreParamsVGSC.Parameters["additional"] = "param"
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Create backward",
+			InitialVirtualState:  []runtime.Object{},
+			InitialPhysicalState: []runtime.Object{vBaseVGSC.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotClass"): {vBaseVGSC.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotClass"): {vBaseVGSC.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotClassSyncer).SyncUp(syncCtx, vBaseVGSC.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update backward",
+			InitialVirtualState:  []runtime.Object{vBaseVGSC.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{vMoreParamsVGSC.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotClass"): {vMoreParamsVGSC.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotClass"): {vMoreParamsVGSC.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotClassSyncer).Sync(syncCtx, vMoreParamsVGSC.DeepCopy(), vBaseVGSC.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Ignore forward update",
+			InitialVirtualState:  []runtime.Object{vMoreParamsVGSC.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{vBaseVGSC.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotClass"): {vBaseVGSC.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotClass"): {vBaseVGSC.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotClassSyncer).Sync(syncCtx, vBaseVGSC.DeepCopy(), vMoreParamsVGSC.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                  "Delete backward",
+			InitialVirtualState:   []runtime.Object{vBaseVGSC.DeepCopy()},
+			InitialPhysicalState:  []runtime.Object{},
+			ExpectedVirtualState:  map[schema.GroupVersionKind][]runtime.Object{},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotClassSyncer).SyncDown(syncCtx, vBaseVGSC.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
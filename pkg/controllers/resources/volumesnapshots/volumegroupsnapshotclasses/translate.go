@@ -0,0 +1,41 @@
+package volumegroupsnapshotclasses
+
+import (
+	"context"
+
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func (s *volumeGroupSnapshotClassSyncer) translateBackwards(ctx context.Context, pVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass) *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass {
+	return s.TranslateMetadata(ctx, pVGSC).(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass)
+}
+
+func (s *volumeGroupSnapshotClassSyncer) translateUpdateBackwards(ctx context.Context, pVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass, vVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass) *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass {
+	var updated *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotClass
+
+	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vVGSC, pVGSC)
+	if changed {
+		updated = translator.NewIfNil(updated, vVGSC)
+		updated.Labels = updatedLabels
+		updated.Annotations = updatedAnnotations
+	}
+
+	if !equality.Semantic.DeepEqual(vVGSC.Driver, pVGSC.Driver) {
+		updated = translator.NewIfNil(updated, vVGSC)
+		updated.Driver = pVGSC.Driver
+	}
+
+	if !equality.Semantic.DeepEqual(vVGSC.Parameters, pVGSC.Parameters) {
+		updated = translator.NewIfNil(updated, vVGSC)
+		updated.Parameters = pVGSC.Parameters
+	}
+
+	if !equality.Semantic.DeepEqual(vVGSC.DeletionPolicy, pVGSC.DeletionPolicy) {
+		updated = translator.NewIfNil(updated, vVGSC)
+		updated.DeletionPolicy = pVGSC.DeletionPolicy
+	}
+
+	return updated
+}
@@ -0,0 +1,284 @@
+package volumegroupsnapshotcontents
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util"
+
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	crdPath = "volumegroupsnapshots/groupsnapshot.storage.k8s.io_volumegroupsnapshotcontents.yaml"
+
+	HostClusterVGSCAnnotation              = "vcluster.loft.sh/host-volumegroupsnapshotcontent"
+	PhysicalVGSCGarbageCollectionFinalizer = "vcluster.loft.sh/physical-volumegroupsnapshotcontent-gc"
+)
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &volumeGroupSnapshotContentSyncer{
+		Translator: translator.NewClusterTranslator(ctx, "volume-group-snapshot-content", &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{}, NewVolumeGroupSnapshotContentTranslator()),
+
+		virtualClient: ctx.VirtualManager.GetClient(),
+	}, nil
+}
+
+type volumeGroupSnapshotContentSyncer struct {
+	translator.Translator
+
+	virtualClient client.Client
+}
+
+var _ syncer.Initializer = &volumeGroupSnapshotContentSyncer{}
+
+func (s *volumeGroupSnapshotContentSyncer) Init(registerContext *synccontext.RegisterContext) error {
+	return util.EnsureCRDFromFile(registerContext.Context, registerContext.VirtualManager.GetConfig(), path.Join(constants.ContainerManifestsFolder, crdPath), volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"))
+}
+
+func NewVolumeGroupSnapshotContentTranslator() translate.PhysicalNameTranslator {
+	return func(vName string, vObj client.Object) string {
+		return translateVolumeGroupSnapshotContentName(vName, vObj)
+	}
+}
+
+var _ syncer.IndicesRegisterer = &volumeGroupSnapshotContentSyncer{}
+
+func (s *volumeGroupSnapshotContentSyncer) RegisterIndices(ctx *synccontext.RegisterContext) error {
+	return ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{}, constants.IndexByPhysicalName, newIndexByVGSCPhysicalName())
+}
+
+func newIndexByVGSCPhysicalName() client.IndexerFunc {
+	return func(rawObj client.Object) []string {
+		return []string{translateVolumeGroupSnapshotContentName(rawObj.GetName(), rawObj)}
+	}
+}
+
+var _ syncer.UpSyncer = &volumeGroupSnapshotContentSyncer{}
+
+func (s *volumeGroupSnapshotContentSyncer) SyncUp(ctx *synccontext.SyncContext, pObj client.Object) (ctrl.Result, error) {
+	pVGSC := pObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent)
+	// check if the VolumeGroupSnapshotContent should get synced
+	sync, vVGS, err := s.shouldSync(ctx.Context, pVGSC)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !sync {
+		// ignore this VolumeGroupSnapshotContent resource, because there is no virtual VolumeGroupSnapshot bound to it
+		return ctrl.Result{}, nil
+	}
+
+	vVGSC := s.translateBackwards(pVGSC, vVGS)
+	ctx.Log.Infof("create VolumeGroupSnapshotContent %s, because it does not exist in the virtual cluster", vVGSC.Name)
+	return ctrl.Result{}, s.virtualClient.Create(ctx.Context, vVGSC)
+}
+
+var _ syncer.Syncer = &volumeGroupSnapshotContentSyncer{}
+
+func (s *volumeGroupSnapshotContentSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	vVGSC := vObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent)
+	if vVGSC.DeletionTimestamp != nil || (vVGSC.Annotations != nil && vVGSC.Annotations[HostClusterVGSCAnnotation] != "") {
+		if len(vVGSC.Finalizers) > 0 {
+			// delete the finalizer here so that the object can be deleted
+			vVGSC.Finalizers = []string{}
+			ctx.Log.Infof("remove virtual VolumeGroupSnapshotContent %s finalizers, because object should get deleted", vVGSC.Name)
+			return ctrl.Result{}, s.virtualClient.Update(ctx.Context, vVGSC)
+		}
+
+		ctx.Log.Infof("remove virtual VolumeGroupSnapshotContent %s, because object should get deleted", vVGSC.Name)
+		return ctrl.Result{}, s.virtualClient.Delete(ctx.Context, vVGSC)
+	}
+
+	pVGSC, err := s.translate(ctx.Context, vVGSC)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ctx.Log.Infof("create physical VolumeGroupSnapshotContent %s, because there is a virtual VolumeGroupSnapshotContent", pVGSC.Name)
+	err = ctx.PhysicalClient.Create(ctx.Context, pVGSC)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (s *volumeGroupSnapshotContentSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	pVGSC := pObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent)
+	vVGSC := vObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent)
+
+	// check if objects are getting deleted
+	if vObj.GetDeletionTimestamp() != nil {
+		if pObj.GetDeletionTimestamp() == nil {
+			ctx.Log.Infof("delete physical VolumeGroupSnapshotContent %s, because virtual VolumeGroupSnapshotContent is being deleted", pObj.GetName())
+			err := ctx.PhysicalClient.Delete(ctx.Context, pObj)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// sync finalizers and status to allow tracking of the deletion progress
+		// we can not add new finalizers from physical to virtual once it has deletionTimestamp, we can only remove finalizers
+		if !equality.Semantic.DeepEqual(vVGSC.Finalizers, pVGSC.Finalizers) {
+			updated := vVGSC.DeepCopy()
+			updated.Finalizers = pVGSC.Finalizers
+			ctx.Log.Infof("update finalizers of the virtual VolumeGroupSnapshotContent %s, because finalizers on the physical resource changed", vVGSC.Name)
+			translator.PrintChanges(vObj, updated, ctx.Log)
+			err := s.virtualClient.Update(ctx.Context, updated)
+			if kerrors.IsNotFound(err) {
+				return ctrl.Result{RequeueAfter: time.Second}, nil
+			}
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if !equality.Semantic.DeepEqual(vVGSC.Status, pVGSC.Status) {
+			updated := vVGSC.DeepCopy()
+			updated.Status = pVGSC.Status.DeepCopy()
+			ctx.Log.Infof("update virtual VolumeGroupSnapshotContent %s, because status has changed", vVGSC.Name)
+			translator.PrintChanges(vObj, updated, ctx.Log)
+			err := s.virtualClient.Status().Update(ctx.Context, updated)
+			if err != nil && !kerrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	// check if the VolumeGroupSnapshotContent should get synced
+	sync, vVGS, err := s.shouldSync(ctx.Context, pVGSC)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if !sync {
+		// ignore VolumeGroupSnapshotContent object for which VolumeGroupSnapshot was deleted,
+		// it will be automatically managed by the snapshot controller based on deletion policy
+		return ctrl.Result{}, nil
+	}
+
+	updatedObj := s.translateUpdateBackwards(pVGSC, vVGSC, vVGS)
+	if updatedObj != nil {
+		ctx.Log.Infof("update virtual VolumeGroupSnapshotContent %s, because spec or metadata(annotations or labels) have changed", vVGSC.Name)
+		translator.PrintChanges(vObj, updatedObj, ctx.Log)
+		return ctrl.Result{}, s.virtualClient.Update(ctx.Context, updatedObj)
+	}
+
+	// update virtual status if it differs
+	if !equality.Semantic.DeepEqual(vVGSC.Status, pVGSC.Status) {
+		newVGSC := vVGSC.DeepCopy()
+		newVGSC.Status = pVGSC.Status.DeepCopy()
+		translator.PrintChanges(vObj, newVGSC, ctx.Log)
+		ctx.Log.Infof("update virtual VolumeGroupSnapshotContent %s, because status has changed", vVGSC.Name)
+		return ctrl.Result{}, s.virtualClient.Status().Update(ctx.Context, newVGSC)
+	}
+
+	// update the physical VolumeGroupSnapshotContent if the virtual has changed
+	if vVGSC.Annotations == nil || vVGSC.Annotations[HostClusterVGSCAnnotation] == "" {
+		if vVGSC.DeletionTimestamp != nil {
+			if pVGSC.DeletionTimestamp != nil {
+				return ctrl.Result{}, nil
+			}
+
+			ctx.Log.Infof("delete physical VolumeGroupSnapshotContent %s, because virtual VolumeGroupSnapshotContent is being deleted", pVGSC.Name)
+			err := ctx.PhysicalClient.Delete(ctx.Context, pVGSC, &client.DeleteOptions{
+				GracePeriodSeconds: vVGSC.DeletionGracePeriodSeconds,
+				Preconditions:      metav1.NewUIDPreconditions(string(pVGSC.UID)),
+			})
+			if kerrors.IsNotFound(err) {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+
+		updatedVGSC := s.translateUpdate(ctx.Context, vVGSC, pVGSC)
+		if updatedVGSC != nil {
+			ctx.Log.Infof("update physical VolumeGroupSnapshotContent %s, because spec or annotations have changed", updatedVGSC.Name)
+			translator.PrintChanges(pVGSC, updatedVGSC, ctx.Log)
+			err := ctx.PhysicalClient.Update(ctx.Context, updatedVGSC)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (s *volumeGroupSnapshotContentSyncer) shouldSync(ctx context.Context, pObj *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent) (bool, *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot, error) {
+	vVGS := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshot{}
+	err := clienthelper.GetByIndex(ctx, s.virtualClient, vVGS, constants.IndexByPhysicalName, pObj.Spec.VolumeGroupSnapshotRef.Namespace+"/"+pObj.Spec.VolumeGroupSnapshotRef.Name)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, nil, err
+		} else if translate.Default.IsManagedCluster(pObj) {
+			return true, nil, nil
+		}
+		return false, nil, nil
+	}
+
+	return true, vVGS, nil
+}
+
+func (s *volumeGroupSnapshotContentSyncer) IsManaged(ctx context.Context, pObj client.Object) (bool, error) {
+	pVGSC, ok := pObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent)
+	if !ok {
+		return false, nil
+	}
+
+	sync, _, err := s.shouldSync(ctx, pVGSC)
+	if err != nil {
+		return false, nil
+	}
+
+	return sync, nil
+}
+
+func (s *volumeGroupSnapshotContentSyncer) VirtualToPhysical(_ context.Context, req types.NamespacedName, vObj client.Object) types.NamespacedName {
+	return types.NamespacedName{Name: translateVolumeGroupSnapshotContentName(req.Name, vObj)}
+}
+
+func (s *volumeGroupSnapshotContentSyncer) PhysicalToVirtual(ctx context.Context, pObj client.Object) types.NamespacedName {
+	pAnnotations := pObj.GetAnnotations()
+	if pAnnotations != nil && pAnnotations[translate.NameAnnotation] != "" {
+		return types.NamespacedName{
+			Name: pAnnotations[translate.NameAnnotation],
+		}
+	}
+
+	vObj := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{}
+	err := clienthelper.GetByIndex(ctx, s.virtualClient, vObj, constants.IndexByPhysicalName, pObj.GetName())
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return types.NamespacedName{}
+		}
+
+		return types.NamespacedName{Name: pObj.GetName()}
+	}
+
+	return types.NamespacedName{Name: vObj.GetName()}
+}
+
+func translateVolumeGroupSnapshotContentName(name string, vObj runtime.Object) string {
+	if vObj == nil {
+		return name
+	}
+
+	vVGSC, ok := vObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent)
+	if !ok || vVGSC.Annotations == nil || vVGSC.Annotations[HostClusterVGSCAnnotation] == "" {
+		return translate.Default.PhysicalNameClusterScoped(name)
+	}
+
+	return vVGSC.Annotations[HostClusterVGSCAnnotation]
+}
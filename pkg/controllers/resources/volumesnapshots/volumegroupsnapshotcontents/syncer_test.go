@@ -0,0 +1,277 @@
+package volumegroupsnapshotcontents
+
+import (
+	"testing"
+	"time"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"gotest.tools/assert"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	targetNamespace = "test"
+)
+
+func newFakeSyncer(t *testing.T, ctx *synccontext.RegisterContext) (*synccontext.SyncContext, *volumeGroupSnapshotContentSyncer) {
+	err := ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{}, constants.IndexByPhysicalName, newIndexByVGSCPhysicalName())
+	assert.NilError(t, err)
+	err = ctx.VirtualManager.GetFieldIndexer().IndexField(ctx.Context, &volumegroupsnapshotv1alpha1.VolumeGroupSnapshot{}, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
+		return []string{translate.Default.PhysicalNamespace(rawObj.GetNamespace()) + "/" + translate.Default.PhysicalName(rawObj.GetName(), rawObj.GetNamespace())}
+	})
+	assert.NilError(t, err)
+
+	syncContext, object := generictesting.FakeStartSyncer(t, ctx, New)
+	return syncContext, object.(*volumeGroupSnapshotContentSyncer)
+}
+
+func TestSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(targetNamespace)
+
+	vVolumeGroupSnapshot := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "group-snapshot",
+			Namespace:       "ns-abc",
+			ResourceVersion: "1111",
+		},
+	}
+	pVolumeGroupSnapshot := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName(vVolumeGroupSnapshot.Name, vVolumeGroupSnapshot.Namespace),
+			Namespace: targetNamespace,
+		},
+	}
+
+	vPersistentVolume := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "virtual-pv",
+		},
+	}
+	vHostPersistentVolume := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pvc-1234",
+			Annotations: map[string]string{
+				persistentvolumes.HostClusterPersistentVolumeAnnotation: "pvc-1234",
+			},
+		},
+	}
+
+	vObjectMeta := metav1.ObjectMeta{
+		Name:            "test-group-snapshotcontent",
+		ResourceVersion: "789",
+	}
+	vPreProvisioned := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{
+		ObjectMeta: vObjectMeta,
+		Spec: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContentSpec{
+			VolumeGroupSnapshotRef: corev1.ObjectReference{
+				Name:      vVolumeGroupSnapshot.Name,
+				Namespace: vVolumeGroupSnapshot.Namespace,
+			},
+			DeletionPolicy: volumesnapshotv1.VolumeSnapshotContentRetain,
+			Driver:         "something.csi.k8s.io",
+			Source: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContentSource{
+				VolumeGroupSnapshotHandle: pointer.String("some-group-handle"),
+			},
+		},
+	}
+
+	pPreProvisioned := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            translate.Default.PhysicalNameClusterScoped(vPreProvisioned.Name),
+			ResourceVersion: "12345",
+			Annotations: map[string]string{
+				translate.NameAnnotation: vObjectMeta.Name,
+				translate.UIDAnnotation:  "",
+			},
+		},
+		Spec: *vPreProvisioned.Spec.DeepCopy(),
+	}
+	pPreProvisioned.Spec.VolumeGroupSnapshotRef = corev1.ObjectReference{
+		Name:      translate.Default.PhysicalName(vPreProvisioned.Spec.VolumeGroupSnapshotRef.Name, vPreProvisioned.Spec.VolumeGroupSnapshotRef.Namespace),
+		Namespace: targetNamespace,
+	}
+
+	vPreProvisionedWithVolumes := vPreProvisioned.DeepCopy()
+	vPreProvisionedWithVolumes.Spec.Source = volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContentSource{
+		PersistentVolumeNames: []string{vPersistentVolume.Name, vHostPersistentVolume.Name},
+	}
+	pPreProvisionedWithVolumes := pPreProvisioned.DeepCopy()
+	pPreProvisionedWithVolumes.Spec.Source = volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContentSource{
+		PersistentVolumeNames: []string{translate.Default.PhysicalNameClusterScoped(vPersistentVolume.Name), vHostPersistentVolume.Name},
+	}
+
+	pDynamic := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "groupsnapcontent-abcd",
+			ResourceVersion: "12345",
+		},
+		Spec: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContentSpec{
+			VolumeGroupSnapshotRef: corev1.ObjectReference{
+				Name:      translate.Default.PhysicalName(vVolumeGroupSnapshot.Name, vVolumeGroupSnapshot.Namespace),
+				Namespace: targetNamespace,
+			},
+			DeletionPolicy:               volumesnapshotv1.VolumeSnapshotContentDelete,
+			Driver:                       "something.csi.k8s.io",
+			VolumeGroupSnapshotClassName: pointer.String("classy-class"),
+			Source: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContentSource{
+				PersistentVolumeNames: []string{"pvc-1234"},
+			},
+		},
+	}
+
+	vDynamic := pDynamic.DeepCopy()
+	vDynamic.Annotations = map[string]string{HostClusterVGSCAnnotation: pDynamic.Name}
+	vDynamic.Spec.VolumeGroupSnapshotRef = corev1.ObjectReference{
+		Name:            vVolumeGroupSnapshot.Name,
+		Namespace:       vVolumeGroupSnapshot.Namespace,
+		ResourceVersion: vVolumeGroupSnapshot.ResourceVersion,
+	}
+
+	vWithGCFinalizer := vDynamic.DeepCopy()
+	vWithGCFinalizer.Finalizers = []string{PhysicalVGSCGarbageCollectionFinalizer}
+
+	pWithStatus := pDynamic.DeepCopy()
+	pWithStatus.Status = &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContentStatus{
+		ReadyToUse: pointer.Bool(false),
+		Error:      &volumesnapshotv1.VolumeSnapshotError{Message: pointer.String("the stars didn't align error")},
+	}
+	vWithStatus := vWithGCFinalizer.DeepCopy()
+	vWithStatus.Status = pWithStatus.Status
+
+	vModifiedDeletionPolicy := vPreProvisioned.DeepCopy()
+	vModifiedDeletionPolicy.Spec.DeletionPolicy = volumesnapshotv1.VolumeSnapshotContentDelete
+	vModifiedDeletionPolicy.Finalizers = []string{PhysicalVGSCGarbageCollectionFinalizer}
+	pModifiedDeletionPolicy := pPreProvisioned.DeepCopy()
+	pModifiedDeletionPolicy.Spec.DeletionPolicy = vModifiedDeletionPolicy.Spec.DeletionPolicy
+
+	vDeleting := vPreProvisioned.DeepCopy()
+	vDeleting.Finalizers = []string{"kubernetes"}
+	deletionTime := metav1.NewTime(time.Now().Add(-5 * time.Second)).Rfc3339Copy()
+	vDeleting.DeletionTimestamp = &deletionTime
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Create dynamic VolumeGroupSnapshotContent from host",
+			InitialVirtualState:  []runtime.Object{vVolumeGroupSnapshot.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pDynamic.DeepCopy(), pVolumeGroupSnapshot.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {vDynamic.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {pDynamic.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.SyncUp(syncCtx, pDynamic.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Create pre-provisioned VolumeGroupSnapshotContent from vcluster",
+			InitialVirtualState:  []runtime.Object{vPreProvisioned.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {vPreProvisioned.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {pPreProvisioned.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.SyncDown(syncCtx, vPreProvisioned.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Translate persistent volume names of pre-provisioned VolumeGroupSnapshotContent",
+			InitialVirtualState:  []runtime.Object{vPreProvisionedWithVolumes.DeepCopy(), vPersistentVolume.DeepCopy(), vHostPersistentVolume.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {vPreProvisionedWithVolumes.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {pPreProvisionedWithVolumes.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.SyncDown(syncCtx, vPreProvisionedWithVolumes.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Ensure a finalizer is added to a virtual VolumeGroupSnapshotContent",
+			InitialVirtualState:  []runtime.Object{vDynamic.DeepCopy(), vVolumeGroupSnapshot.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pDynamic.DeepCopy(), pVolumeGroupSnapshot.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {vWithGCFinalizer.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {pDynamic.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, pDynamic.DeepCopy(), vDynamic.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update status from physical to virtual",
+			InitialVirtualState:  []runtime.Object{vWithGCFinalizer.DeepCopy(), vVolumeGroupSnapshot.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pWithStatus.DeepCopy(), pVolumeGroupSnapshot.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {vWithStatus.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {pWithStatus.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, pWithStatus.DeepCopy(), vWithGCFinalizer.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update .spec.DeletionPolicy from virtual to physical",
+			InitialVirtualState:  []runtime.Object{vModifiedDeletionPolicy.DeepCopy(), vVolumeGroupSnapshot.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pPreProvisioned.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {vModifiedDeletionPolicy.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {pModifiedDeletionPolicy.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, pPreProvisioned.DeepCopy(), vModifiedDeletionPolicy.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Delete in host when virtual is being deleted",
+			InitialVirtualState:  []runtime.Object{vDeleting.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pPreProvisioned.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshotContent"): {}},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.Sync(syncCtx, pPreProvisioned.DeepCopy(), vDeleting.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
@@ -0,0 +1,109 @@
+package volumegroupsnapshotcontents
+
+import (
+	"context"
+	"fmt"
+
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func (s *volumeGroupSnapshotContentSyncer) translate(ctx context.Context, vVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent) (*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent, error) {
+	pVGSC := s.TranslateMetadata(ctx, vVGSC).(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent)
+	pVGSC.Spec.VolumeGroupSnapshotRef = corev1.ObjectReference{
+		Namespace: translate.Default.PhysicalNamespace(vVGSC.Spec.VolumeGroupSnapshotRef.Namespace),
+		Name:      translate.Default.PhysicalName(vVGSC.Spec.VolumeGroupSnapshotRef.Name, vVGSC.Spec.VolumeGroupSnapshotRef.Namespace),
+	}
+
+	// the persistent volumes of a pre-provisioned group snapshot have to be translated to the host names
+	if len(vVGSC.Spec.Source.PersistentVolumeNames) > 0 {
+		pvNameTranslator := persistentvolumes.NewPersistentVolumeTranslator()
+		pVGSC.Spec.Source.PersistentVolumeNames = []string{}
+		for _, pvName := range vVGSC.Spec.Source.PersistentVolumeNames {
+			vPv := &corev1.PersistentVolume{}
+			err := s.virtualClient.Get(ctx, client.ObjectKey{Name: pvName}, vPv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get virtual PersistentVolume %s referenced as source of the %s VolumeGroupSnapshotContent: %v", pvName, vVGSC.Name, err)
+			}
+
+			pVGSC.Spec.Source.PersistentVolumeNames = append(pVGSC.Spec.Source.PersistentVolumeNames, pvNameTranslator(vPv.Name, vPv))
+		}
+	}
+
+	return pVGSC, nil
+}
+
+func (s *volumeGroupSnapshotContentSyncer) translateBackwards(pVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent, vVGS *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot) *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent {
+	// build virtual VolumeGroupSnapshotContent object
+	vObj := pVGSC.DeepCopy()
+	vObj.ResourceVersion = ""
+	vObj.UID = ""
+	vObj.ManagedFields = nil
+
+	if vVGS != nil {
+		vObj.Spec.VolumeGroupSnapshotRef = translateVolumeGroupSnapshotRefBackwards(&vObj.Spec.VolumeGroupSnapshotRef, vVGS)
+	}
+
+	if vObj.Annotations == nil {
+		vObj.Annotations = map[string]string{}
+	}
+	vObj.Annotations[HostClusterVGSCAnnotation] = pVGSC.Name
+
+	return vObj
+}
+
+func (s *volumeGroupSnapshotContentSyncer) translateUpdateBackwards(pVGSC, vVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent, _ *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot) *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent {
+	var updated *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent
+
+	// add a finalizer to ensure that we delete the physical VolumeGroupSnapshotContent object when virtual is being deleted
+	pCopy := pVGSC.DeepCopy()
+	if pCopy.Finalizers == nil {
+		pCopy.Finalizers = []string{}
+	}
+	controllerutil.AddFinalizer(pCopy, PhysicalVGSCGarbageCollectionFinalizer)
+
+	if !equality.Semantic.DeepEqual(vVGSC.Finalizers, pCopy.Finalizers) {
+		updated = translator.NewIfNil(updated, vVGSC)
+		updated.Finalizers = pCopy.Finalizers
+	}
+
+	return updated
+}
+
+func (s *volumeGroupSnapshotContentSyncer) translateUpdate(ctx context.Context, vVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent, pVGSC *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent) *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent {
+	var updated *volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent
+
+	if !equality.Semantic.DeepEqual(pVGSC.Spec.DeletionPolicy, vVGSC.Spec.DeletionPolicy) {
+		updated = translator.NewIfNil(updated, pVGSC)
+		updated.Spec.DeletionPolicy = vVGSC.Spec.DeletionPolicy
+	}
+
+	if !equality.Semantic.DeepEqual(pVGSC.Spec.VolumeGroupSnapshotClassName, vVGSC.Spec.VolumeGroupSnapshotClassName) {
+		updated = translator.NewIfNil(updated, pVGSC)
+		updated.Spec.VolumeGroupSnapshotClassName = vVGSC.Spec.VolumeGroupSnapshotClassName
+	}
+
+	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vVGSC, pVGSC)
+	if changed {
+		updated = translator.NewIfNil(updated, pVGSC)
+		updated.Annotations = updatedAnnotations
+		updated.Labels = updatedLabels
+	}
+
+	return updated
+}
+
+func translateVolumeGroupSnapshotRefBackwards(ref *corev1.ObjectReference, vVGS *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot) corev1.ObjectReference {
+	newRef := ref.DeepCopy()
+	newRef.Namespace = vVGS.Namespace
+	newRef.Name = vVGS.Name
+	newRef.UID = vVGS.UID
+	newRef.ResourceVersion = vVGS.ResourceVersion
+	return *newRef
+}
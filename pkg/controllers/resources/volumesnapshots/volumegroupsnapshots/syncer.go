@@ -0,0 +1,163 @@
+package volumegroupsnapshots
+
+import (
+	"path"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util"
+
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/volumesnapshots/volumegroupsnapshotcontents"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// file path relative to the manifests folder in the container
+	crdPath = "volumegroupsnapshots/groupsnapshot.storage.k8s.io_volumegroupsnapshots.yaml"
+)
+
+var (
+	// Default grace period in seconds
+	minimumGracePeriodInSeconds int64 = 30
+	zero                              = int64(0)
+)
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &volumeGroupSnapshotSyncer{
+		NamespacedTranslator:                     translator.NewNamespacedTranslator(ctx, "volume-group-snapshot", &volumegroupsnapshotv1alpha1.VolumeGroupSnapshot{}),
+		volumeGroupSnapshotContentNameTranslator: volumegroupsnapshotcontents.NewVolumeGroupSnapshotContentTranslator(),
+	}, nil
+}
+
+type volumeGroupSnapshotSyncer struct {
+	translator.NamespacedTranslator
+	volumeGroupSnapshotContentNameTranslator translate.PhysicalNameTranslator
+}
+
+var _ syncer.Initializer = &volumeGroupSnapshotSyncer{}
+
+func (s *volumeGroupSnapshotSyncer) Init(registerContext *synccontext.RegisterContext) error {
+	return util.EnsureCRDFromFile(registerContext.Context, registerContext.VirtualManager.GetConfig(), path.Join(constants.ContainerManifestsFolder, crdPath), volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"))
+}
+
+var _ syncer.Syncer = &volumeGroupSnapshotSyncer{}
+
+func (s *volumeGroupSnapshotSyncer) SyncDown(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	vVGS := vObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshot)
+	if vVGS.DeletionTimestamp != nil {
+		// delete volume group snapshot immediately
+		if len(vObj.GetFinalizers()) > 0 || (vObj.GetDeletionGracePeriodSeconds() != nil && *vObj.GetDeletionGracePeriodSeconds() > 0) {
+			vObj.SetFinalizers([]string{})
+			vObj.SetDeletionGracePeriodSeconds(&zero)
+			return ctrl.Result{}, ctx.VirtualClient.Update(ctx.Context, vObj)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	pObj, err := s.translate(ctx, vVGS)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return s.SyncDownCreate(ctx, vObj, pObj)
+}
+
+func (s *volumeGroupSnapshotSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	vVGS := vObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshot)
+	pVGS := pObj.(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshot)
+
+	if pVGS.DeletionTimestamp != nil {
+		if vVGS.DeletionTimestamp == nil {
+			ctx.Log.Infof("delete virtual volume group snapshot %s/%s, because the physical volume group snapshot is being deleted", vVGS.Namespace, vVGS.Name)
+			err := ctx.VirtualClient.Delete(ctx.Context, vVGS, &client.DeleteOptions{GracePeriodSeconds: &minimumGracePeriodInSeconds})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if *vVGS.DeletionGracePeriodSeconds != *pVGS.DeletionGracePeriodSeconds {
+			ctx.Log.Infof("delete virtual volume group snapshot %s/%s with grace period seconds %v", vVGS.Namespace, vVGS.Name, *pVGS.DeletionGracePeriodSeconds)
+			err := ctx.VirtualClient.Delete(ctx.Context, vVGS, &client.DeleteOptions{GracePeriodSeconds: pVGS.DeletionGracePeriodSeconds, Preconditions: metav1.NewUIDPreconditions(string(vVGS.UID))})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// sync finalizers and status to allow tracking of the deletion progress
+		// we can not add new finalizers from physical to virtual once it has deletionTimestamp, we can only remove finalizers
+		if !equality.Semantic.DeepEqual(vVGS.Finalizers, pVGS.Finalizers) {
+			updated := vVGS.DeepCopy()
+			updated.Finalizers = pVGS.Finalizers
+			ctx.Log.Infof("update finalizers of the virtual VolumeGroupSnapshot %s, because finalizers on the physical resource changed", vVGS.Name)
+			translator.PrintChanges(vObj, updated, ctx.Log)
+			err := ctx.VirtualClient.Update(ctx.Context, updated)
+			if kerrors.IsNotFound(err) {
+				return ctrl.Result{}, nil
+			}
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if !equality.Semantic.DeepEqual(vVGS.Status, pVGS.Status) {
+			updated := vVGS.DeepCopy()
+			updated.Status = pVGS.Status.DeepCopy()
+			ctx.Log.Infof("update virtual VolumeGroupSnapshot %s, because status has changed", vVGS.Name)
+			translator.PrintChanges(vObj, updated, ctx.Log)
+			err := ctx.VirtualClient.Status().Update(ctx.Context, updated)
+			if err != nil && !kerrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else if vVGS.DeletionTimestamp != nil {
+		if pVGS.DeletionTimestamp == nil {
+			ctx.Log.Infof("delete physical volume group snapshot %s/%s, because virtual volume group snapshot is being deleted", pVGS.Namespace, pVGS.Name)
+			return ctrl.Result{}, ctx.PhysicalClient.Delete(ctx.Context, pVGS, &client.DeleteOptions{
+				GracePeriodSeconds: vVGS.DeletionGracePeriodSeconds,
+				Preconditions:      metav1.NewUIDPreconditions(string(pVGS.UID)),
+			})
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// check backwards update
+	updated := s.translateUpdateBackwards(pVGS, vVGS)
+	if updated != nil {
+		ctx.Log.Infof("update virtual volume group snapshot %s/%s, because the spec has changed", vVGS.Namespace, vVGS.Name)
+		translator.PrintChanges(vObj, updated, ctx.Log)
+		err := ctx.VirtualClient.Update(ctx.Context, updated)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// check backwards status
+	if !equality.Semantic.DeepEqual(vVGS.Status, pVGS.Status) {
+		updated := vVGS.DeepCopy()
+		updated.Status = pVGS.Status.DeepCopy()
+		ctx.Log.Infof("update virtual volume group snapshot %s/%s, because the status has changed", vVGS.Namespace, vVGS.Name)
+		translator.PrintChanges(vObj, updated, ctx.Log)
+		err := ctx.VirtualClient.Status().Update(ctx.Context, updated)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// forward update
+	updated = s.translateUpdate(ctx.Context, pVGS, vVGS)
+	if updated != nil {
+		translator.PrintChanges(pVGS, updated, ctx.Log)
+	}
+
+	return s.SyncDownUpdate(ctx, vVGS, updated)
+}
@@ -0,0 +1,230 @@
+package volumegroupsnapshots
+
+import (
+	"testing"
+	"time"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"gotest.tools/assert"
+
+	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	targetNamespace = "test"
+)
+
+func TestSync(t *testing.T) {
+	translate.Default = translate.NewSingleNamespaceTranslator(targetNamespace)
+
+	vObjectMeta := metav1.ObjectMeta{
+		Name:            "test-group-snapshot",
+		Namespace:       "test",
+		ResourceVersion: "999",
+	}
+	vSelectorSource := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshot{
+		ObjectMeta: vObjectMeta,
+		Spec: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotSpec{
+			Source: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotSource{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "database"},
+				},
+			},
+			VolumeGroupSnapshotClassName: pointer.String("my-group-class"),
+		},
+	}
+	vDeleting := vSelectorSource.DeepCopy()
+	vDeleting.Finalizers = []string{"kubernetes"}
+	deletionTime := metav1.NewTime(time.Now().Add(-5 * time.Second)).Rfc3339Copy()
+	vDeleting.DeletionTimestamp = &deletionTime
+
+	vVolumeGroupSnapshotContent := volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-vgsc-name",
+		},
+	}
+	vContentSource := vSelectorSource.DeepCopy()
+	vContentSource.Spec.Source = volumegroupsnapshotv1alpha1.VolumeGroupSnapshotSource{
+		VolumeGroupSnapshotContentName: pointer.String(vVolumeGroupSnapshotContent.Name),
+	}
+
+	pObjectMeta := metav1.ObjectMeta{
+		Name:            translate.Default.PhysicalName(vObjectMeta.Name, vObjectMeta.Namespace),
+		Namespace:       targetNamespace,
+		ResourceVersion: "1234",
+		Annotations: map[string]string{
+			translate.NameAnnotation:      vObjectMeta.Name,
+			translate.NamespaceAnnotation: vObjectMeta.Namespace,
+			translate.UIDAnnotation:       "",
+		},
+		Labels: map[string]string{
+			translate.MarkerLabel:    translate.Suffix,
+			translate.NamespaceLabel: vObjectMeta.Namespace,
+		},
+	}
+	pSelectorSource := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshot{
+		ObjectMeta: pObjectMeta,
+		Spec: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotSpec{
+			Source: volumegroupsnapshotv1alpha1.VolumeGroupSnapshotSource{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						translate.Default.ConvertLabelKey("app"): "database",
+						translate.MarkerLabel:                    translate.Suffix,
+						translate.NamespaceLabel:                 vObjectMeta.Namespace,
+					},
+				},
+			},
+			VolumeGroupSnapshotClassName: vSelectorSource.Spec.VolumeGroupSnapshotClassName,
+		},
+	}
+	pContentSource := pSelectorSource.DeepCopy()
+	pContentSource.Spec.Source = volumegroupsnapshotv1alpha1.VolumeGroupSnapshotSource{
+		VolumeGroupSnapshotContentName: pointer.String(translate.Default.PhysicalNameClusterScoped(vVolumeGroupSnapshotContent.Name)),
+	}
+
+	vWithNilClass := vSelectorSource.DeepCopy()
+	vWithNilClass.Spec.VolumeGroupSnapshotClassName = nil
+
+	finalizers := []string{"groupsnapshot.storage.kubernetes.io/volumegroupsnapshot-protection"}
+	vWithFinalizers := vSelectorSource.DeepCopy()
+	vWithFinalizers.Finalizers = finalizers
+	pWithFinalizers := pSelectorSource.DeepCopy()
+	pWithFinalizers.Finalizers = finalizers
+
+	pWithStatus := pSelectorSource.DeepCopy()
+	pWithStatus.Status = &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotStatus{
+		ReadyToUse: pointer.Bool(false),
+		Error:      &volumesnapshotv1.VolumeSnapshotError{Message: pointer.String("random error")},
+		VolumeSnapshotRefList: []corev1.ObjectReference{
+			{Name: "snapshot-abc", Namespace: targetNamespace},
+		},
+	}
+	vWithStatus := vSelectorSource.DeepCopy()
+	vWithStatus.Status = pWithStatus.Status
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Create with label selector source",
+			InitialVirtualState:  []runtime.Object{vSelectorSource.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {vSelectorSource.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {pSelectorSource.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotSyncer).SyncDown(syncCtx, vSelectorSource.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Create with VolumeGroupSnapshotContent source",
+			InitialVirtualState:  []runtime.Object{vContentSource.DeepCopy(), vVolumeGroupSnapshotContent.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {vContentSource.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {pContentSource.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotSyncer).SyncDown(syncCtx, vContentSource.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Immutable .spec.source field is not synced on update",
+			InitialVirtualState:  []runtime.Object{vContentSource.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pSelectorSource.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {vContentSource.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {pSelectorSource.DeepCopy()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotSyncer).Sync(syncCtx, pSelectorSource.DeepCopy(), vContentSource.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "VolumeGroupSnapshotClassName is changed on update",
+			InitialVirtualState:  []runtime.Object{vWithNilClass.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pSelectorSource.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {vWithNilClass.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {func() runtime.Object {
+					p := pSelectorSource.DeepCopy()
+					p.Spec.VolumeGroupSnapshotClassName = nil
+					return p
+				}()},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotSyncer).Sync(syncCtx, pSelectorSource.DeepCopy(), vWithNilClass.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync finalizers from physical to virtual",
+			InitialVirtualState:  []runtime.Object{vSelectorSource.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pWithFinalizers},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {vWithFinalizers},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {pWithFinalizers},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotSyncer).Sync(syncCtx, pWithFinalizers, vSelectorSource.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Sync status from physical to virtual",
+			InitialVirtualState:  []runtime.Object{vSelectorSource.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pWithStatus},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {vWithStatus},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {pWithStatus},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotSyncer).Sync(syncCtx, pWithStatus, vSelectorSource.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Delete in host when virtual is being deleted",
+			InitialVirtualState:  []runtime.Object{vDeleting},
+			InitialPhysicalState: []runtime.Object{pSelectorSource.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {vDeleting},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				volumegroupsnapshotv1alpha1.SchemeGroupVersion.WithKind("VolumeGroupSnapshot"): {}},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*volumeGroupSnapshotSyncer).Sync(syncCtx, pSelectorSource.DeepCopy(), vDeleting)
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package volumegroupsnapshots
+
+import (
+	"context"
+	"fmt"
+
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func (s *volumeGroupSnapshotSyncer) translate(ctx *synccontext.SyncContext, vVGS *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot) (*volumegroupsnapshotv1alpha1.VolumeGroupSnapshot, error) {
+	pVGS := s.TranslateMetadata(ctx.Context, vVGS).(*volumegroupsnapshotv1alpha1.VolumeGroupSnapshot)
+	if vVGS.Annotations != nil && vVGS.Annotations[constants.SkipTranslationAnnotation] == "true" {
+		pVGS.Spec.Source = vVGS.Spec.Source
+	} else {
+		if vVGS.Spec.Source.Selector != nil {
+			pVGS.Spec.Source.Selector = translatePersistentVolumeClaimSelector(vVGS.Spec.Source.Selector, vVGS.Namespace)
+		}
+		if vVGS.Spec.Source.VolumeGroupSnapshotContentName != nil {
+			vVGSC := &volumegroupsnapshotv1alpha1.VolumeGroupSnapshotContent{}
+			err := ctx.VirtualClient.Get(ctx.Context, client.ObjectKey{Name: *vVGS.Spec.Source.VolumeGroupSnapshotContentName}, vVGSC)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get virtual VolumeGroupSnapshotContent resource referenced as source of the %s VolumeGroupSnapshot: %v", vVGS.Name, err)
+			}
+			translatedName := s.volumeGroupSnapshotContentNameTranslator(vVGSC.Name, vVGSC)
+			pVGS.Spec.Source.VolumeGroupSnapshotContentName = &translatedName
+		}
+	}
+
+	pVGS.Spec.VolumeGroupSnapshotClassName = vVGS.Spec.VolumeGroupSnapshotClassName
+	return pVGS, nil
+}
+
+func (s *volumeGroupSnapshotSyncer) translateUpdate(ctx context.Context, pVGS, vVGS *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot) *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot {
+	var updated *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot
+
+	// group snapshot class can be updated
+	if !equality.Semantic.DeepEqual(pVGS.Spec.VolumeGroupSnapshotClassName, vVGS.Spec.VolumeGroupSnapshotClassName) {
+		updated = translator.NewIfNil(updated, pVGS)
+		updated.Spec.VolumeGroupSnapshotClassName = vVGS.Spec.VolumeGroupSnapshotClassName
+	}
+
+	// check if metadata changed
+	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vVGS, pVGS)
+	if changed {
+		updated = translator.NewIfNil(updated, pVGS)
+		updated.Annotations = updatedAnnotations
+		updated.Labels = updatedLabels
+	}
+
+	return updated
+}
+
+func (s *volumeGroupSnapshotSyncer) translateUpdateBackwards(pObj, vObj *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot) *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot {
+	var updated *volumegroupsnapshotv1alpha1.VolumeGroupSnapshot
+
+	// sync back the finalizers
+	if !equality.Semantic.DeepEqual(vObj.Finalizers, pObj.Finalizers) {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Finalizers = pObj.Finalizers
+	}
+	return updated
+}
+
+// translatePersistentVolumeClaimSelector translates the persistent volume claim selector of a group snapshot
+// and makes sure it only selects the synced persistent volume claims of the group snapshot's namespace
+func translatePersistentVolumeClaimSelector(selector *metav1.LabelSelector, vNamespace string) *metav1.LabelSelector {
+	pSelector := translate.Default.TranslateLabelSelector(selector)
+	if !translate.Default.SingleNamespaceTarget() {
+		return pSelector
+	}
+
+	if pSelector.MatchLabels == nil {
+		pSelector.MatchLabels = map[string]string{}
+	}
+	pSelector.MatchLabels[translate.MarkerLabel] = translate.Suffix
+	pSelector.MatchLabels[translate.NamespaceLabel] = vNamespace
+	return pSelector
+}
@@ -0,0 +1,173 @@
+package sleep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// the syncer image doesn't ship the timezone database
+	_ "time/tzdata"
+)
+
+// Schedule is a parsed cron schedule in the standard five field format
+// (minute, hour, day of month, month, day of week)
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// restricted days of month and week match if either of them matches, as with cron
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+
+	location *time.Location
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// ParseSchedule parses a cron schedule such as '0 20 * * 1-5'. Fields can be *, values, ranges, steps and lists
+// of these. The schedule can be prefixed with TZ=<timezone>, otherwise it is evaluated in UTC.
+func ParseSchedule(spec string) (*Schedule, error) {
+	schedule := &Schedule{location: time.UTC}
+
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		timezone, rest, _ := strings.Cut(spec, " ")
+		_, timezone, _ = strings.Cut(timezone, "=")
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+
+		schedule.location = location
+		spec = strings.TrimSpace(rest)
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected %d fields, got %d", spec, len(fields), len(parts))
+	}
+
+	values := make([]uint64, len(fields))
+	for i, part := range parts {
+		bits, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+
+		values[i] = bits
+	}
+
+	schedule.minute = values[0]
+	schedule.hour = values[1]
+	schedule.dayOfMonth = values[2]
+	schedule.month = values[3]
+	schedule.dayOfWeek = values[4]
+	schedule.anyDayOfMonth = parts[2] == "*"
+	schedule.anyDayOfWeek = parts[4] == "*"
+
+	// 7 is sunday as well
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+func parseField(value string, f field) (uint64, error) {
+	bits := uint64(0)
+	for _, item := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+			}
+		}
+
+		start, end := f.min, f.max
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			start, err = strconv.Atoi(startPart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q in %s field", startPart, f.name)
+			}
+
+			end = start
+			if isRange {
+				end, err = strconv.Atoi(endPart)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q in %s field", endPart, f.name)
+				}
+			} else if hasStep {
+				end = f.max
+			}
+		}
+		if start < f.min || end > f.max || start > end {
+			return 0, fmt.Errorf("%s field %q is out of range %d-%d", f.name, item, f.min, f.max)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << i
+		}
+	}
+
+	return bits, nil
+}
+
+// Next returns the first time after t that matches the schedule
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.location).Truncate(time.Minute).Add(time.Minute)
+
+	// a matching time is found within 5 years, unless the schedule can never match (e.g. 30th of february)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := s.dayOfWeek&(1<<int(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
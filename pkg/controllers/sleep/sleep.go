@@ -0,0 +1,391 @@
+package sleep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/utils/pkg/log"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
+	"github.com/loft-sh/vcluster/pkg/server/activity"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// ReasonInactivity is recorded if the virtual cluster went to sleep because there were no requests
+	ReasonInactivity = "Inactivity"
+	// ReasonSchedule is recorded if the virtual cluster went to sleep because of the sleep schedule
+	ReasonSchedule = "Schedule"
+
+	// WakeupApp is the app label of the wake-up pod
+	WakeupApp = "vcluster-wakeup"
+)
+
+// WakeupLabels returns the labels of the wake-up pod of the given virtual cluster
+func WakeupLabels(name string) map[string]string {
+	return map[string]string{
+		"app":     WakeupApp,
+		"release": name,
+	}
+}
+
+// Controller puts the virtual cluster to sleep after it was inactive for the configured duration or
+// on the sleep schedule. Before the virtual cluster is paused, a wake-up pod is started that takes over
+// the vcluster service and resumes the virtual cluster on the next connection.
+type Controller struct {
+	Client kubernetes.Interface
+
+	// Name is the name of the virtual cluster
+	Name string
+	// Namespace is the namespace the virtual cluster runs in
+	Namespace string
+	// ServiceName is the name of the vcluster service
+	ServiceName string
+	// Port is the port the syncer serves the virtual cluster api on
+	Port int
+
+	// SleepAfter is the duration without requests after which the virtual cluster goes to sleep. 0 disables it
+	SleepAfter time.Duration
+	// Schedule is the schedule when the virtual cluster goes to sleep. nil disables it
+	Schedule *Schedule
+	// Tracker records the requests to the virtual cluster
+	Tracker *activity.Tracker
+
+	// Interval is how often the controller checks if the virtual cluster should sleep
+	Interval time.Duration
+	// WakeupGracePeriod is how long the wake-up pod is kept after the syncer started, so that the
+	// connections it accepted while the virtual cluster woke up can finish
+	WakeupGracePeriod time.Duration
+
+	Log log.Logger
+
+	started   time.Time
+	lastCheck time.Time
+	asleep    bool
+
+	now func() time.Time
+}
+
+// Start checks periodically if the virtual cluster should go to sleep until the context is done
+func (c *Controller) Start(ctx context.Context) error {
+	c.started = c.getNow()
+	c.lastCheck = c.started
+
+	// the service still points to the wake-up pod if the virtual cluster was resumed manually
+	err := RestoreService(ctx, c.Client, c.Namespace, c.ServiceName)
+	if err != nil {
+		c.Log.Errorf("error restoring vcluster service: %v", err)
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		err := c.reconcile(ctx)
+		if err != nil {
+			c.Log.Errorf("error putting vcluster to sleep: %v", err)
+		}
+	}, c.Interval)
+
+	return nil
+}
+
+func (c *Controller) reconcile(ctx context.Context) error {
+	if c.asleep {
+		return nil
+	}
+
+	now := c.getNow()
+	if now.Sub(c.started) > c.WakeupGracePeriod {
+		err := c.deleteWakeupPods(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	reason := c.sleepReason(now)
+	c.lastCheck = now
+	if reason == "" {
+		return nil
+	}
+
+	err := c.Sleep(ctx, reason)
+	if err != nil {
+		return err
+	}
+
+	c.asleep = true
+	return nil
+}
+
+// sleepReason returns why the virtual cluster should go to sleep or an empty string if it shouldn't
+func (c *Controller) sleepReason(now time.Time) string {
+	if c.Schedule != nil {
+		next := c.Schedule.Next(c.lastCheck)
+		if !next.IsZero() && !next.After(now) {
+			return ReasonSchedule
+		}
+	}
+
+	if c.SleepAfter > 0 && c.Tracker != nil {
+		lastActivity, idle := c.Tracker.IdleSince()
+		if idle && now.Sub(lastActivity) >= c.SleepAfter {
+			return ReasonInactivity
+		}
+	}
+
+	return ""
+}
+
+// Sleep starts the wake-up pod, points the vcluster service to it and pauses the virtual cluster
+func (c *Controller) Sleep(ctx context.Context, reason string) error {
+	c.Log.Infof("Put vcluster %s to sleep (reason: %s)", c.Name, reason)
+	template, err := c.syncerPodTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
+	service, err := c.Client.CoreV1().Services(c.Namespace).Get(ctx, c.ServiceName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "get vcluster service")
+	}
+
+	err = c.deleteWakeupPods(ctx)
+	if err != nil {
+		return err
+	}
+
+	pod, err := c.Client.CoreV1().Pods(c.Namespace).Create(ctx, c.wakeupPod(template, service), metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "create wake-up pod")
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, time.Minute*2, true, func(ctx context.Context) (bool, error) {
+		pod, err = c.Client.CoreV1().Pods(c.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return isPodReady(pod), nil
+	})
+	if err != nil {
+		_ = c.deleteWakeupPods(ctx)
+		return errors.Wrap(err, "wait for wake-up pod")
+	}
+
+	err = updateServices(ctx, c.Client, c.Namespace, c.ServiceName, func(service *corev1.Service) error {
+		if service.Annotations[constants.SleepSelectorAnnotation] != "" {
+			return nil
+		}
+
+		selector, err := json.Marshal(service.Spec.Selector)
+		if err != nil {
+			return err
+		}
+
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+		service.Annotations[constants.SleepSelectorAnnotation] = string(selector)
+		service.Spec.Selector = WakeupLabels(c.Name)
+		return nil
+	})
+	if err != nil {
+		_ = RestoreService(ctx, c.Client, c.Namespace, c.ServiceName)
+		_ = c.deleteWakeupPods(ctx)
+		return errors.Wrap(err, "point vcluster service to wake-up pod")
+	}
+
+	// the syncer might be terminated while pausing the virtual cluster, so everything else needs to be done before
+	err = lifecycle.PauseVClusterWithAnnotations(ctx, c.Client, c.Name, c.Namespace, map[string]string{
+		constants.SleepReasonAnnotation: reason,
+	}, c.Log)
+	if err != nil {
+		_ = RestoreService(ctx, c.Client, c.Namespace, c.ServiceName)
+		_ = c.deleteWakeupPods(ctx)
+		return errors.Wrap(err, "pause vcluster")
+	}
+
+	return nil
+}
+
+// syncerPodTemplate returns the pod template of the statefulset or deployment the syncer runs in
+func (c *Controller) syncerPodTemplate(ctx context.Context) (*corev1.PodTemplateSpec, error) {
+	selector := labels.SelectorFromSet(map[string]string{"app": "vcluster", "release": c.Name}).String()
+	statefulSets, err := c.Client.AppsV1().StatefulSets(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "list statefulsets")
+	} else if len(statefulSets.Items) > 0 {
+		return &statefulSets.Items[0].Spec.Template, nil
+	}
+
+	deployments, err := c.Client.AppsV1().Deployments(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "list deployments")
+	} else if len(deployments.Items) > 0 {
+		return &deployments.Items[0].Spec.Template, nil
+	}
+
+	return nil, fmt.Errorf("couldn't find vcluster %s in namespace %s", c.Name, c.Namespace)
+}
+
+// wakeupPod returns the wake-up pod, which runs the syncer image with the settings of the syncer container.
+// The pod is owned by the vcluster service, so it is deleted together with the virtual cluster.
+func (c *Controller) wakeupPod(template *corev1.PodTemplateSpec, service *corev1.Service) *corev1.Pod {
+	var syncer *corev1.Container
+	for i := range template.Spec.Containers {
+		if template.Spec.Containers[i].Name == "syncer" {
+			syncer = &template.Spec.Containers[i]
+			break
+		}
+	}
+	if syncer == nil {
+		syncer = &template.Spec.Containers[0]
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.Name + "-wakeup",
+			Namespace: c.Namespace,
+			Labels:    WakeupLabels(c.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       service.Name,
+					UID:        service.UID,
+				},
+			},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: template.Spec.ServiceAccountName,
+			NodeSelector:       template.Spec.NodeSelector,
+			Tolerations:        template.Spec.Tolerations,
+			ImagePullSecrets:   template.Spec.ImagePullSecrets,
+			PriorityClassName:  template.Spec.PriorityClassName,
+			SecurityContext:    template.Spec.SecurityContext,
+			Containers: []corev1.Container{
+				{
+					Name:            "wakeup",
+					Image:           syncer.Image,
+					ImagePullPolicy: syncer.ImagePullPolicy,
+					Command:         []string{"/vcluster", "wakeup"},
+					Args: []string{
+						"--name=" + c.Name,
+						"--service-name=" + c.ServiceName,
+						"--port=" + strconv.Itoa(c.Port),
+					},
+					Ports: []corev1.ContainerPort{
+						{
+							Name:          "https",
+							ContainerPort: int32(c.Port),
+							Protocol:      corev1.ProtocolTCP,
+						},
+					},
+					SecurityContext: syncer.SecurityContext,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("10m"),
+							corev1.ResourceMemory: resource.MustParse("32Mi"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (c *Controller) deleteWakeupPods(ctx context.Context) error {
+	pods, err := c.Client.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(WakeupLabels(c.Name)).String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "list wake-up pods")
+	}
+
+	for _, pod := range pods.Items {
+		err = c.Client.CoreV1().Pods(c.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "delete wake-up pod %s", pod.Name)
+		}
+	}
+
+	return nil
+}
+
+func (c *Controller) getNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+
+	return time.Now()
+}
+
+// RestoreService points the vcluster service back to the virtual cluster if it was pointed to the wake-up pod
+func RestoreService(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	return updateServices(ctx, client, namespace, name, func(service *corev1.Service) error {
+		if service.Annotations[constants.SleepSelectorAnnotation] == "" {
+			return nil
+		}
+
+		selector := map[string]string{}
+		err := json.Unmarshal([]byte(service.Annotations[constants.SleepSelectorAnnotation]), &selector)
+		if err != nil {
+			return errors.Wrap(err, "parse original selector")
+		}
+
+		delete(service.Annotations, constants.SleepSelectorAnnotation)
+		service.Spec.Selector = selector
+		return nil
+	})
+}
+
+// updateServices updates the vcluster service and the load balancer service the chart creates next to it
+func updateServices(ctx context.Context, client kubernetes.Interface, namespace, name string, update func(service *corev1.Service) error) error {
+	for _, serviceName := range []string{name, name + "-lb"} {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			service, err := client.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			original := service.DeepCopy()
+			err = update(service)
+			if err != nil {
+				return err
+			} else if equalServices(original, service) {
+				return nil
+			}
+
+			_, err = client.CoreV1().Services(namespace).Update(ctx, service, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "update service %s/%s", namespace, serviceName)
+		}
+	}
+
+	return nil
+}
+
+func equalServices(a, b *corev1.Service) bool {
+	return labels.Equals(a.Spec.Selector, b.Spec.Selector) && labels.Equals(a.Annotations, b.Annotations)
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package sleep
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/server/activity"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		from     string
+		next     string
+	}{
+		{schedule: "0 20 * * 1-5", from: "2026-10-16T19:59:00Z", next: "2026-10-16T20:00:00Z"},
+		{schedule: "0 20 * * 1-5", from: "2026-10-16T20:00:00Z", next: "2026-10-19T20:00:00Z"},
+		{schedule: "*/15 * * * *", from: "2026-10-16T10:07:30Z", next: "2026-10-16T10:15:00Z"},
+		{schedule: "0 0 1,15 * 0", from: "2026-10-16T00:00:00Z", next: "2026-10-18T00:00:00Z"},
+		{schedule: "30 18 * * 7", from: "2026-10-16T00:00:00Z", next: "2026-10-18T18:30:00Z"},
+		{schedule: "TZ=Europe/Berlin 0 20 * * *", from: "2026-10-16T12:00:00Z", next: "2026-10-16T18:00:00Z"},
+	}
+	for _, test := range tests {
+		schedule, err := ParseSchedule(test.schedule)
+		assert.NilError(t, err, test.schedule)
+		from, _ := time.Parse(time.RFC3339, test.from)
+		next, _ := time.Parse(time.RFC3339, test.next)
+		assert.Assert(t, schedule.Next(from).Equal(next), "%s: expected %s, got %s", test.schedule, next, schedule.Next(from))
+	}
+
+	for _, invalid := range []string{"* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "TZ=Invalid/Zone * * * * *"} {
+		_, err := ParseSchedule(invalid)
+		assert.ErrorContains(t, err, "invalid schedule", invalid)
+	}
+}
+
+func TestSleepReason(t *testing.T) {
+	now := time.Date(2026, 10, 16, 20, 0, 30, 0, time.UTC)
+	schedule, err := ParseSchedule("0 20 * * *")
+	assert.NilError(t, err)
+
+	controller := &Controller{Schedule: schedule}
+	controller.lastCheck = now.Add(-time.Minute)
+	assert.Equal(t, controller.sleepReason(now), ReasonSchedule)
+
+	// the schedule fired before the last check, e.g. before the virtual cluster woke up
+	controller.lastCheck = now
+	assert.Equal(t, controller.sleepReason(now.Add(time.Hour)), "")
+
+	tracker := activity.NewTracker()
+	_, idle := tracker.IdleSince()
+	assert.Assert(t, idle)
+	controller = &Controller{SleepAfter: time.Hour, Tracker: tracker}
+	assert.Equal(t, controller.sleepReason(time.Now()), "")
+	assert.Equal(t, controller.sleepReason(time.Now().Add(time.Hour*2)), ReasonInactivity)
+
+	// requests in flight keep the virtual cluster active
+	done := tracker.Start()
+	assert.Equal(t, controller.sleepReason(time.Now().Add(time.Hour*2)), "")
+	done()
+	assert.Equal(t, controller.sleepReason(time.Now().Add(time.Minute)), "")
+	assert.Equal(t, controller.sleepReason(time.Now().Add(time.Hour*2)), ReasonInactivity)
+}
+
+func TestSleepAndWakeUp(t *testing.T) {
+	replicas := int32(1)
+	kubeClient := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{"app": "vcluster", "release": "test"}},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						ServiceAccountName: "vc-test",
+						Containers:         []corev1.Container{{Name: "vcluster"}, {Name: "syncer", Image: "ghcr.io/loft-sh/vcluster:0.15.0"}},
+					},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "vcluster", "release": "test"}},
+		},
+	)
+	kubeClient.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		pod := action.(clienttesting.CreateAction).GetObject().(*corev1.Pod)
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		return false, nil, nil
+	})
+
+	ctx := context.Background()
+	controller := &Controller{Client: kubeClient, Name: "test", Namespace: "test", ServiceName: "test", Port: 8443, Log: log.Discard}
+	assert.NilError(t, controller.Sleep(ctx, ReasonInactivity))
+
+	pod, err := kubeClient.CoreV1().Pods("test").Get(ctx, "test-wakeup", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, pod.Spec.ServiceAccountName, "vc-test")
+	assert.Equal(t, pod.Spec.Containers[0].Image, "ghcr.io/loft-sh/vcluster:0.15.0")
+	assert.Equal(t, pod.OwnerReferences[0].Name, "test")
+
+	service, err := kubeClient.CoreV1().Services("test").Get(ctx, "test", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, service.Spec.Selector, WakeupLabels("test"))
+
+	statefulSet, err := kubeClient.AppsV1().StatefulSets("test").Get(ctx, "test", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *statefulSet.Spec.Replicas, int32(0))
+	assert.Equal(t, statefulSet.Annotations[constants.PausedAnnotation], "true")
+	assert.Equal(t, statefulSet.Annotations[constants.SleepReasonAnnotation], ReasonInactivity)
+
+	// the syncer the responder forwards the connections to
+	syncer, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer syncer.Close()
+	go func() {
+		conn, err := syncer.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("awake"))
+	}()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	responder := &Responder{
+		Client:      kubeClient,
+		Name:        "test",
+		Namespace:   "test",
+		ServiceName: "test",
+		Log:         log.Discard,
+		resolveTarget: func(ctx context.Context) (string, error) {
+			return syncer.Addr().String(), nil
+		},
+	}
+	responderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_ = responder.Serve(responderCtx, listener)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close()
+	assert.NilError(t, conn.SetDeadline(time.Now().Add(time.Second*10)))
+	out, err := io.ReadAll(conn)
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), "awake")
+
+	statefulSet, err = kubeClient.AppsV1().StatefulSets("test").Get(ctx, "test", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *statefulSet.Spec.Replicas, int32(1))
+	assert.Equal(t, statefulSet.Annotations[constants.SleepReasonAnnotation], "")
+
+	service, err = kubeClient.CoreV1().Services("test").Get(ctx, "test", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, service.Spec.Selector, map[string]string{"app": "vcluster", "release": "test"})
+	assert.Equal(t, service.Annotations[constants.SleepSelectorAnnotation], "")
+}
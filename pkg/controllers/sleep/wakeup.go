@@ -0,0 +1,216 @@
+package sleep
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/utils/pkg/log"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Responder serves the vcluster service while the virtual cluster sleeps. On the first connection it resumes
+// the virtual cluster, holds the connections until the syncer is ready and then forwards them to the syncer.
+// The connections are forwarded on tcp level, so clients don't notice that the virtual cluster was sleeping
+// apart from the longer response time.
+type Responder struct {
+	Client kubernetes.Interface
+
+	// Name is the name of the virtual cluster
+	Name string
+	// Namespace is the namespace the virtual cluster runs in
+	Namespace string
+	// ServiceName is the name of the vcluster service
+	ServiceName string
+	// Port is the port the responder listens on and the syncer serves the virtual cluster api on
+	Port int
+
+	// Timeout is how long the virtual cluster has to wake up
+	Timeout time.Duration
+
+	Log log.Logger
+
+	once   sync.Once
+	waking chan struct{}
+	awake  chan struct{}
+	errors chan error
+	target string
+
+	// resolveTarget returns the address of the ready syncer, overridden in tests
+	resolveTarget func(ctx context.Context) (string, error)
+}
+
+// Serve accepts connections on the given listener until the context is done or waking up the
+// virtual cluster failed
+func (r *Responder) Serve(ctx context.Context, listener net.Listener) error {
+	r.waking = make(chan struct{})
+	r.awake = make(chan struct{})
+	r.errors = make(chan error, 2)
+	go r.deleteWorkloads(ctx)
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	connections := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() == nil {
+					r.errors <- err
+				}
+				return
+			}
+
+			connections <- conn
+		}
+	}()
+
+	r.Log.Infof("Waiting for connections to wake up vcluster %s", r.Name)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-r.errors:
+			return err
+		case conn := <-connections:
+			r.once.Do(func() {
+				close(r.waking)
+				go r.wakeUp(ctx)
+			})
+			go r.handle(ctx, conn)
+		}
+	}
+}
+
+func (r *Responder) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-r.awake:
+	}
+
+	target, err := net.DialTimeout("tcp", r.target, time.Second*10)
+	if err != nil {
+		r.Log.Errorf("error connecting to vcluster: %v", err)
+		return
+	}
+	defer target.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(target, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, target)
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-done:
+	}
+}
+
+// deleteWorkloads deletes the workloads of the virtual cluster in the host cluster as vcluster pause does,
+// as soon as the syncer is terminated. The syncer recreates them after the virtual cluster woke up.
+func (r *Responder) deleteWorkloads(ctx context.Context) {
+	selector := labels.SelectorFromSet(map[string]string{"app": "vcluster", "release": r.Name}).String()
+	err := wait.PollUntilContextCancel(ctx, time.Second*5, true, func(ctx context.Context) (bool, error) {
+		select {
+		case <-r.waking:
+			return false, errors.New("vcluster is waking up")
+		default:
+		}
+
+		pods, err := r.Client.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			r.Log.Errorf("error listing vcluster pods: %v", err)
+			return false, nil
+		}
+
+		return len(pods.Items) == 0, nil
+	})
+	if err != nil {
+		return
+	}
+
+	err = lifecycle.DeleteVClusterWorkloads(ctx, r.Client, "vcluster.loft.sh/managed-by="+r.Name, r.Namespace, r.Log)
+	if err != nil {
+		r.Log.Errorf("error deleting vcluster workloads: %v", err)
+	}
+
+	err = lifecycle.DeleteMultiNamespaceVclusterWorkloads(ctx, r.Client, r.Name, r.Namespace, r.Log)
+	if err != nil {
+		r.Log.Errorf("error deleting vcluster multinamespace workloads: %v", err)
+	}
+}
+
+// wakeUp resumes the virtual cluster, waits until the syncer is ready and points the vcluster
+// service back to it
+func (r *Responder) wakeUp(ctx context.Context) {
+	r.Log.Infof("Wake up vcluster %s", r.Name)
+	err := lifecycle.ResumeVCluster(ctx, r.Client, r.Name, r.Namespace, r.Log)
+	if err != nil {
+		r.errors <- errors.Wrap(err, "resume vcluster")
+		return
+	}
+
+	resolveTarget := r.resolveTarget
+	if resolveTarget == nil {
+		resolveTarget = r.waitForSyncer
+	}
+	r.target, err = resolveTarget(ctx)
+	if err != nil {
+		r.errors <- err
+		return
+	}
+
+	err = RestoreService(ctx, r.Client, r.Namespace, r.ServiceName)
+	if err != nil {
+		r.errors <- errors.Wrap(err, "restore vcluster service")
+		return
+	}
+
+	r.Log.Donef("Successfully woke up vcluster %s", r.Name)
+	close(r.awake)
+}
+
+// waitForSyncer returns the address of the first ready syncer pod
+func (r *Responder) waitForSyncer(ctx context.Context) (string, error) {
+	target := ""
+	selector := labels.SelectorFromSet(map[string]string{"app": "vcluster", "release": r.Name}).String()
+	err := wait.PollUntilContextTimeout(ctx, time.Second*2, r.Timeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := r.Client.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+
+		for i := range pods.Items {
+			if pods.Items[i].DeletionTimestamp == nil && pods.Items[i].Status.PodIP != "" && isPodReady(&pods.Items[i]) {
+				target = net.JoinHostPort(pods.Items[i].Status.PodIP, strconv.Itoa(r.Port))
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("wait for vcluster %s to become ready: %w", r.Name, err)
+	}
+
+	return target, nil
+}
@@ -32,6 +32,9 @@ type RegisterContext struct {
 
 	VirtualManager  ctrl.Manager
 	PhysicalManager ctrl.Manager
+
+	// NamespaceFilter is only set for the syncers of a shard
+	NamespaceFilter controllercontext.NamespaceFilter
 }
 
 func ConvertContext(registerContext *RegisterContext, logName string) *SyncContext {
@@ -0,0 +1,47 @@
+package syncer
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/dryrun"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRun redirects the host writes of a syncer to server side dry run requests
+type dryRun struct {
+	syncer        string
+	writer        client.Client
+	eventRecorder record.EventRecorder
+}
+
+// newDryRun returns nil if the syncer with the given name should not run in dry run mode
+func newDryRun(ctx *synccontext.RegisterContext, name string) (*dryRun, error) {
+	if ctx.Options == nil || !dryrun.Enabled(ctx.Options.DryRunSyncers, name) {
+		return nil, nil
+	}
+
+	// the dry run writes need an uncached client, as the blocking cache client would wait
+	// for objects that never show up in the cache
+	writer, err := client.New(ctx.PhysicalManager.GetConfig(), client.Options{
+		Scheme: ctx.PhysicalManager.GetScheme(),
+		Mapper: ctx.PhysicalManager.GetRESTMapper(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dryRun{
+		syncer:        name,
+		writer:        writer,
+		eventRecorder: ctx.VirtualManager.GetEventRecorderFor("vcluster-dry-run"),
+	}, nil
+}
+
+// wrap returns a client that only records host writes for the given virtual object
+func (d *dryRun) wrap(c client.Client, vObj client.Object) client.Client {
+	if d == nil || c == nil {
+		return c
+	}
+
+	return dryrun.NewClient(c, d.writer, dryrun.Default, d.syncer, vObj, d.eventRecorder)
+}
@@ -0,0 +1,136 @@
+package dryrun
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewClient returns a client that reads through the given client, but sends all writes as server side
+// dry run requests through writer and records them. writer should not be a cached client, as the
+// dry run objects never show up in the cache. If vObj is set, an event is emitted on it for every write.
+func NewClient(delegate, writer client.Client, recorder *Recorder, syncer string, vObj client.Object, eventRecorder record.EventRecorder) client.Client {
+	return &dryRunClient{
+		Client: delegate,
+		writer: &recordingWriter{
+			writer:        writer,
+			recorder:      recorder,
+			syncer:        syncer,
+			vObj:          vObj,
+			eventRecorder: eventRecorder,
+		},
+	}
+}
+
+type dryRunClient struct {
+	client.Client
+
+	writer *recordingWriter
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.writer.record(OperationCreate, obj, c.writer.writer.Create(ctx, obj, append(opts, client.DryRunAll)...))
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.writer.record(OperationUpdate, obj, c.writer.writer.Update(ctx, obj, append(opts, client.DryRunAll)...))
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.writer.record(OperationPatch, obj, c.writer.writer.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...))
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.writer.record(OperationDelete, obj, c.writer.writer.Delete(ctx, obj, append(opts, client.DryRunAll)...))
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	return c.writer.record(OperationDeleteAllOf, obj, c.writer.writer.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...))
+}
+
+func (c *dryRunClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *dryRunClient) SubResource(subResource string) client.SubResourceClient {
+	return &dryRunSubResourceClient{
+		SubResourceClient: c.Client.SubResource(subResource),
+		subResourceWriter: c.writer.writer.SubResource(subResource),
+		writer:            c.writer,
+	}
+}
+
+type dryRunSubResourceClient struct {
+	client.SubResourceClient
+
+	subResourceWriter client.SubResourceWriter
+	writer            *recordingWriter
+}
+
+func (c *dryRunSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return c.writer.record(OperationCreate, obj, c.subResourceWriter.Create(ctx, obj, subResource, append(opts, client.DryRunAll)...))
+}
+
+func (c *dryRunSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return c.writer.record(OperationUpdate, obj, c.subResourceWriter.Update(ctx, obj, append(opts, client.DryRunAll)...))
+}
+
+func (c *dryRunSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return c.writer.record(OperationPatch, obj, c.subResourceWriter.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...))
+}
+
+type recordingWriter struct {
+	writer        client.Client
+	recorder      *Recorder
+	syncer        string
+	vObj          client.Object
+	eventRecorder record.EventRecorder
+}
+
+func (w *recordingWriter) record(operation Operation, obj client.Object, err error) error {
+	entry := Entry{
+		Syncer:    w.syncer,
+		Operation: operation,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+	gvk, gvkErr := apiutil.GVKForObject(obj, w.writer.Scheme())
+	if gvkErr == nil {
+		entry.APIVersion, entry.Kind = gvk.ToAPIVersionAndKind()
+	}
+	if w.vObj != nil {
+		entry.VirtualNamespace = w.vObj.GetNamespace()
+		entry.VirtualName = w.vObj.GetName()
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	w.recorder.Record(entry)
+
+	if w.vObj != nil && w.eventRecorder != nil {
+		name := entry.Name
+		if entry.Namespace != "" {
+			name = entry.Namespace + "/" + name
+		}
+
+		if err != nil {
+			w.eventRecorder.Eventf(w.vObj, corev1.EventTypeWarning, "DryRun"+string(operation), "Dry run: %s host %s %s failed: %v", verb(operation), entry.Kind, name, err)
+		} else {
+			w.eventRecorder.Eventf(w.vObj, corev1.EventTypeNormal, "DryRun"+string(operation), "Dry run: would %s host %s %s", verb(operation), entry.Kind, name)
+		}
+	}
+
+	return err
+}
+
+func verb(operation Operation) string {
+	if operation == OperationDeleteAllOf {
+		return "delete all of"
+	}
+
+	return strings.ToLower(string(operation))
+}
@@ -0,0 +1,57 @@
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	pExisting := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "test"}}
+	pClient := testingutil.NewFakeClient(testingutil.NewScheme(), pExisting)
+	vObj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+	recorder := NewRecorder()
+	eventRecorder := record.NewFakeRecorder(10)
+	dryRunClient := NewClient(pClient, pClient, recorder, "configmap", vObj, eventRecorder)
+
+	// create should not be applied
+	for i := 0; i < 2; i++ {
+		err := dryRunClient.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config-x-default-x-suffix", Namespace: "test"}})
+		assert.NilError(t, err)
+	}
+	err := pClient.Get(ctx, types.NamespacedName{Namespace: "test", Name: "config-x-default-x-suffix"}, &corev1.ConfigMap{})
+	assert.Assert(t, kerrors.IsNotFound(err))
+
+	// delete should not be applied
+	err = dryRunClient.Delete(ctx, pExisting.DeepCopy())
+	assert.NilError(t, err)
+	err = pClient.Get(ctx, types.NamespacedName{Namespace: "test", Name: "existing"}, &corev1.ConfigMap{})
+	assert.NilError(t, err)
+
+	report := recorder.Report()
+	assert.Equal(t, len(report.Items), 2)
+	assert.Equal(t, report.Items[0].Operation, OperationCreate)
+	assert.Equal(t, report.Items[0].Kind, "ConfigMap")
+	assert.Equal(t, report.Items[0].Name, "config-x-default-x-suffix")
+	assert.Equal(t, report.Items[0].VirtualName, "config")
+	assert.Equal(t, report.Items[0].Count, 2)
+	assert.Equal(t, report.Items[1].Operation, OperationDelete)
+	assert.Equal(t, report.Items[1].Count, 1)
+
+	assert.Equal(t, <-eventRecorder.Events, "Normal DryRunCreate Dry run: would create host ConfigMap test/config-x-default-x-suffix")
+}
+
+func TestEnabled(t *testing.T) {
+	assert.Assert(t, Enabled([]string{"ingress", "configmap"}, "configmap"))
+	assert.Assert(t, Enabled([]string{AllSyncers}, "configmap"))
+	assert.Assert(t, !Enabled([]string{"ingress"}, "configmap"))
+	assert.Assert(t, !Enabled(nil, "configmap"))
+}
@@ -0,0 +1,119 @@
+package dryrun
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AllSyncers enables the dry run mode for all syncers
+const AllSyncers = "*"
+
+// Default is the recorder all dry run clients report to
+var Default = NewRecorder()
+
+type Operation string
+
+const (
+	OperationCreate      Operation = "Create"
+	OperationUpdate      Operation = "Update"
+	OperationPatch       Operation = "Patch"
+	OperationDelete      Operation = "Delete"
+	OperationDeleteAllOf Operation = "DeleteAllOf"
+)
+
+// Report is the aggregated list of host changes the dry run syncers would have applied
+type Report struct {
+	Items []*Entry `json:"items"`
+}
+
+// Entry is a single host change a dry run syncer would have applied
+type Entry struct {
+	Syncer    string    `json:"syncer"`
+	Operation Operation `json:"operation"`
+
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+
+	VirtualNamespace string `json:"virtualNamespace,omitempty"`
+	VirtualName      string `json:"virtualName,omitempty"`
+
+	// Error is the error the host cluster returned for the dry run request
+	Error string `json:"error,omitempty"`
+
+	// Count is how often the change was attempted
+	Count          int         `json:"count"`
+	FirstTimestamp metav1.Time `json:"firstTimestamp"`
+	LastTimestamp  metav1.Time `json:"lastTimestamp"`
+}
+
+func (e *Entry) key() string {
+	return strings.Join([]string{e.Syncer, string(e.Operation), e.APIVersion, e.Kind, e.Namespace, e.Name}, "/")
+}
+
+// Recorder aggregates the host changes of the dry run syncers
+type Recorder struct {
+	m       sync.Mutex
+	entries map[string]*Entry
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		entries: map[string]*Entry{},
+	}
+}
+
+// Record adds a host change to the report. Repeated changes of the same object are aggregated.
+func (r *Recorder) Record(entry Entry) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	now := metav1.NewTime(time.Now())
+	existing, ok := r.entries[entry.key()]
+	if !ok {
+		entry.Count = 1
+		entry.FirstTimestamp = now
+		entry.LastTimestamp = now
+		r.entries[entry.key()] = &entry
+		return
+	}
+
+	existing.Count++
+	existing.LastTimestamp = now
+	existing.Error = entry.Error
+	existing.VirtualNamespace = entry.VirtualNamespace
+	existing.VirtualName = entry.VirtualName
+}
+
+// Report returns a copy of all recorded host changes sorted by syncer and object
+func (r *Recorder) Report() *Report {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	report := &Report{Items: []*Entry{}}
+	for _, entry := range r.entries {
+		e := *entry
+		report.Items = append(report.Items, &e)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		return report.Items[i].key() < report.Items[j].key()
+	})
+
+	return report
+}
+
+// Enabled returns true if the syncer with the given name should run in dry run mode
+func Enabled(dryRunSyncers []string, name string) bool {
+	for _, s := range dryRunSyncers {
+		if s == AllSyncers || s == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -14,6 +14,11 @@ import (
 )
 
 func RegisterFakeSyncer(ctx *synccontext.RegisterContext, syncer FakeSyncer) error {
+	dryRun, err := newDryRun(ctx, syncer.Name())
+	if err != nil {
+		return err
+	}
+
 	controller := &fakeSyncer{
 		syncer:         syncer,
 		log:            loghelper.New(syncer.Name()),
@@ -23,6 +28,7 @@ func RegisterFakeSyncer(ctx *synccontext.RegisterContext, syncer FakeSyncer) err
 		currentNamespaceClient: ctx.CurrentNamespaceClient,
 
 		virtualClient: ctx.VirtualManager.GetClient(),
+		dryRun:        dryRun,
 	}
 
 	return controller.Register(ctx)
@@ -38,6 +44,7 @@ type fakeSyncer struct {
 	currentNamespaceClient client.Client
 
 	virtualClient client.Client
+	dryRun        *dryRun
 }
 
 func (r *fakeSyncer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -45,9 +52,9 @@ func (r *fakeSyncer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	syncContext := &synccontext.SyncContext{
 		Context:                ctx,
 		Log:                    log,
-		PhysicalClient:         r.physicalClient,
+		PhysicalClient:         r.dryRun.wrap(r.physicalClient, nil),
 		CurrentNamespace:       r.currentNamespace,
-		CurrentNamespaceClient: r.currentNamespaceClient,
+		CurrentNamespaceClient: r.dryRun.wrap(r.currentNamespaceClient, nil),
 		VirtualClient:          r.virtualClient,
 	}
 
@@ -77,6 +84,12 @@ func (r *fakeSyncer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// in dry run mode, changes are reported as events on the virtual object
+	if r.dryRun != nil {
+		syncContext.PhysicalClient = r.dryRun.wrap(r.physicalClient, vObj)
+		syncContext.CurrentNamespaceClient = r.dryRun.wrap(r.currentNamespaceClient, vObj)
+	}
+
 	// update object
 	return r.syncer.FakeSync(syncContext, vObj)
 }
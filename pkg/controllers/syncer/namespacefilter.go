@@ -0,0 +1,30 @@
+package syncer
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// owns returns true if the objects of the virtual namespace are synced by this replica
+func (r *syncerController) owns(namespace string) bool {
+	return r.namespaceFilter == nil || namespace == "" || r.namespaceFilter.Owns(namespace)
+}
+
+// namespacePredicate ignores the events of virtual objects in namespaces another replica owns
+func (r *syncerController) namespacePredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return r.owns(obj.GetNamespace())
+	})
+}
+
+// watchOwnedNamespaces requeues all objects of a virtual namespace as soon as this replica starts to own it
+func (r *syncerController) watchOwnedNamespaces(controller *builder.Builder) *builder.Builder {
+	if r.namespaceFilter == nil {
+		return controller
+	}
+
+	return controller.WatchesRawSource(&source.Channel{Source: r.namespaceFilter.Watch()}, handler.EnqueueRequestsFromMapFunc(r.enqueueNamespace))
+}
@@ -0,0 +1,137 @@
+package syncer
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// IsPaused returns true if the object has the pause annotation set
+func IsPaused(obj client.Object) bool {
+	return obj != nil && obj.GetAnnotations() != nil && obj.GetAnnotations()[constants.PauseSyncAnnotation] == "true"
+}
+
+// isPaused checks if syncing is paused for the virtual object or its virtual namespace
+func (r *syncerController) isPaused(ctx context.Context, req reconcile.Request, vObj client.Object) (bool, error) {
+	if IsPaused(vObj) {
+		return true, nil
+	} else if req.Namespace == "" {
+		return false, nil
+	}
+
+	vNamespace := &corev1.Namespace{}
+	err := r.virtualClient.Get(ctx, types.NamespacedName{Name: req.Namespace}, vNamespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return IsPaused(vNamespace), nil
+}
+
+// watchPausedNamespaces requeues all objects of a virtual namespace when its pause annotation changes,
+// so the drift that accumulated while the namespace was paused is reconciled in both directions
+func (r *syncerController) watchPausedNamespaces(controller *builder.Builder) *builder.Builder {
+	return controller.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.enqueueNamespace), builder.WithPredicates(predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return IsPaused(e.ObjectOld) != IsPaused(e.ObjectNew)
+		},
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}))
+}
+
+func (r *syncerController) enqueueNamespace(ctx context.Context, vNamespace client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	vList, err := r.newList(r.virtualClient)
+	if err != nil {
+		klog.Errorf("error creating list for %s syncer: %v", r.syncer.Name(), err)
+		return nil
+	}
+	err = r.virtualClient.List(ctx, vList, client.InNamespace(vNamespace.GetName()))
+	if err != nil {
+		klog.Errorf("error listing virtual objects in namespace %s: %v", vNamespace.GetName(), err)
+		return nil
+	}
+	forEachObject(vList, func(obj client.Object) {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}})
+	})
+
+	// host objects whose virtual object was deleted while the namespace was paused need to be requeued as well
+	pList, err := r.newList(r.physicalClient)
+	if err != nil {
+		klog.Errorf("error creating list for %s syncer: %v", r.syncer.Name(), err)
+		return requests
+	}
+	err = r.physicalClient.List(ctx, pList, client.InNamespace(translate.Default.PhysicalNamespace(vNamespace.GetName())))
+	if err != nil {
+		klog.Errorf("error listing host objects of namespace %s: %v", vNamespace.GetName(), err)
+		return requests
+	}
+	forEachObject(pList, func(obj client.Object) {
+		managed, err := r.syncer.IsManaged(ctx, obj)
+		if err != nil || !managed {
+			return
+		}
+
+		name := r.syncer.PhysicalToVirtual(ctx, obj)
+		if name.Namespace == vNamespace.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: name})
+		}
+	})
+
+	return requests
+}
+
+// newList returns an empty list for the resource of the syncer
+func (r *syncerController) newList(c client.Client) (client.ObjectList, error) {
+	obj := r.syncer.Resource()
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(listGVK)
+		return list, nil
+	}
+
+	list, err := c.Scheme().New(listGVK)
+	if err != nil {
+		return nil, err
+	}
+
+	return list.(client.ObjectList), nil
+}
+
+func forEachObject(list client.ObjectList, fn func(obj client.Object)) {
+	_ = meta.EachListItem(list, func(o runtime.Object) error {
+		obj, ok := o.(client.Object)
+		if ok {
+			fn(obj)
+		}
+
+		return nil
+	})
+}
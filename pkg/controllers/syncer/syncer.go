@@ -2,21 +2,28 @@ package syncer
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	controllercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
 	"github.com/loft-sh/vcluster/pkg/telemetry"
 	telemetrytypes "github.com/loft-sh/vcluster/pkg/telemetry/types"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncstatus"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	controller2 "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -30,6 +37,11 @@ func RegisterSyncer(ctx *synccontext.RegisterContext, syncer Syncer) error {
 		options = optionsProvider.WithOptions()
 	}
 
+	dryRun, err := newDryRun(ctx, syncer.Name())
+	if err != nil {
+		return err
+	}
+
 	controller := &syncerController{
 		syncer:         syncer,
 		log:            loghelper.New(syncer.Name()),
@@ -39,7 +51,13 @@ func RegisterSyncer(ctx *synccontext.RegisterContext, syncer Syncer) error {
 		currentNamespaceClient: ctx.CurrentNamespaceClient,
 
 		virtualClient: ctx.VirtualManager.GetClient(),
+		eventRecorder: ctx.VirtualManager.GetEventRecorderFor(syncer.Name() + "-syncer"),
 		options:       options,
+		dryRun:        dryRun,
+
+		disableSyncStatus: ctx.Options != nil && ctx.Options.DisableSyncStatus,
+
+		namespaceFilter: ctx.NamespaceFilter,
 	}
 
 	return controller.Register(ctx)
@@ -56,7 +74,13 @@ type syncerController struct {
 	currentNamespaceClient client.Client
 
 	virtualClient client.Client
+	eventRecorder record.EventRecorder
 	options       *Options
+	dryRun        *dryRun
+
+	disableSyncStatus bool
+
+	namespaceFilter controllercontext.NamespaceFilter
 }
 
 func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -65,12 +89,17 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	syncContext := &synccontext.SyncContext{
 		Context:                ctx,
 		Log:                    log,
-		PhysicalClient:         r.physicalClient,
+		PhysicalClient:         r.dryRun.wrap(r.physicalClient, nil),
 		CurrentNamespace:       r.currentNamespace,
-		CurrentNamespaceClient: r.currentNamespaceClient,
+		CurrentNamespaceClient: r.dryRun.wrap(r.currentNamespaceClient, nil),
 		VirtualClient:          r.virtualClient,
 	}
 
+	// another replica syncs the namespace
+	if !r.owns(req.Namespace) {
+		return ctrl.Result{}, nil
+	}
+
 	// check if we should skip reconcile
 	lifecycle, ok := r.syncer.(Starter)
 	if ok {
@@ -90,6 +119,7 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 
 		vObj = nil
+		r.removeSyncStatus(req)
 	}
 
 	// check if we should skip resource
@@ -98,9 +128,29 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, nil
 	}
 
+	// check if syncing is paused for the object or its namespace, in which case we neither change
+	// the host nor the virtual object until the pause annotation is removed
+	paused, err := r.isPaused(ctx, req, vObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if paused {
+		if vObj != nil {
+			r.setSyncStatus(syncContext, vObj, syncstatus.NewPaused(r.syncer.Name(), r.syncer.VirtualToPhysical(ctx, req.NamespacedName, vObj)))
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// in dry run mode, changes are reported as events on the virtual object
+	if r.dryRun != nil && vObj != nil {
+		syncContext.PhysicalClient = r.dryRun.wrap(r.physicalClient, vObj)
+		syncContext.CurrentNamespaceClient = r.dryRun.wrap(r.currentNamespaceClient, vObj)
+	}
+
 	// translate to physical name
+	pName := r.syncer.VirtualToPhysical(ctx, req.NamespacedName, vObj)
 	pObj := r.syncer.Resource()
-	err = r.physicalClient.Get(ctx, r.syncer.VirtualToPhysical(ctx, req.NamespacedName, vObj), pObj)
+	err = r.physicalClient.Get(ctx, pName, pObj)
 	if err != nil {
 		if !kerrors.IsNotFound(err) {
 			return ctrl.Result{}, err
@@ -117,8 +167,20 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	// check what function we should call
 	if vObj != nil && pObj == nil {
-		return captureSyncTelemetry(r.syncer.SyncDown(syncContext, vObj))(vObj.GetObjectKind().GroupVersionKind(), reconcileStart)
+		result, err := r.syncer.SyncDown(syncContext, vObj)
+		r.updateSyncStatus(syncContext, vObj, pName, err)
+		return captureSyncTelemetry(result, err)(vObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 	} else if vObj != nil && pObj != nil {
+		// make sure the physical object belongs to the virtual object, which might not be the case
+		// if a name template translates different virtual objects to the same physical name
+		if isNameConflict(pObj, vObj) {
+			err := fmt.Errorf("host object %s/%s is already owned by a different virtual object, please adjust the name template", pObj.GetNamespace(), pObj.GetName())
+			log.Infof("%v", err)
+			r.eventRecorder.Eventf(vObj, "Warning", "NameConflict", "Error syncing to physical cluster: %v", err)
+			r.updateSyncStatus(syncContext, vObj, pName, err)
+			return ctrl.Result{}, nil
+		}
+
 		// make sure the object uid matches
 		pAnnotations := pObj.GetAnnotations()
 		if !r.options.DisableUIDDeletion && pAnnotations != nil && pAnnotations[translate.UIDAnnotation] != "" && pAnnotations[translate.UIDAnnotation] != string(vObj.GetUID()) {
@@ -131,7 +193,9 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			return captureSyncTelemetry(DeleteObject(syncContext, pObj, "virtual object uid is different"))(pObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 		}
 
-		return captureSyncTelemetry(r.syncer.Sync(syncContext, pObj, vObj))(vObj.GetObjectKind().GroupVersionKind(), reconcileStart)
+		result, err := r.syncer.Sync(syncContext, pObj, vObj)
+		r.updateSyncStatus(syncContext, vObj, pName, err)
+		return captureSyncTelemetry(result, err)(vObj.GetObjectKind().GroupVersionKind(), reconcileStart)
 	} else if vObj == nil && pObj != nil {
 		if pObj.GetAnnotations() != nil {
 			if shouldSkip, ok := pObj.GetAnnotations()[translate.SkipBacksyncInMultiNamespaceMode]; ok && shouldSkip == "true" {
@@ -152,6 +216,88 @@ func (r *syncerController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, nil
 }
 
+// updateSyncStatus records the result of a sync on the namespaced virtual object, so tenants can see if
+// the host object could not be created or updated
+func (r *syncerController) updateSyncStatus(ctx *synccontext.SyncContext, vObj client.Object, pName types.NamespacedName, syncErr error) {
+	if r.disableSyncStatus || r.dryRun != nil || vObj.GetNamespace() == "" || kerrors.IsConflict(syncErr) {
+		return
+	}
+
+	var status *syncstatus.SyncStatus
+	if syncErr != nil {
+		status = syncstatus.NewFailed(r.syncer.Name(), pName, syncErr)
+	} else {
+		// some syncers only sync objects under certain conditions, e.g. secrets that are used by pods,
+		// so we only mark the object as synced if the host object exists
+		err := r.physicalClient.Get(ctx.Context, pName, r.syncer.Resource())
+		if err != nil && !kerrors.IsNotFound(err) {
+			ctx.Log.Infof("error retrieving host object for sync status: %v", err)
+			return
+		} else if err == nil {
+			status = syncstatus.NewSynced(r.syncer.Name(), pName, vObj)
+		}
+	}
+
+	r.setSyncStatus(ctx, vObj, status)
+}
+
+// setSyncStatus writes the status to the namespaced virtual object and reports it to the registry. If status
+// is nil, the sync status is removed from the object.
+func (r *syncerController) setSyncStatus(ctx *synccontext.SyncContext, vObj client.Object, status *syncstatus.SyncStatus) {
+	if r.disableSyncStatus || r.dryRun != nil || vObj.GetNamespace() == "" {
+		return
+	}
+
+	err := syncstatus.Update(ctx.Context, r.virtualClient, vObj, status)
+	if err != nil {
+		ctx.Log.Infof("error updating sync status: %v", err)
+	}
+
+	entry := syncstatus.Entry{Namespace: vObj.GetNamespace(), Name: vObj.GetName(), Status: syncstatus.SyncStatus{Syncer: r.syncer.Name()}}
+	gvk, err := apiutil.GVKForObject(vObj, r.virtualClient.Scheme())
+	if err == nil {
+		entry.APIVersion, entry.Kind = gvk.ToAPIVersionAndKind()
+	}
+	if status != nil {
+		entry.Status = *status
+	}
+	syncstatus.Default.Set(entry)
+}
+
+// removeSyncStatus removes a deleted virtual object from the failing objects
+func (r *syncerController) removeSyncStatus(req ctrl.Request) {
+	if r.disableSyncStatus || req.Namespace == "" {
+		return
+	}
+
+	entry := syncstatus.Entry{Namespace: req.Namespace, Name: req.Name, Status: syncstatus.SyncStatus{Syncer: r.syncer.Name()}}
+	gvk, err := apiutil.GVKForObject(r.syncer.Resource(), r.virtualClient.Scheme())
+	if err == nil {
+		entry.APIVersion, entry.Kind = gvk.ToAPIVersionAndKind()
+	}
+	syncstatus.Default.Set(entry)
+}
+
+func isNameConflict(pObj, vObj client.Object) bool {
+	if vObj.GetNamespace() == "" {
+		return false
+	}
+
+	// check if the physical object was synced by another virtual cluster in the same namespace
+	pLabels := pObj.GetLabels()
+	if translate.Default.SingleNamespaceTarget() && pLabels != nil && pLabels[translate.MarkerLabel] != "" && pLabels[translate.MarkerLabel] != translate.Suffix {
+		return true
+	}
+
+	// check if the physical object was synced from another virtual object
+	pAnnotations := pObj.GetAnnotations()
+	if pAnnotations == nil || pAnnotations[translate.NameAnnotation] == "" {
+		return false
+	}
+
+	return pAnnotations[translate.NameAnnotation] != vObj.GetName() || pAnnotations[translate.NamespaceAnnotation] != vObj.GetNamespace()
+}
+
 func (r *syncerController) excludePhysical(pObj client.Object) bool {
 	excluder, ok := r.syncer.(ObjectExcluder)
 	if ok {
@@ -225,7 +371,7 @@ func (r *syncerController) enqueuePhysical(ctx context.Context, obj client.Objec
 	}
 
 	name := r.syncer.PhysicalToVirtual(ctx, obj)
-	if name.Name != "" {
+	if name.Name != "" && r.owns(name.Namespace) {
 		q.Add(reconcile.Request{NamespacedName: name})
 	}
 }
@@ -237,8 +383,17 @@ func (r *syncerController) Register(ctx *synccontext.RegisterContext) error {
 		}).
 		Named(r.syncer.Name()).
 		WatchesRawSource(source.Kind(ctx.PhysicalManager.GetCache(), r.syncer.Resource()), r).
-		For(r.syncer.Resource())
-	var err error
+		For(r.syncer.Resource(), builder.WithPredicates(r.namespacePredicate()))
+
+	// namespaced objects can be paused through their virtual namespace
+	namespaced, err := apiutil.IsObjectNamespaced(r.syncer.Resource(), ctx.VirtualManager.GetScheme(), ctx.VirtualManager.GetRESTMapper())
+	if err != nil {
+		return err
+	} else if namespaced {
+		controller = r.watchPausedNamespaces(controller)
+		controller = r.watchOwnedNamespaces(controller)
+	}
+
 	modifier, ok := r.syncer.(ControllerModifier)
 	if ok {
 		controller, err = modifier.ModifyController(ctx, controller)
@@ -0,0 +1,208 @@
+package syncstatus
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Path is where the syncer serves the failing and paused objects within the virtual cluster api
+const Path = "/vcluster/sync-status"
+
+// maxErrorLength caps the error message stored on the virtual object
+const maxErrorLength = 1024
+
+// Default is the registry all syncers report the failing and paused objects to
+var Default = NewRegistry()
+
+type Phase string
+
+const (
+	PhaseSynced Phase = "Synced"
+	PhaseFailed Phase = "Failed"
+	PhasePaused Phase = "Paused"
+)
+
+// SyncStatus is stored json encoded in the constants.SyncStatusAnnotation of a virtual object
+type SyncStatus struct {
+	Phase  Phase  `json:"phase"`
+	Syncer string `json:"syncer,omitempty"`
+
+	// HostName is the namespace and name of the host object
+	HostName string `json:"hostName,omitempty"`
+
+	// LastError is the error of the last failed sync
+	LastError string `json:"lastError,omitempty"`
+
+	// LastSyncedGeneration is the generation of the virtual object that was synced successfully
+	LastSyncedGeneration int64 `json:"lastSyncedGeneration,omitempty"`
+
+	// LastTransitionTime is when the phase changed the last time
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// NewFailed returns the status of an object that could not be synced
+func NewFailed(syncer string, hostName types.NamespacedName, err error) *SyncStatus {
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+
+	return &SyncStatus{
+		Phase:     PhaseFailed,
+		Syncer:    syncer,
+		HostName:  hostName.String(),
+		LastError: message,
+	}
+}
+
+// NewPaused returns the status of an object whose syncing is paused
+func NewPaused(syncer string, hostName types.NamespacedName) *SyncStatus {
+	return &SyncStatus{
+		Phase:    PhasePaused,
+		Syncer:   syncer,
+		HostName: hostName.String(),
+	}
+}
+
+// NewSynced returns the status of an object that was synced successfully
+func NewSynced(syncer string, hostName types.NamespacedName, vObj client.Object) *SyncStatus {
+	return &SyncStatus{
+		Phase:                PhaseSynced,
+		Syncer:               syncer,
+		HostName:             hostName.String(),
+		LastSyncedGeneration: vObj.GetGeneration(),
+	}
+}
+
+// Get returns the sync status of the object or nil if there is none
+func Get(obj client.Object) *SyncStatus {
+	annotations := obj.GetAnnotations()
+	if annotations == nil || annotations[constants.SyncStatusAnnotation] == "" {
+		return nil
+	}
+
+	status := &SyncStatus{}
+	err := json.Unmarshal([]byte(annotations[constants.SyncStatusAnnotation]), status)
+	if err != nil {
+		return nil
+	}
+
+	return status
+}
+
+// Update writes the sync status to the object if it has changed. If status is nil, the sync status is removed.
+func Update(ctx context.Context, c client.Client, obj client.Object, status *SyncStatus) error {
+	existing := Get(obj)
+	if status != nil && existing != nil && existing.Phase == status.Phase {
+		status.LastTransitionTime = existing.LastTransitionTime
+	} else if status != nil {
+		status.LastTransitionTime = metav1.Now()
+	}
+	if equal(existing, status) {
+		return nil
+	}
+
+	value := []byte("null")
+	if status != nil {
+		out, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+
+		// the annotation value is a json string itself
+		value, err = json.Marshal(string(out))
+		if err != nil {
+			return err
+		}
+	}
+
+	key, err := json.Marshal(constants.SyncStatusAnnotation)
+	if err != nil {
+		return err
+	}
+
+	patch := []byte(`{"metadata":{"annotations":{` + string(key) + `:` + string(value) + `}}}`)
+	err = c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func equal(a, b *SyncStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Phase == b.Phase && a.Syncer == b.Syncer && a.HostName == b.HostName && a.LastError == b.LastError && a.LastSyncedGeneration == b.LastSyncedGeneration && a.LastTransitionTime.Equal(&b.LastTransitionTime)
+}
+
+// Report is the list of virtual objects that currently fail to sync or are paused
+type Report struct {
+	Items []*Entry `json:"items"`
+}
+
+// Entry is a virtual object that failed to sync or is paused
+type Entry struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	Status SyncStatus `json:"status"`
+}
+
+func (e *Entry) key() string {
+	return strings.Join([]string{e.Status.Syncer, e.APIVersion, e.Kind, e.Namespace, e.Name}, "/")
+}
+
+// Registry keeps track of the failing and paused objects of all syncers
+type Registry struct {
+	m       sync.Mutex
+	entries map[string]*Entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: map[string]*Entry{},
+	}
+}
+
+// Set adds the entry if it failed to sync or is paused and removes it otherwise
+func (r *Registry) Set(entry Entry) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if entry.Status.Phase == PhaseFailed || entry.Status.Phase == PhasePaused {
+		r.entries[entry.key()] = &entry
+	} else {
+		delete(r.entries, entry.key())
+	}
+}
+
+// Report returns a copy of all failing and paused objects sorted by syncer and object
+func (r *Registry) Report() *Report {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	report := &Report{Items: []*Entry{}}
+	for _, entry := range r.entries {
+		e := *entry
+		report.Items = append(report.Items, &e)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		return report.Items[i].key() < report.Items[j].key()
+	})
+
+	return report
+}
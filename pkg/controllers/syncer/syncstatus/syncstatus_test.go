@@ -0,0 +1,80 @@
+package syncstatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	vConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	vClient := testingutil.NewFakeClient(testingutil.NewScheme(), vConfigMap)
+	hostName := types.NamespacedName{Namespace: "test", Name: "test-x-default-x-suffix"}
+
+	// failed status is written to the object
+	err := Update(ctx, vClient, vConfigMap, NewFailed("configmap", hostName, errors.New("exceeded quota")))
+	assert.NilError(t, err)
+	vConfigMap = getConfigMap(t, vClient)
+	status := Get(vConfigMap)
+	assert.Assert(t, status != nil)
+	assert.Equal(t, status.Phase, PhaseFailed)
+	assert.Equal(t, status.HostName, "test/test-x-default-x-suffix")
+	assert.Equal(t, status.LastError, "exceeded quota")
+	assert.Assert(t, !status.LastTransitionTime.IsZero())
+
+	// unchanged status is not written again
+	resourceVersion := vConfigMap.ResourceVersion
+	err = Update(ctx, vClient, vConfigMap, NewFailed("configmap", hostName, errors.New("exceeded quota")))
+	assert.NilError(t, err)
+	assert.Equal(t, getConfigMap(t, vClient).ResourceVersion, resourceVersion)
+
+	// synced status replaces the error
+	err = Update(ctx, vClient, vConfigMap, NewSynced("configmap", hostName, vConfigMap))
+	assert.NilError(t, err)
+	vConfigMap = getConfigMap(t, vClient)
+	status = Get(vConfigMap)
+	assert.Equal(t, status.Phase, PhaseSynced)
+	assert.Equal(t, status.LastError, "")
+
+	// nil removes the status
+	err = Update(ctx, vClient, vConfigMap, nil)
+	assert.NilError(t, err)
+	_, ok := getConfigMap(t, vClient).Annotations[constants.SyncStatusAnnotation]
+	assert.Assert(t, !ok)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	failed := NewFailed("configmap", types.NamespacedName{Namespace: "test", Name: "b"}, errors.New("denied"))
+	registry.Set(Entry{Kind: "ConfigMap", Namespace: "default", Name: "b", Status: *failed})
+	registry.Set(Entry{Kind: "ConfigMap", Namespace: "default", Name: "a", Status: *failed})
+	assert.Equal(t, len(registry.Report().Items), 2)
+	assert.Equal(t, registry.Report().Items[0].Name, "a")
+
+	registry.Set(Entry{Kind: "ConfigMap", Namespace: "default", Name: "a", Status: SyncStatus{Syncer: "configmap"}})
+	report := registry.Report()
+	assert.Equal(t, len(report.Items), 1)
+	assert.Equal(t, report.Items[0].Name, "b")
+	assert.Equal(t, report.Items[0].Status.LastError, "denied")
+
+	// paused objects are reported as well
+	registry.Set(Entry{Kind: "ConfigMap", Namespace: "default", Name: "c", Status: *NewPaused("configmap", types.NamespacedName{Namespace: "test", Name: "c"})})
+	report = registry.Report()
+	assert.Equal(t, len(report.Items), 2)
+	assert.Equal(t, report.Items[1].Status.Phase, PhasePaused)
+}
+
+func getConfigMap(t *testing.T, vClient *testingutil.FakeIndexClient) *corev1.ConfigMap {
+	vConfigMap := &corev1.ConfigMap{}
+	err := vClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test"}, vConfigMap)
+	assert.NilError(t, err)
+	return vConfigMap
+}
@@ -0,0 +1,428 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	controllercontext "github.com/loft-sh/vcluster/cmd/vcluster/context"
+	"github.com/loft-sh/vcluster/pkg/controllers"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	util "github.com/loft-sh/vcluster/pkg/util/context"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+const (
+	DefaultName      = "vcluster"
+	DefaultNamespace = "vcluster"
+	DefaultTimeout   = 30 * time.Second
+
+	// AssetsEnv is the environment variable envtest reads the kube-apiserver and etcd binaries from
+	AssetsEnv = "KUBEBUILDER_ASSETS"
+
+	defaultAssetsDirectory = "/usr/local/kubebuilder/bin"
+)
+
+type Options struct {
+	// Args are the vcluster syncer flags, e.g. --sync-all-configmaps or --multi-namespace-mode
+	Args []string
+
+	// Syncers are the syncers to start, e.g. configmaps.New or the syncers of a plugin
+	Syncers []func(ctx *synccontext.RegisterContext) (syncer.Object, error)
+
+	// GenericSyncConfig is an optional generic sync configuration as it would be passed via the CONFIG
+	// environment variable of the syncer
+	GenericSyncConfig string
+
+	// HostCRDDirectoryPaths and VirtualCRDDirectoryPaths are directories with CRD manifests that are
+	// installed into the host and virtual cluster before the syncers start
+	HostCRDDirectoryPaths    []string
+	VirtualCRDDirectoryPaths []string
+
+	// Scheme is the scheme used by the syncer, defaults to the scheme of the fake test clients
+	Scheme *runtime.Scheme
+
+	// Timeout is the time the Expect and WaitFor helpers wait, defaults to DefaultTimeout
+	Timeout time.Duration
+}
+
+// Harness runs a host and a virtual kube-apiserver via envtest together with the vcluster syncer core
+// in-process. As translate.Default is global state, harnesses must not be used in parallel.
+type Harness struct {
+	t       testing.TB
+	timeout time.Duration
+
+	Context           context.Context
+	ControllerContext *controllercontext.ControllerContext
+
+	Host    *envtest.Environment
+	Virtual *envtest.Environment
+
+	HostClient    client.Client
+	VirtualClient client.Client
+}
+
+// Start starts the harness and registers its cleanup with the test. The test is skipped if the
+// envtest binaries cannot be found, see https://book.kubebuilder.io/reference/envtest.html.
+func Start(t testing.TB, options *Options) *Harness {
+	t.Helper()
+	if options == nil {
+		options = &Options{}
+	}
+	if !hasAssets() {
+		t.Skipf("skipping test, envtest binaries not found. Set %s to the directory containing kube-apiserver and etcd", AssetsEnv)
+	}
+
+	h, err := start(t, options)
+	if err != nil {
+		t.Fatalf("start harness: %v", err)
+	}
+
+	return h
+}
+
+func start(t testing.TB, options *Options) (*Harness, error) {
+	vClusterOptions, err := parseOptions(options.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := options.Scheme
+	if scheme == nil {
+		scheme = testingutil.NewScheme()
+		err = apiextensionsv1.AddToScheme(scheme)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Harness{
+		t:       t,
+		timeout: timeout,
+		Context: ctx,
+		Host:    &envtest.Environment{Scheme: scheme, CRDDirectoryPaths: options.HostCRDDirectoryPaths, ErrorIfCRDPathMissing: true},
+		Virtual: &envtest.Environment{Scheme: scheme, CRDDirectoryPaths: options.VirtualCRDDirectoryPaths, ErrorIfCRDPathMissing: true},
+	}
+	t.Cleanup(func() {
+		cancel()
+		_ = h.Virtual.Stop()
+		_ = h.Host.Stop()
+	})
+
+	hostConfig, err := h.Host.Start()
+	if err != nil {
+		return nil, fmt.Errorf("start host cluster: %w", err)
+	}
+	virtualConfig, err := h.Virtual.Start()
+	if err != nil {
+		return nil, fmt.Errorf("start virtual cluster: %w", err)
+	}
+
+	h.HostClient, err = client.New(hostConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	h.VirtualClient, err = client.New(virtualConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	// create the namespace vcluster is running in
+	err = h.HostClient.Create(ctx, &corev1.Namespace{ObjectMeta: ctrl.ObjectMeta{Name: DefaultNamespace}})
+	if err != nil {
+		return nil, err
+	}
+
+	// this is the same setup as the vcluster start command does
+	if vClusterOptions.MultiNamespaceMode {
+		vClusterOptions.TargetNamespace = ""
+		translate.Default = translate.NewMultiNamespaceTranslator(DefaultNamespace)
+	} else {
+		if vClusterOptions.TargetNamespace == "" {
+			vClusterOptions.TargetNamespace = DefaultNamespace
+		} else if vClusterOptions.TargetNamespace != DefaultNamespace {
+			err = h.HostClient.Create(ctx, &corev1.Namespace{ObjectMeta: ctrl.ObjectMeta{Name: vClusterOptions.TargetNamespace}})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		translate.Default, err = translate.NewSingleNamespaceTranslatorWithNameTemplate(vClusterOptions.TargetNamespace, vClusterOptions.NameTemplate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h.ControllerContext, err = newControllerContext(ctx, hostConfig, virtualConfig, scheme, vClusterOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, h.startSyncers(options)
+}
+
+func parseOptions(args []string) (*controllercontext.VirtualClusterOptions, error) {
+	options := &controllercontext.VirtualClusterOptions{}
+	flags := pflag.NewFlagSet("vcluster", pflag.ContinueOnError)
+	controllercontext.AddFlags(flags, options)
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Name == "" {
+		options.Name = DefaultName
+	}
+	if options.ServiceName == "" {
+		options.ServiceName = options.Name
+	}
+
+	return options, nil
+}
+
+func newControllerContext(ctx context.Context, hostConfig, virtualConfig *rest.Config, scheme *runtime.Scheme, options *controllercontext.VirtualClusterOptions) (*controllercontext.ControllerContext, error) {
+	localManager, err := ctrl.NewManager(hostConfig, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		Namespace:          options.TargetNamespace,
+		NewClient:          blockingcacheclient.NewCacheClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	virtualManager, err := ctrl.NewManager(virtualConfig, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		NewClient:          blockingcacheclient.NewCacheClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(virtualConfig)
+	if err != nil {
+		return nil, err
+	}
+	serverVersion, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	return controllercontext.NewControllerContext(ctx, DefaultNamespace, localManager, virtualManager, nil, serverVersion, options)
+}
+
+func (h *Harness) startSyncers(options *Options) error {
+	registerContext := util.ToRegisterContext(h.ControllerContext)
+	syncers := []syncer.Object{}
+	for _, newSyncer := range options.Syncers {
+		s, err := newSyncer(registerContext)
+		if err != nil {
+			return err
+		}
+
+		syncers = append(syncers, s)
+	}
+
+	err := controllers.ExecuteInitializers(h.ControllerContext, syncers)
+	if err != nil {
+		return err
+	}
+	err = controllers.RegisterIndices(h.ControllerContext, syncers)
+	if err != nil {
+		return err
+	}
+
+	// start the managers
+	go func() {
+		err := h.ControllerContext.LocalManager.Start(h.Context)
+		if err != nil && h.Context.Err() == nil {
+			panic(err)
+		}
+	}()
+	go func() {
+		err := h.ControllerContext.VirtualManager.Start(h.Context)
+		if err != nil && h.Context.Err() == nil {
+			panic(err)
+		}
+	}()
+	h.ControllerContext.LocalManager.GetCache().WaitForCacheSync(h.Context)
+	h.ControllerContext.VirtualManager.GetCache().WaitForCacheSync(h.Context)
+
+	err = controllers.RegisterGenericSyncControllerFromConfig(h.ControllerContext, options.GenericSyncConfig)
+	if err != nil {
+		return err
+	}
+
+	return controllers.RegisterSyncers(h.ControllerContext, syncers)
+}
+
+// CreateVirtual creates the given object in the virtual cluster
+func (h *Harness) CreateVirtual(obj client.Object) {
+	h.t.Helper()
+	err := h.VirtualClient.Create(h.Context, obj)
+	if err != nil {
+		h.t.Fatalf("create virtual %s: %v", objectKey(obj), err)
+	}
+}
+
+// UpdateVirtual updates the given object in the virtual cluster
+func (h *Harness) UpdateVirtual(obj client.Object) {
+	h.t.Helper()
+	err := h.VirtualClient.Update(h.Context, obj)
+	if err != nil {
+		h.t.Fatalf("update virtual %s: %v", objectKey(obj), err)
+	}
+}
+
+// DeleteVirtual deletes the given object in the virtual cluster
+func (h *Harness) DeleteVirtual(obj client.Object) {
+	h.t.Helper()
+	err := h.VirtualClient.Delete(h.Context, obj)
+	if err != nil && !kerrors.IsNotFound(err) {
+		h.t.Fatalf("delete virtual %s: %v", objectKey(obj), err)
+	}
+}
+
+// CreateHost creates the given object in the host cluster
+func (h *Harness) CreateHost(obj client.Object) {
+	h.t.Helper()
+	err := h.HostClient.Create(h.Context, obj)
+	if err != nil {
+		h.t.Fatalf("create host %s: %v", objectKey(obj), err)
+	}
+}
+
+// HostName returns the name of the host object the given virtual object is synced to
+func (h *Harness) HostName(vObj client.Object) types.NamespacedName {
+	h.t.Helper()
+	namespaced, err := apiutil.IsObjectNamespaced(vObj, h.VirtualClient.Scheme(), h.VirtualClient.RESTMapper())
+	if err != nil {
+		h.t.Fatalf("check scope of %s: %v", objectKey(vObj), err)
+	}
+	if !namespaced {
+		return types.NamespacedName{Name: translate.Default.PhysicalNameClusterScoped(vObj.GetName())}
+	}
+
+	return types.NamespacedName{
+		Namespace: translate.Default.PhysicalNamespace(vObj.GetNamespace()),
+		Name:      translate.Default.PhysicalName(vObj.GetName(), vObj.GetNamespace()),
+	}
+}
+
+// WaitForSync waits until the given virtual object exists in the host cluster and returns the host object
+func (h *Harness) WaitForSync(vObj client.Object) client.Object {
+	h.t.Helper()
+	pObj := newEmptyObject(vObj)
+	key := h.HostName(vObj)
+	h.expect(fmt.Sprintf("host object %s", key), func() error {
+		return h.HostClient.Get(h.Context, key, pObj)
+	})
+
+	return pObj
+}
+
+// ExpectHost waits until the host object with the given key exists and the given condition is met.
+// obj is filled with the latest state of the host object.
+func (h *Harness) ExpectHost(key types.NamespacedName, obj client.Object, condition func(obj client.Object) error) {
+	h.t.Helper()
+	h.expect(fmt.Sprintf("host object %s", key), getAndCheck(h.Context, h.HostClient, key, obj, condition))
+}
+
+// ExpectVirtual waits until the virtual object with the given key exists and the given condition is met.
+// obj is filled with the latest state of the virtual object.
+func (h *Harness) ExpectVirtual(key types.NamespacedName, obj client.Object, condition func(obj client.Object) error) {
+	h.t.Helper()
+	h.expect(fmt.Sprintf("virtual object %s", key), getAndCheck(h.Context, h.VirtualClient, key, obj, condition))
+}
+
+// ExpectHostDeleted waits until the host object with the given key is deleted
+func (h *Harness) ExpectHostDeleted(key types.NamespacedName, obj client.Object) {
+	h.t.Helper()
+	h.expect(fmt.Sprintf("deletion of host object %s", key), func() error {
+		err := h.HostClient.Get(h.Context, key, obj)
+		if err == nil {
+			return fmt.Errorf("object still exists")
+		} else if !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (h *Harness) expect(what string, check func() error) {
+	h.t.Helper()
+	var lastErr error
+	err := wait.PollUntilContextTimeout(h.Context, 100*time.Millisecond, h.timeout, true, func(ctx context.Context) (bool, error) {
+		lastErr = check()
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		h.t.Fatalf("timed out waiting for %s: %v", what, lastErr)
+	}
+}
+
+func getAndCheck(ctx context.Context, c client.Client, key types.NamespacedName, obj client.Object, condition func(obj client.Object) error) func() error {
+	return func() error {
+		err := c.Get(ctx, key, obj)
+		if err != nil {
+			return err
+		} else if condition != nil {
+			return condition(obj)
+		}
+
+		return nil
+	}
+}
+
+func newEmptyObject(obj client.Object) client.Object {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		out := &unstructured.Unstructured{}
+		out.SetGroupVersionKind(u.GroupVersionKind())
+		return out
+	}
+
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+}
+
+func objectKey(obj client.Object) string {
+	return types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String()
+}
+
+func hasAssets() bool {
+	dir := os.Getenv(AssetsEnv)
+	if dir == "" {
+		dir = defaultAssetsDirectory
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "kube-apiserver"))
+	return err == nil
+}
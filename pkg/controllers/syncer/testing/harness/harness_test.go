@@ -0,0 +1,44 @@
+package harness_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/configmaps"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/testing/harness"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestConfigMapSync(t *testing.T) {
+	h := harness.Start(t, &harness.Options{
+		Args:    []string{"--sync-all-configmaps"},
+		Syncers: []func(ctx *synccontext.RegisterContext) (syncer.Object, error){configmaps.New},
+	})
+
+	vConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+	h.CreateVirtual(vConfigMap)
+	pConfigMap := h.WaitForSync(vConfigMap).(*corev1.ConfigMap)
+	if pConfigMap.Data["key"] != "value" {
+		t.Fatalf("unexpected host data: %v", pConfigMap.Data)
+	}
+
+	vConfigMap.Data["key"] = "updated"
+	h.UpdateVirtual(vConfigMap)
+	h.ExpectHost(h.HostName(vConfigMap), &corev1.ConfigMap{}, func(obj client.Object) error {
+		if obj.(*corev1.ConfigMap).Data["key"] != "updated" {
+			return fmt.Errorf("data not updated yet")
+		}
+
+		return nil
+	})
+
+	h.DeleteVirtual(vConfigMap)
+	h.ExpectHostDeleted(h.HostName(vConfigMap), &corev1.ConfigMap{})
+}
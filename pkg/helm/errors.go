@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Reason describes why a helm operation failed
+type Reason string
+
+const (
+	ReasonUnknown         Reason = ""
+	ReasonReleaseNotFound Reason = "ReleaseNotFound"
+	ReasonTimeout         Reason = "Timeout"
+)
+
+// Error is returned by the helm client if a helm operation failed
+type Error struct {
+	// Operation is the helm command that failed, e.g. upgrade or delete
+	Operation string
+
+	// Args are the arguments helm was executed with. They can contain credentials and
+	// temporary file paths, so they are not part of the error message.
+	Args []string
+
+	Release   string
+	Namespace string
+
+	Reason Reason
+
+	// Output is the output helm printed
+	Output string
+}
+
+func (e *Error) Error() string {
+	switch e.Reason {
+	case ReasonTimeout:
+		return fmt.Sprintf(errorTimeout, e.Output, e.Operation)
+	case ReasonReleaseNotFound:
+		if e.Release != "" {
+			return fmt.Sprintf("release '%s' was not found in namespace '%s'", e.Release, e.Namespace)
+		}
+	}
+
+	return fmt.Sprintf(errorExecutingHelm, e.Operation, e.Output)
+}
+
+// IsReleaseNotFound returns true if the helm operation failed because the release doesn't exist
+func IsReleaseNotFound(err error) bool {
+	return reason(err) == ReasonReleaseNotFound
+}
+
+// IsTimeout returns true if the helm operation timed out
+func IsTimeout(err error) bool {
+	return reason(err) == ReasonTimeout
+}
+
+func reason(err error) Reason {
+	var helmErr *Error
+	if errors.As(err, &helmErr) {
+		return helmErr.Reason
+	}
+
+	return ReasonUnknown
+}
+
+// newError parses the output of a failed helm command
+func newError(operation string, args []string, output string) *Error {
+	err := &Error{
+		Operation: operation,
+		Args:      args,
+		Output:    output,
+	}
+	if strings.Contains(output, "release: not found") {
+		err.Reason = ReasonReleaseNotFound
+	}
+
+	return err
+}
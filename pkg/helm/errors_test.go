@@ -0,0 +1,39 @@
+package helm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"gotest.tools/assert"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestError(t *testing.T) {
+	err := newError("delete", []string{"delete", "test"}, "Error: uninstall: Release not loaded: test: release: not found")
+	err.Release = "test"
+	err.Namespace = "vcluster-test"
+	assert.Assert(t, IsReleaseNotFound(err))
+	assert.Assert(t, IsReleaseNotFound(fmt.Errorf("delete release: %w", err)))
+	assert.Equal(t, err.Error(), "release 'test' was not found in namespace 'vcluster-test'")
+
+	err = newError("upgrade", []string{"upgrade", "test", "--kubeconfig", "/tmp/kubeconfig"}, "Error: UPGRADE FAILED")
+	assert.Assert(t, !IsReleaseNotFound(err))
+	assert.Equal(t, err.Error(), "error executing helm upgrade: Error: UPGRADE FAILED")
+
+	err = &Error{Operation: "install", Reason: ReasonTimeout}
+	assert.Assert(t, IsTimeout(err))
+	assert.Assert(t, !IsTimeout(fmt.Errorf("other")))
+}
+
+func TestNewClientWithType(t *testing.T) {
+	helmClient, err := NewClientWithType("", &clientcmdapi.Config{}, &log.DiscardLogger{}, "helm")
+	assert.NilError(t, err)
+	_, ok := helmClient.(*client)
+	assert.Assert(t, ok)
+	assert.Assert(t, NeedsBinary(ClientTypeBinary))
+	assert.Assert(t, !NeedsBinary(ClientTypeSDK))
+
+	_, err = NewClientWithType("other", &clientcmdapi.Config{}, &log.DiscardLogger{}, "helm")
+	assert.ErrorContains(t, err, "unknown helm client other")
+}
@@ -48,12 +48,40 @@ type Client interface {
 	Install(ctx context.Context, name, namespace string, options UpgradeOptions) error
 	Upgrade(ctx context.Context, name, namespace string, options UpgradeOptions) error
 	Pull(ctx context.Context, name string, options UpgradeOptions) error
+	Template(ctx context.Context, name, namespace string, options UpgradeOptions) ([]byte, error)
 	Delete(name, namespace string) error
 	Exists(name, namespace string) (bool, error)
 	Rollback(ctx context.Context, name, namespace string) error
 	Status(ctx context.Context, name, namespace string) ([]byte, error)
 }
 
+// ClientType selects the implementation of the helm client
+type ClientType string
+
+const (
+	// ClientTypeBinary executes the helm binary, which is downloaded if it's not installed
+	ClientTypeBinary ClientType = "binary"
+	// ClientTypeSDK runs helm in-process via the Helm Go SDK and doesn't need the helm binary
+	ClientTypeSDK ClientType = "sdk"
+)
+
+// NewClientWithType creates a helm client of the given type, helmPath is only used by the binary client
+func NewClientWithType(clientType ClientType, config *clientcmdapi.Config, log log.Logger, helmPath string) (Client, error) {
+	switch clientType {
+	case "", ClientTypeBinary:
+		return NewClient(config, log, helmPath), nil
+	case ClientTypeSDK:
+		return NewSDKClient(config, log)
+	}
+
+	return nil, fmt.Errorf("unknown helm client %s, please use one of: %s, %s", clientType, ClientTypeBinary, ClientTypeSDK)
+}
+
+// NeedsBinary returns true if the helm client of the given type executes the helm binary
+func NeedsBinary(clientType ClientType) bool {
+	return clientType != ClientTypeSDK
+}
+
 type client struct {
 	config   *clientcmdapi.Config
 	log      log.Logger
@@ -85,12 +113,55 @@ func (c *client) Rollback(ctx context.Context, name, namespace string) error {
 	return c.run(ctx, name, namespace, UpgradeOptions{}, "rollback", []string{})
 }
 
+func (c *client) Template(ctx context.Context, name, namespace string, options UpgradeOptions) ([]byte, error) {
+	args, cleanup, err := c.args(name, namespace, options, "template", []string{"--repository-config=''"})
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Debug("execute command: helm " + strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, c.helmPath, args...)
+	if options.WorkDir != "" {
+		cmd.Dir = options.WorkDir
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, newError("template", args, string(exitErr.Stderr))
+		}
+
+		return nil, err
+	}
+
+	return output, nil
+}
+
 func (c *client) run(ctx context.Context, name, namespace string, options UpgradeOptions, command string, extraArgs []string) error {
-	kubeConfig, err := WriteKubeConfig(c.config)
+	args, cleanup, err := c.args(name, namespace, options, command, extraArgs)
+	defer cleanup()
 	if err != nil {
 		return err
 	}
-	defer os.Remove(kubeConfig)
+
+	return c.execute(ctx, args, command, options.WorkDir)
+}
+
+// args returns the helm arguments for the given command, cleanup removes the temporary files the arguments refer to
+func (c *client) args(name, namespace string, options UpgradeOptions, command string, extraArgs []string) ([]string, func(), error) {
+	tempFiles := []string{}
+	cleanup := func() {
+		for _, tempFile := range tempFiles {
+			_ = os.Remove(tempFile)
+		}
+	}
+
+	kubeConfig, err := WriteKubeConfig(c.config)
+	if err != nil {
+		return nil, cleanup, err
+	}
+	tempFiles = append(tempFiles, kubeConfig)
 
 	args := []string{command, name}
 	if options.Path != "" {
@@ -112,19 +183,18 @@ func (c *client) run(ctx context.Context, name, namespace string, options Upgrad
 		// Create temp file
 		tempFile, err := os.CreateTemp("", "")
 		if err != nil {
-			return errors.Wrap(err, "create temp file")
+			return nil, cleanup, errors.Wrap(err, "create temp file")
 		}
+		tempFiles = append(tempFiles, tempFile.Name())
 
 		// Write to temp file
 		_, err = tempFile.Write([]byte(options.Values))
 		if err != nil {
-			os.Remove(tempFile.Name())
-			return errors.Wrap(err, "write temp file")
+			return nil, cleanup, errors.Wrap(err, "write temp file")
 		}
 
 		// Close temp file
 		tempFile.Close()
-		defer os.Remove(tempFile.Name())
 
 		// Wait quickly so helm will find the file
 		time.Sleep(time.Millisecond)
@@ -178,7 +248,7 @@ func (c *client) run(ctx context.Context, name, namespace string, options Upgrad
 		args = append(args, "--atomic")
 	}
 
-	return c.execute(ctx, args, command, options.WorkDir)
+	return args, cleanup, nil
 }
 
 func (c *client) pull(ctx context.Context, name string, options UpgradeOptions) error {
@@ -260,10 +330,10 @@ func (c *client) execute(ctx context.Context, args []string, operation string, w
 	output, err := cmd.CombinedOutput()
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return fmt.Errorf(errorTimeout, string(output), operation)
+		return &Error{Operation: operation, Args: args, Reason: ReasonTimeout, Output: string(output)}
 	}
 	if err != nil {
-		return fmt.Errorf(errorExecutingHelm, strings.Join(args, " "), string(output))
+		return newError(operation, args, string(output))
 	}
 	return nil
 }
@@ -280,11 +350,10 @@ func (c *client) Delete(name, namespace string) error {
 	c.log.Debug("Delete helm chart with helm " + strings.Join(args, " "))
 	output, err := exec.Command(c.helmPath, args...).CombinedOutput()
 	if err != nil {
-		if strings.Contains(string(output), "release: not found") {
-			return fmt.Errorf("release '%s' was not found in namespace '%s'", name, namespace)
-		}
-
-		return fmt.Errorf("error executing helm delete: %s", string(output))
+		helmErr := newError("delete", args, string(output))
+		helmErr.Release = name
+		helmErr.Namespace = namespace
+		return helmErr
 	}
 
 	return nil
@@ -300,11 +369,12 @@ func (c *client) Exists(name, namespace string) (bool, error) {
 	args := []string{"status", name, "--namespace", namespace, "--kubeconfig", kubeConfig}
 	output, err := exec.Command(c.helmPath, args...).CombinedOutput()
 	if err != nil {
-		if strings.Contains(string(output), "release: not found") {
+		helmErr := newError("status", args, string(output))
+		if helmErr.Reason == ReasonReleaseNotFound {
 			return false, nil
 		}
 
-		return false, fmt.Errorf("error executing helm status: %s", string(output))
+		return false, helmErr
 	}
 
 	return true, nil
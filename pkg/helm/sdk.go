@@ -0,0 +1,480 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"helm.sh/helm/v3/pkg/strvals"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultTimeout is the timeout helm uses if --timeout is not set
+const defaultTimeout = 5 * time.Minute
+
+type sdkClient struct {
+	config   *clientcmdapi.Config
+	log      log.Logger
+	settings *cli.EnvSettings
+}
+
+// NewSDKClient creates a helm client that runs helm in-process via the Helm Go SDK. In contrast to
+// the binary client, it doesn't need the helm binary and passes the kube config in memory.
+func NewSDKClient(config *clientcmdapi.Config, log log.Logger) (Client, error) {
+	return &sdkClient{
+		config:   config,
+		log:      log,
+		settings: cli.New(),
+	}, nil
+}
+
+func (c *sdkClient) Install(ctx context.Context, name, namespace string, options UpgradeOptions) error {
+	actionConfig, err := c.actionConfig(namespace)
+	if err != nil {
+		return err
+	}
+
+	return c.install(ctx, actionConfig, name, namespace, options)
+}
+
+func (c *sdkClient) Upgrade(ctx context.Context, name, namespace string, options UpgradeOptions) error {
+	actionConfig, err := c.actionConfig(namespace)
+	if err != nil {
+		return err
+	}
+
+	// install the release if it doesn't exist yet, the same way helm upgrade --install does
+	history := action.NewHistory(actionConfig)
+	history.Max = 1
+	_, err = history.Run(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return c.install(ctx, actionConfig, name, namespace, options)
+	} else if err != nil {
+		return c.error(ctx, "upgrade", name, namespace, err)
+	}
+
+	chart, err := c.loadChart(actionConfig, options)
+	if err != nil {
+		return c.error(ctx, "upgrade", name, namespace, err)
+	}
+	values, err := c.values(options)
+	if err != nil {
+		return c.error(ctx, "upgrade", name, namespace, err)
+	}
+
+	upgrade := action.NewUpgrade(actionConfig)
+	upgrade.Namespace = namespace
+	upgrade.Atomic = options.Atomic
+	upgrade.Wait = options.Atomic
+	upgrade.Force = options.Force
+	upgrade.Timeout = timeout(ctx)
+	c.log.Debugf("upgrade helm release %s/%s", namespace, name)
+	_, err = upgrade.RunWithContext(ctx, name, chart, values)
+	return c.error(ctx, "upgrade", name, namespace, err)
+}
+
+func (c *sdkClient) install(ctx context.Context, actionConfig *action.Configuration, name, namespace string, options UpgradeOptions) error {
+	chart, err := c.loadChart(actionConfig, options)
+	if err != nil {
+		return c.error(ctx, "install", name, namespace, err)
+	}
+	values, err := c.values(options)
+	if err != nil {
+		return c.error(ctx, "install", name, namespace, err)
+	}
+
+	install := action.NewInstall(actionConfig)
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.CreateNamespace = options.CreateNamespace
+	install.Atomic = options.Atomic
+	install.Wait = options.Atomic
+	install.Timeout = timeout(ctx)
+	c.log.Debugf("install helm release %s/%s", namespace, name)
+	_, err = install.RunWithContext(ctx, chart, values)
+	return c.error(ctx, "install", name, namespace, err)
+}
+
+func (c *sdkClient) Pull(ctx context.Context, name string, options UpgradeOptions) error {
+	if options.Repo == "" {
+		return fmt.Errorf("cannot deploy chart without repo")
+	}
+
+	actionConfig, err := c.actionConfig("")
+	if err != nil {
+		return err
+	}
+	registryClient, cleanup, err := newRegistryClient(c.settings, options)
+	defer cleanup()
+	if err != nil {
+		return c.error(ctx, "pull", name, "", err)
+	}
+
+	pull := action.NewPullWithOpts(action.WithConfig(actionConfig))
+	pull.Settings = c.settings
+	pull.SetRegistryClient(registryClient)
+	pull.DestDir = options.WorkDir
+	if pull.DestDir == "" {
+		pull.DestDir = "."
+	}
+	pull.Version = options.Version
+	pull.Username = options.Username
+	pull.Password = options.Password
+	pull.InsecureSkipTLSverify = options.Insecure
+
+	chartRef := options.Chart
+	if registry.IsOCI(options.Repo) {
+		chartRef = strings.TrimSuffix(options.Repo, "/") + "/" + options.Chart
+	} else {
+		pull.RepoURL = options.Repo
+	}
+
+	c.log.Debugf("pull helm chart %s", chartRef)
+	_, err = pull.Run(chartRef)
+	return c.error(ctx, "pull", name, "", err)
+}
+
+func (c *sdkClient) Template(ctx context.Context, name, namespace string, options UpgradeOptions) ([]byte, error) {
+	actionConfig, err := c.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := c.loadChart(actionConfig, options)
+	if err != nil {
+		return nil, c.error(ctx, "template", name, namespace, err)
+	}
+	values, err := c.values(options)
+	if err != nil {
+		return nil, c.error(ctx, "template", name, namespace, err)
+	}
+
+	install := action.NewInstall(actionConfig)
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+	release, err := install.RunWithContext(ctx, chart, values)
+	if err != nil {
+		return nil, c.error(ctx, "template", name, namespace, err)
+	}
+
+	// print the manifests and hooks the same way helm template does
+	out := &bytes.Buffer{}
+	fmt.Fprintln(out, strings.TrimSpace(release.Manifest))
+	for _, hook := range release.Hooks {
+		fmt.Fprintf(out, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+
+	return out.Bytes(), nil
+}
+
+func (c *sdkClient) Delete(name, namespace string) error {
+	actionConfig, err := c.actionConfig(namespace)
+	if err != nil {
+		return err
+	}
+
+	c.log.Debugf("delete helm release %s/%s", namespace, name)
+	_, err = action.NewUninstall(actionConfig).Run(name)
+	return c.error(context.TODO(), "delete", name, namespace, err)
+}
+
+func (c *sdkClient) Exists(name, namespace string) (bool, error) {
+	actionConfig, err := c.actionConfig(namespace)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = action.NewStatus(actionConfig).Run(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, c.error(context.TODO(), "status", name, namespace, err)
+	}
+
+	return true, nil
+}
+
+func (c *sdkClient) Rollback(ctx context.Context, name, namespace string) error {
+	actionConfig, err := c.actionConfig(namespace)
+	if err != nil {
+		return err
+	}
+
+	rollback := action.NewRollback(actionConfig)
+	rollback.Timeout = timeout(ctx)
+	c.log.Debugf("rollback helm release %s/%s", namespace, name)
+	return c.error(ctx, "rollback", name, namespace, rollback.Run(name))
+}
+
+func (c *sdkClient) Status(ctx context.Context, name, namespace string) ([]byte, error) {
+	actionConfig, err := c.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := action.NewStatus(actionConfig).Run(name)
+	if err != nil {
+		return nil, c.error(ctx, "status", name, namespace, err)
+	}
+
+	// print the same header as helm status
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "NAME: %s\n", release.Name)
+	if release.Info != nil {
+		fmt.Fprintf(out, "LAST DEPLOYED: %s\n", release.Info.LastDeployed.Format(time.ANSIC))
+	}
+	fmt.Fprintf(out, "NAMESPACE: %s\n", release.Namespace)
+	if release.Info != nil {
+		fmt.Fprintf(out, "STATUS: %s\n", release.Info.Status.String())
+	}
+	fmt.Fprintf(out, "REVISION: %d\n", release.Version)
+	return out.Bytes(), nil
+}
+
+// actionConfig creates the helm action configuration for the namespace, releases are stored as secrets like the helm binary does
+func (c *sdkClient) actionConfig(namespace string) (*action.Configuration, error) {
+	actionConfig := &action.Configuration{}
+	err := actionConfig.Init(newRESTClientGetter(c.config, namespace), namespace, "secret", c.log.Debugf)
+	if err != nil {
+		return nil, fmt.Errorf("init helm: %w", err)
+	}
+
+	return actionConfig, nil
+}
+
+// loadChart loads the local chart or locates the chart in the repository or OCI registry
+func (c *sdkClient) loadChart(actionConfig *action.Configuration, options UpgradeOptions) (*chart.Chart, error) {
+	if options.Path != "" {
+		return loader.Load(workDirPath(options.WorkDir, options.Path))
+	} else if options.Chart == "" {
+		return nil, fmt.Errorf("cannot deploy chart without chart name or path")
+	} else if options.Repo == "" {
+		return nil, fmt.Errorf("cannot deploy chart without repo")
+	}
+
+	registryClient, cleanup, err := newRegistryClient(c.settings, options)
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+	actionConfig.RegistryClient = registryClient
+
+	install := action.NewInstall(actionConfig)
+	install.SetRegistryClient(registryClient)
+	install.Version = options.Version
+	install.Username = options.Username
+	install.Password = options.Password
+	install.InsecureSkipTLSverify = options.Insecure
+
+	chartRef := options.Chart
+	if registry.IsOCI(options.Repo) {
+		chartRef = strings.TrimSuffix(options.Repo, "/") + "/" + options.Chart
+	} else {
+		install.RepoURL = options.Repo
+	}
+
+	chartPath, err := install.ChartPathOptions.LocateChart(chartRef, c.settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.Load(chartPath)
+}
+
+// values merges the values in the same order the binary client passes them to helm
+func (c *sdkClient) values(options UpgradeOptions) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if options.Values != "" {
+		err := yaml.Unmarshal([]byte(options.Values), &values)
+		if err != nil {
+			return nil, fmt.Errorf("parse values: %w", err)
+		}
+	}
+
+	for _, file := range options.ValuesFiles {
+		out, err := os.ReadFile(workDirPath(options.WorkDir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		fileValues := map[string]interface{}{}
+		err = yaml.Unmarshal(out, &fileValues)
+		if err != nil {
+			return nil, fmt.Errorf("parse values file %s: %w", file, err)
+		}
+
+		MergeValues(values, fileValues)
+	}
+
+	for key, value := range options.SetValues {
+		err := strvals.ParseInto(key+"="+value, values)
+		if err != nil {
+			return nil, fmt.Errorf("parse set value %s: %w", key, err)
+		}
+	}
+	for key, value := range options.SetStringValues {
+		err := strvals.ParseIntoString(key+"="+value, values)
+		if err != nil {
+			return nil, fmt.Errorf("parse set string value %s: %w", key, err)
+		}
+	}
+
+	return values, nil
+}
+
+// error converts an error of the helm sdk into a structured helm error
+func (c *sdkClient) error(ctx context.Context, operation, name, namespace string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	helmErr := &Error{
+		Operation: operation,
+		Release:   name,
+		Namespace: namespace,
+		Output:    err.Error(),
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		helmErr.Reason = ReasonTimeout
+	} else if errors.Is(err, driver.ErrReleaseNotFound) {
+		helmErr.Reason = ReasonReleaseNotFound
+	}
+
+	return helmErr
+}
+
+// newRegistryClient creates a registry client for OCI charts. With credentials, the client logs in with a
+// temporary credentials file, which cleanup removes, so the credentials don't end up in the helm config.
+func newRegistryClient(settings *cli.EnvSettings, options UpgradeOptions) (*registry.Client, func(), error) {
+	cleanup := func() {}
+	credentialsFile := settings.RegistryConfig
+	if options.Username != "" && options.Password != "" {
+		tempDir, err := os.MkdirTemp("", "vcluster-helm-registry-")
+		if err != nil {
+			return nil, cleanup, err
+		}
+		cleanup = func() {
+			_ = os.RemoveAll(tempDir)
+		}
+		credentialsFile = filepath.Join(tempDir, "config.json")
+	}
+
+	registryOptions := []registry.ClientOption{
+		registry.ClientOptWriter(io.Discard),
+		registry.ClientOptCredentialsFile(credentialsFile),
+	}
+	if options.Insecure {
+		registryOptions = append(registryOptions, registry.ClientOptHTTPClient(&http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+			},
+		}))
+	}
+
+	registryClient, err := registry.NewClient(registryOptions...)
+	if err != nil {
+		return nil, cleanup, err
+	}
+
+	if options.Username != "" && options.Password != "" && registry.IsOCI(options.Repo) {
+		repoURL, err := url.Parse(options.Repo)
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("error login in, repo is not a valid URL: %s", options.Repo)
+		}
+
+		err = registryClient.Login(repoURL.Host, registry.LoginOptBasicAuth(options.Username, options.Password), registry.LoginOptInsecure(options.Insecure))
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("error login to registry: %w", err)
+		}
+	}
+
+	return registryClient, cleanup, nil
+}
+
+// timeout returns the time until the deadline of the context or the default helm timeout
+func timeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+
+	return defaultTimeout
+}
+
+func workDirPath(workDir, path string) string {
+	if workDir == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(workDir, path)
+}
+
+// restClientGetter passes the kube config to helm without writing it to a file
+type restClientGetter struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+func newRESTClientGetter(config *clientcmdapi.Config, namespace string) *restClientGetter {
+	return &restClientGetter{
+		clientConfig: clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{
+			Context: clientcmdapi.Context{Namespace: namespace},
+		}),
+	}
+}
+
+func (r *restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return r.clientConfig.ClientConfig()
+}
+
+func (r *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	restConfig, err := r.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return memory.NewMemCacheClient(discoveryClient), nil
+}
+
+func (r *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := r.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient), nil
+}
+
+func (r *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return r.clientConfig
+}
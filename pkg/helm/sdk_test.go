@@ -0,0 +1,53 @@
+package helm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/log"
+	"gotest.tools/assert"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestSDKClientTemplate(t *testing.T) {
+	chartDir := t.TempDir()
+	writeFile(t, filepath.Join(chartDir, "Chart.yaml"), "apiVersion: v2\nname: test\nversion: 0.1.0\n")
+	writeFile(t, filepath.Join(chartDir, "values.yaml"), "replicas: 1\nimage: nginx\n")
+	writeFile(t, filepath.Join(chartDir, "templates", "configmap.yaml"), `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+data:
+  replicas: "{{ .Values.replicas }}"
+  image: {{ .Values.image }}
+`)
+
+	helmClient, err := NewClientWithType(ClientTypeSDK, &clientcmdapi.Config{}, &log.DiscardLogger{}, "")
+	assert.NilError(t, err)
+	_, ok := helmClient.(*sdkClient)
+	assert.Assert(t, ok)
+
+	out, err := helmClient.Template(context.Background(), "my-release", "my-namespace", UpgradeOptions{
+		Path:      chartDir,
+		Values:    "replicas: 3\n",
+		SetValues: map[string]string{"image": "nginx:1.25"},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(out), "name: my-release"), string(out))
+	assert.Assert(t, strings.Contains(string(out), "namespace: my-namespace"), string(out))
+	assert.Assert(t, strings.Contains(string(out), `replicas: "3"`), string(out))
+	assert.Assert(t, strings.Contains(string(out), "image: nginx:1.25"), string(out))
+
+	// a missing chart is reported as helm error
+	_, err = helmClient.Template(context.Background(), "my-release", "my-namespace", UpgradeOptions{Path: filepath.Join(chartDir, "missing")})
+	assert.ErrorContains(t, err, "error executing helm template")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NilError(t, os.WriteFile(path, []byte(content), 0644))
+}
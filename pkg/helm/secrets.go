@@ -150,6 +150,8 @@ type Metadata struct {
 // Chart holds the chart metadata
 type Chart struct {
 	Metadata *Metadata `json:"metadata,omitempty"`
+	// Values are the default values of the chart
+	Values map[string]interface{} `json:"values,omitempty"`
 }
 
 // Secrets is a wrapper around an implementation of a kubernetes
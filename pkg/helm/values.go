@@ -0,0 +1,36 @@
+package helm
+
+// MergeValues merges overrides into values the way helm coalesces the release values with the
+// chart values. Maps are merged recursively and everything else is replaced.
+func MergeValues(values, overrides map[string]interface{}) {
+	for key, override := range overrides {
+		overrideMap, ok := override.(map[string]interface{})
+		valueMap, valueOk := values[key].(map[string]interface{})
+		if ok && valueOk {
+			MergeValues(valueMap, overrideMap)
+			continue
+		} else if ok {
+			// copy the map, so later merges don't modify the overrides
+			copied := map[string]interface{}{}
+			MergeValues(copied, overrideMap)
+			override = copied
+		}
+
+		values[key] = override
+	}
+}
+
+// LookupValue returns the value at the given path or nil if it doesn't exist
+func LookupValue(values map[string]interface{}, path ...string) interface{} {
+	var current interface{} = values
+	for _, key := range path {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		current = currentMap[key]
+	}
+
+	return current
+}
@@ -0,0 +1,37 @@
+package helm
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+)
+
+func TestMergeValues(t *testing.T) {
+	values := map[string]interface{}{
+		"sync": map[string]interface{}{
+			"ingresses": map[string]interface{}{"enabled": false},
+			"nodes":     map[string]interface{}{"enabled": true},
+		},
+		"replicas": 1,
+	}
+	overrides := map[string]interface{}{
+		"sync": map[string]interface{}{
+			"ingresses": map[string]interface{}{"enabled": true},
+		},
+		"isolation": map[string]interface{}{"enabled": true},
+		"replicas":  2,
+	}
+
+	MergeValues(values, overrides)
+	assert.Equal(t, LookupValue(values, "sync", "ingresses", "enabled"), true)
+	assert.Equal(t, LookupValue(values, "sync", "nodes", "enabled"), true)
+	assert.Equal(t, LookupValue(values, "isolation", "enabled"), true)
+	assert.Equal(t, LookupValue(values, "replicas"), 2)
+	assert.Assert(t, cmp.Nil(LookupValue(values, "replicas", "enabled")))
+	assert.Assert(t, cmp.Nil(LookupValue(values, "missing", "enabled")))
+
+	// merged maps are copied
+	MergeValues(values, map[string]interface{}{"isolation": map[string]interface{}{"enabled": false}})
+	assert.Equal(t, LookupValue(overrides, "isolation", "enabled"), true)
+}
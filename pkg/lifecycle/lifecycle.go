@@ -18,34 +18,48 @@ import (
 )
 
 // PauseVCluster pauses a running vcluster
-func PauseVCluster(ctx context.Context, kubeClient *kubernetes.Clientset, name, namespace string, log log.Logger) error {
+func PauseVCluster(ctx context.Context, kubeClient kubernetes.Interface, name, namespace string, log log.Logger) error {
+	return PauseVClusterWithAnnotations(ctx, kubeClient, name, namespace, nil, log)
+}
+
+// PauseVClusterWithAnnotations pauses a running vcluster and adds the given annotations to the scaled down workloads
+func PauseVClusterWithAnnotations(ctx context.Context, kubeClient kubernetes.Interface, name, namespace string, annotations map[string]string, log log.Logger) error {
 	// scale down vcluster itself
 	labelSelector := "app=vcluster,release=" + name
-	found, err := scaleDownStatefulSet(ctx, kubeClient, labelSelector, namespace, log)
+	found, err := scaleDownStatefulSet(ctx, kubeClient, labelSelector, namespace, annotations, log)
 	if err != nil {
 		return err
 	} else if !found {
-		found, err = scaleDownDeployment(ctx, kubeClient, labelSelector, namespace, log)
+		list, err := kubeClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 		if err != nil {
 			return err
-		} else if !found {
+		} else if len(list.Items) == 0 {
 			return errors.Errorf("couldn't find vcluster %s in namespace %s", name, namespace)
 		}
 
+		// the control plane is scaled down before the syncer, so that a syncer that pauses
+		// its own vcluster isn't terminated before the other components are scaled down
+
 		// scale down kube api server
-		_, err = scaleDownDeployment(ctx, kubeClient, "app=vcluster-api,release="+name, namespace, log)
+		_, err = scaleDownDeployment(ctx, kubeClient, "app=vcluster-api,release="+name, namespace, annotations, log)
 		if err != nil {
 			return err
 		}
 
 		// scale down kube controller
-		_, err = scaleDownDeployment(ctx, kubeClient, "app=vcluster-controller,release="+name, namespace, log)
+		_, err = scaleDownDeployment(ctx, kubeClient, "app=vcluster-controller,release="+name, namespace, annotations, log)
 		if err != nil {
 			return err
 		}
 
 		// scale down etcd
-		_, err = scaleDownStatefulSet(ctx, kubeClient, "app=vcluster-etcd,release="+name, namespace, log)
+		_, err = scaleDownStatefulSet(ctx, kubeClient, "app=vcluster-etcd,release="+name, namespace, annotations, log)
+		if err != nil {
+			return err
+		}
+
+		// scale down syncer
+		_, err = scaleDownDeployment(ctx, kubeClient, labelSelector, namespace, annotations, log)
 		if err != nil {
 			return err
 		}
@@ -55,7 +69,7 @@ func PauseVCluster(ctx context.Context, kubeClient *kubernetes.Clientset, name,
 }
 
 // DeleteVClusterWorkloads deletes all pods associated with a running vcluster
-func DeleteVClusterWorkloads(ctx context.Context, kubeClient *kubernetes.Clientset, labelSelector, namespace string, log log.Logger) error {
+func DeleteVClusterWorkloads(ctx context.Context, kubeClient kubernetes.Interface, labelSelector, namespace string, log log.Logger) error {
 	list, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
 		return err
@@ -74,7 +88,7 @@ func DeleteVClusterWorkloads(ctx context.Context, kubeClient *kubernetes.Clients
 	return nil
 }
 
-func DeleteMultiNamespaceVclusterWorkloads(ctx context.Context, client *kubernetes.Clientset, vclusterName, vclusterNamespace string, log log.Logger) error {
+func DeleteMultiNamespaceVclusterWorkloads(ctx context.Context, client kubernetes.Interface, vclusterName, vclusterNamespace string, log log.Logger) error {
 	// get all host namespaces managed by this multinamespace mode enabled vcluster
 	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
 		LabelSelector: labels.FormatLabels(map[string]string{
@@ -103,7 +117,7 @@ func DeleteMultiNamespaceVclusterWorkloads(ctx context.Context, client *kubernet
 	return nil
 }
 
-func scaleDownDeployment(ctx context.Context, kubeClient kubernetes.Interface, labelSelector, namespace string, log log.Logger) (bool, error) {
+func scaleDownDeployment(ctx context.Context, kubeClient kubernetes.Interface, labelSelector, namespace string, annotations map[string]string, log log.Logger) (bool, error) {
 	list, err := kubeClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
 		return false, err
@@ -133,6 +147,9 @@ func scaleDownDeployment(ctx context.Context, kubeClient kubernetes.Interface, l
 		item.Annotations[constants.PausedAnnotation] = "true"
 		item.Annotations[constants.PausedReplicasAnnotation] = strconv.Itoa(replicas)
 		item.Annotations[constants.PausedDateAnnotation] = time.Now().Format("2006-01-02T15:04:05.000Z")
+		for k, v := range annotations {
+			item.Annotations[k] = v
+		}
 		item.Spec.Replicas = &zero
 
 		patch := client.MergeFrom(originalObject)
@@ -165,7 +182,7 @@ func scaleDownDeployment(ctx context.Context, kubeClient kubernetes.Interface, l
 	return true, nil
 }
 
-func scaleDownStatefulSet(ctx context.Context, kubeClient kubernetes.Interface, labelSelector, namespace string, log log.Logger) (bool, error) {
+func scaleDownStatefulSet(ctx context.Context, kubeClient kubernetes.Interface, labelSelector, namespace string, annotations map[string]string, log log.Logger) (bool, error) {
 	list, err := kubeClient.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
 		return false, err
@@ -195,6 +212,9 @@ func scaleDownStatefulSet(ctx context.Context, kubeClient kubernetes.Interface,
 		item.Annotations[constants.PausedAnnotation] = "true"
 		item.Annotations[constants.PausedReplicasAnnotation] = strconv.Itoa(replicas)
 		item.Annotations[constants.PausedDateAnnotation] = time.Now().Format("2006-01-02T15:04:05.000Z")
+		for k, v := range annotations {
+			item.Annotations[k] = v
+		}
 		item.Spec.Replicas = &zero
 
 		patch := client.MergeFrom(originalObject)
@@ -228,7 +248,7 @@ func scaleDownStatefulSet(ctx context.Context, kubeClient kubernetes.Interface,
 }
 
 // ResumeVCluster resumes a paused vcluster
-func ResumeVCluster(ctx context.Context, kubeClient *kubernetes.Clientset, name, namespace string, log log.Logger) error {
+func ResumeVCluster(ctx context.Context, kubeClient kubernetes.Interface, name, namespace string, log log.Logger) error {
 	// scale up vcluster itself
 	labelSelector := "app=vcluster,release=" + name
 	found, err := scaleUpStatefulSet(ctx, kubeClient, labelSelector, namespace, log)
@@ -292,6 +312,7 @@ func scaleUpDeployment(ctx context.Context, kubeClient kubernetes.Interface, lab
 		delete(item.Annotations, constants.PausedAnnotation)
 		delete(item.Annotations, constants.PausedReplicasAnnotation)
 		delete(item.Annotations, constants.PausedDateAnnotation)
+		delete(item.Annotations, constants.SleepReasonAnnotation)
 		item.Spec.Replicas = &replicas32
 
 		patch := client.MergeFrom(originalObject)
@@ -338,6 +359,7 @@ func scaleUpStatefulSet(ctx context.Context, kubeClient kubernetes.Interface, la
 		delete(item.Annotations, constants.PausedAnnotation)
 		delete(item.Annotations, constants.PausedReplicasAnnotation)
 		delete(item.Annotations, constants.PausedDateAnnotation)
+		delete(item.Annotations, constants.SleepReasonAnnotation)
 		item.Spec.Replicas = &replicas32
 
 		patch := client.MergeFrom(originalObject)
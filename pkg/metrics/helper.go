@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	corev1 "k8s.io/api/core/v1"
@@ -83,7 +84,7 @@ func Rewrite(ctx context.Context, metricsFamilies []*dto.MetricFamily, vClient c
 			// rewrite pod
 			if pod != "" {
 				// search if we can find the pod by name in the virtual cluster
-				podList := &corev1.PodList{}
+				podList := clienthelper.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Pod"))
 				err := vClient.List(ctx, podList, client.MatchingFields{constants.IndexByPhysicalName: namespace + "/" + pod})
 				if err != nil {
 					return nil, err
@@ -101,7 +102,7 @@ func Rewrite(ctx context.Context, metricsFamilies []*dto.MetricFamily, vClient c
 			// rewrite persistentvolumeclaim
 			if persistentvolumeclaim != "" {
 				// search if we can find the pvc by name in the virtual cluster
-				pvcList := &corev1.PersistentVolumeClaimList{}
+				pvcList := clienthelper.NewMetadataList(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
 				err := vClient.List(ctx, pvcList, client.MatchingFields{constants.IndexByPhysicalName: namespace + "/" + persistentvolumeclaim})
 				if err != nil {
 					return nil, err
@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"sync"
+	"time"
+)
+
+// Default is the tracker the syncer proxy records its requests in
+var Default = NewTracker()
+
+// Tracker records when the virtual cluster api was used the last time
+type Tracker struct {
+	m sync.Mutex
+
+	lastActivity time.Time
+	inFlight     int
+}
+
+// NewTracker creates a new tracker, the creation counts as the first activity
+func NewTracker() *Tracker {
+	return &Tracker{
+		lastActivity: time.Now(),
+	}
+}
+
+// Start records the start of a request. The returned function needs to be called as soon as the request is done,
+// as the virtual cluster is considered active as long as there are requests in flight.
+func (t *Tracker) Start() func() {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.inFlight++
+	t.lastActivity = time.Now()
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			t.m.Lock()
+			defer t.m.Unlock()
+
+			t.inFlight--
+			t.lastActivity = time.Now()
+		})
+	}
+}
+
+// IdleSince returns the time of the last activity. If there are requests in flight, false is returned.
+func (t *Tracker) IdleSince() (time.Time, bool) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	return t.lastActivity, t.inFlight == 0
+}
+
+// Observe records an activity another replica served, if it is newer than the last activity
+func (t *Tracker) Observe(lastActivity time.Time) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if lastActivity.After(t.lastActivity) {
+		t.lastActivity = lastActivity
+	}
+}
@@ -0,0 +1,109 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+// Shared shares the last activity of all replicas of a virtual cluster through a lease. Every replica
+// serves the virtual cluster api, but only the leader decides if the virtual cluster goes to sleep.
+type Shared struct {
+	Client    kubernetes.Interface
+	Namespace string
+
+	// Name is the name of the virtual cluster the replicas belong to
+	Name string
+
+	// Identity distinguishes this replica from the others
+	Identity string
+
+	// Tracker records the requests this replica serves
+	Tracker *Tracker
+
+	// Interval is how often the activity is shared. Requests in flight on another replica are seen
+	// by the leader with at most this delay.
+	Interval time.Duration
+
+	now func() time.Time
+}
+
+// Start shares the activity periodically until the context is done
+func (s *Shared) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		err := s.Sync(ctx)
+		if err != nil {
+			klog.Errorf("error sharing vcluster activity: %v", err)
+		}
+	}, s.Interval)
+
+	return nil
+}
+
+// Sync publishes the last activity of this replica in the lease if it is newer than the shared one.
+// Otherwise the shared activity of the other replicas is recorded in the tracker.
+func (s *Shared) Sync(ctx context.Context) error {
+	lastActivity, idle := s.Tracker.IdleSince()
+	if !idle {
+		// requests in flight keep the virtual cluster active
+		lastActivity = s.getNow()
+	}
+	// the lease only stores microseconds
+	lastActivity = lastActivity.Truncate(time.Microsecond)
+	renewTime := metav1.NewMicroTime(lastActivity)
+
+	lease, err := s.Client.CoordinationV1().Leases(s.Namespace).Get(ctx, s.leaseName(), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = s.Client.CoordinationV1().Leases(s.Namespace).Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.leaseName(),
+				Namespace: s.Namespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity: pointer.String(s.Identity),
+				RenewTime:      &renewTime,
+			},
+		}, metav1.CreateOptions{})
+		if kerrors.IsAlreadyExists(err) {
+			// another replica created it in the meantime, retry on the next interval
+			return nil
+		}
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if lease.Spec.RenewTime != nil && !lease.Spec.RenewTime.Time.Before(lastActivity) {
+		s.Tracker.Observe(lease.Spec.RenewTime.Time)
+		return nil
+	}
+
+	lease.Spec.HolderIdentity = pointer.String(s.Identity)
+	lease.Spec.RenewTime = &renewTime
+	_, err = s.Client.CoordinationV1().Leases(s.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if kerrors.IsConflict(err) {
+		// another replica updated it in the meantime, retry on the next interval
+		return nil
+	}
+	return err
+}
+
+func (s *Shared) leaseName() string {
+	return translate.SafeConcatName("vcluster", s.Name, "activity")
+}
+
+func (s *Shared) getNow() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,58 @@
+package activity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestShared(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	start := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	now := start
+	newReplica := func(identity string) *Shared {
+		return &Shared{
+			Client:    client,
+			Namespace: "test",
+			Name:      "vcluster",
+			Identity:  identity,
+			Tracker:   &Tracker{lastActivity: start},
+			now:       func() time.Time { return now },
+		}
+	}
+	leader := newReplica("leader")
+	follower := newReplica("follower")
+	assert.NilError(t, leader.Sync(ctx))
+	assert.NilError(t, follower.Sync(ctx))
+
+	// a request the follower served is seen by the leader
+	now = start.Add(time.Minute)
+	follower.Tracker.Observe(now)
+	assert.NilError(t, follower.Sync(ctx))
+	assert.NilError(t, leader.Sync(ctx))
+	lastActivity, idle := leader.Tracker.IdleSince()
+	assert.Assert(t, idle)
+	assert.Equal(t, lastActivity, now)
+
+	// a request in flight on the follower keeps the leader active
+	done := follower.Tracker.Start()
+	now = start.Add(time.Hour)
+	assert.NilError(t, follower.Sync(ctx))
+	assert.NilError(t, leader.Sync(ctx))
+	lastActivity, _ = leader.Tracker.IdleSince()
+	assert.Equal(t, lastActivity, now)
+	done()
+
+	// an older activity doesn't overwrite the shared one
+	leader.Tracker = &Tracker{lastActivity: start}
+	assert.NilError(t, leader.Sync(ctx))
+	lease, err := client.CoordinationV1().Leases("test").Get(ctx, leader.leaseName(), metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, lease.Spec.RenewTime.Time, now)
+	assert.Equal(t, *lease.Spec.HolderIdentity, "follower")
+}
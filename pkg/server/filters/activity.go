@@ -0,0 +1,26 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/server/activity"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithActivityTracker records the resource requests to the virtual cluster in the given tracker. Watches are not
+// recorded, as controllers within the virtual cluster would otherwise keep it active forever, while other long
+// running requests such as exec, logs or port forwardings count as activity until they are done.
+func WithActivityTracker(h http.Handler, tracker *activity.Tracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok || !info.IsResourceRequest || info.Verb == "watch" {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		done := tracker.Start()
+		defer done()
+
+		h.ServeHTTP(w, req)
+	})
+}
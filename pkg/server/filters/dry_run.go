@@ -0,0 +1,50 @@
+package filters
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/dryrun"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const DryRunReportPath = "/vcluster/dry-run"
+
+// WithDryRunReport serves the aggregated host changes of the dry run syncers to virtual cluster admins
+func WithDryRunReport(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != DryRunReportPath {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok || !isAdmin(userInfo) {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return
+		} else if req.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		out, err := json.MarshalIndent(dryrun.Default.Report(), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	})
+}
+
+func isAdmin(userInfo user.Info) bool {
+	for _, group := range userInfo.GetGroups() {
+		if group == user.SystemPrivilegedGroup {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package filters
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/garbagecollector"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithGarbageCollector serves the report of the last garbage collection run on GET and starts a new run
+// on POST to virtual cluster admins. A POST request can set the delete and gracePeriod (in seconds) parameters.
+func WithGarbageCollector(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != garbagecollector.Path {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok || !isAdmin(userInfo) {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return
+		}
+
+		collector := garbagecollector.Default()
+		if collector == nil {
+			http.Error(w, "garbage collector is not running on this replica", http.StatusServiceUnavailable)
+			return
+		}
+
+		var report *garbagecollector.Report
+		switch req.Method {
+		case http.MethodGet:
+			report = collector.LastReport()
+			if report == nil {
+				http.Error(w, "garbage collector did not run yet", http.StatusNotFound)
+				return
+			}
+		case http.MethodPost:
+			options, err := parseRunOptions(req, collector.Options)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			report, err = collector.Run(req.Context(), options)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	})
+}
+
+func parseRunOptions(req *http.Request, defaults garbagecollector.RunOptions) (garbagecollector.RunOptions, error) {
+	options := garbagecollector.RunOptions{
+		GracePeriod: defaults.GracePeriod,
+	}
+
+	query := req.URL.Query()
+	if query.Get("delete") != "" {
+		deleteOrphans, err := strconv.ParseBool(query.Get("delete"))
+		if err != nil {
+			return options, err
+		}
+
+		options.Delete = deleteOrphans
+	}
+	if query.Get("gracePeriod") != "" {
+		gracePeriod, err := strconv.ParseInt(query.Get("gracePeriod"), 10, 64)
+		if err != nil {
+			return options, err
+		}
+
+		options.GracePeriod = time.Duration(gracePeriod) * time.Second
+	}
+
+	return options, nil
+}
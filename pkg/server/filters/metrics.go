@@ -160,7 +160,7 @@ func rewriteStats(ctx context.Context, data []byte, vClient client.Client) ([]by
 	newPods := []statsv1alpha1.PodStats{}
 	for _, pod := range stats.Pods {
 		// search if we can find the pod by name in the virtual cluster
-		podList := &corev1.PodList{}
+		podList := clienthelper.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Pod"))
 		err := vClient.List(ctx, podList, client.MatchingFields{constants.IndexByPhysicalName: pod.PodRef.Namespace + "/" + pod.PodRef.Name})
 		if err != nil {
 			return nil, err
@@ -179,13 +179,17 @@ func rewriteStats(ctx context.Context, data []byte, vClient client.Client) ([]by
 		newVolumes := []statsv1alpha1.VolumeStats{}
 		for _, volume := range pod.VolumeStats {
 			if volume.PVCRef != nil {
-				vPVC := &corev1.PersistentVolumeClaim{}
-				err = clienthelper.GetByIndex(ctx, vClient, vPVC, constants.IndexByPhysicalName, volume.PVCRef.Namespace+"/"+volume.PVCRef.Name)
+				pvcList := clienthelper.NewMetadataList(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+				err = vClient.List(ctx, pvcList, client.MatchingFields{constants.IndexByPhysicalName: volume.PVCRef.Namespace + "/" + volume.PVCRef.Name})
 				if err != nil {
 					return nil, err
+				} else if len(pvcList.Items) == 0 {
+					return nil, kerrors.NewNotFound(corev1.Resource("persistentvolumeclaims"), volume.PVCRef.Namespace+"/"+volume.PVCRef.Name)
+				} else if len(pvcList.Items) > 1 {
+					return nil, kerrors.NewConflict(corev1.Resource("persistentvolumeclaims"), volume.PVCRef.Namespace+"/"+volume.PVCRef.Name, fmt.Errorf("more than 1 object with the value"))
 				}
-				volume.PVCRef.Name = vPVC.Name
-				volume.PVCRef.Namespace = vPVC.Namespace
+				volume.PVCRef.Name = pvcList.Items[0].Name
+				volume.PVCRef.Namespace = pvcList.Items[0].Namespace
 			}
 
 			newVolumes = append(newVolumes, volume)
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/server/handler"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
@@ -249,10 +250,10 @@ type MetricsServerProxy struct {
 	responseWriter http.ResponseWriter
 	resourceType   string
 
-	podsInNamespace      []corev1.Pod
+	podsInNamespace      []metav1.PartialObjectMetadata
 	verb                 string
 	tableFormatRequested bool
-	nodesInVcluster      []corev1.Node
+	nodesInVcluster      []metav1.PartialObjectMetadata
 
 	client client.Client
 }
@@ -326,7 +327,7 @@ func (p *MetricsServerProxy) HandleRequest() {
 func (p *MetricsServerProxy) filterVirtualNodes(data []byte) ([]byte, error) {
 	var newData []byte
 
-	virtualNodeMap := make(map[string]corev1.Node)
+	virtualNodeMap := make(map[string]metav1.PartialObjectMetadata)
 	for _, node := range p.nodesInVcluster {
 		virtualNodeMap[node.Name] = node
 	}
@@ -516,8 +517,8 @@ func (p *MetricsServerProxy) rewritePodMetricsListData(data []byte) ([]byte, err
 }
 
 // returns the types.NamespacedName list of pods for the given namespace
-func getVirtualPodObjectsInNamespace(ctx context.Context, vClient client.Client, namespace string) ([]corev1.Pod, error) {
-	podList := &corev1.PodList{}
+func getVirtualPodObjectsInNamespace(ctx context.Context, vClient client.Client, namespace string) ([]metav1.PartialObjectMetadata, error) {
+	podList := clienthelper.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Pod"))
 
 	err := vClient.List(ctx, podList, &client.ListOptions{
 		Namespace: namespace,
@@ -529,8 +530,8 @@ func getVirtualPodObjectsInNamespace(ctx context.Context, vClient client.Client,
 	return podList.Items, nil
 }
 
-func getVirtualNodes(ctx context.Context, vClient client.Client) ([]corev1.Node, error) {
-	nodeList := &corev1.NodeList{}
+func getVirtualNodes(ctx context.Context, vClient client.Client) ([]metav1.PartialObjectMetadata, error) {
+	nodeList := clienthelper.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Node"))
 
 	err := vClient.List(ctx, nodeList)
 	if err != nil {
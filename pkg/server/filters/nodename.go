@@ -7,6 +7,7 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes/nodeservice"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog/v2"
@@ -40,7 +41,7 @@ func nodeNameFromHost(req *http.Request, currentNamespace string, fakeKubeletIPs
 	splitted := strings.Split(req.Host, ":")
 	if len(splitted) == 2 {
 		hostname := splitted[0]
-		nodeList := &corev1.NodeList{}
+		nodeList := clienthelper.NewMetadataList(corev1.SchemeGroupVersion.WithKind("Node"))
 		err := virtualClient.List(req.Context(), nodeList, client.MatchingFields{constants.IndexByHostName: hostname})
 		if err != nil && !errors.IsNotFound(err) {
 			klog.Error(err, "couldn't fetch nodename for hostname")
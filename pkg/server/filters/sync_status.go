@@ -0,0 +1,37 @@
+package filters
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/syncstatus"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithSyncStatus serves the virtual objects that currently fail to sync or are paused to virtual cluster admins
+func WithSyncStatus(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != syncstatus.Path {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok || !isAdmin(userInfo) {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return
+		} else if req.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		out, err := json.MarshalIndent(syncstatus.Default.Report(), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	})
+}
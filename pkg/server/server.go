@@ -18,11 +18,14 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes/nodeservice"
+	"github.com/loft-sh/vcluster/pkg/server/activity"
 	"github.com/loft-sh/vcluster/pkg/server/cert"
 	"github.com/loft-sh/vcluster/pkg/server/filters"
 	"github.com/loft-sh/vcluster/pkg/server/handler"
 	servertypes "github.com/loft-sh/vcluster/pkg/server/types"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/cachetransform"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"github.com/loft-sh/vcluster/pkg/util/serverhelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -117,22 +120,24 @@ func NewServer(ctx *context2.ControllerContext, requestHeaderCaFile, clientCaFil
 	if err != nil {
 		return nil, err
 	}
+	// the server only needs to map host to virtual names for pods, persistent volume claims and nodes,
+	// so the virtual cache only keeps their metadata
 	cachedVirtualClient, err := createCachedClient(ctx.Context, virtualConfig, corev1.NamespaceAll, uncachedVirtualClient.RESTMapper(), uncachedVirtualClient.Scheme(), func(cache cache.Cache) error {
-		err := cache.IndexField(ctx.Context, &corev1.PersistentVolumeClaim{}, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
+		err := cache.IndexField(ctx.Context, clienthelper.NewMetadataObject(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")), constants.IndexByPhysicalName, func(rawObj client.Object) []string {
 			return []string{translate.Default.PhysicalNamespace(rawObj.GetNamespace()) + "/" + translate.Default.PhysicalName(rawObj.GetName(), rawObj.GetNamespace())}
 		})
 		if err != nil {
 			return err
 		}
 
-		err = cache.IndexField(ctx.Context, &corev1.Node{}, constants.IndexByHostName, func(rawObj client.Object) []string {
+		err = cache.IndexField(ctx.Context, clienthelper.NewMetadataObject(corev1.SchemeGroupVersion.WithKind("Node")), constants.IndexByHostName, func(rawObj client.Object) []string {
 			return []string{nodes.GetNodeHost(rawObj.GetName()), nodes.GetNodeHostLegacy(rawObj.GetName(), ctx.CurrentNamespace)}
 		})
 		if err != nil {
 			return err
 		}
 
-		err = cache.IndexField(ctx.Context, &corev1.Pod{}, constants.IndexByPhysicalName, func(rawObj client.Object) []string {
+		err = cache.IndexField(ctx.Context, clienthelper.NewMetadataObject(corev1.SchemeGroupVersion.WithKind("Pod")), constants.IndexByPhysicalName, func(rawObj client.Object) []string {
 			return []string{translate.Default.PhysicalNamespace(rawObj.GetNamespace()) + "/" + translate.Default.PhysicalName(rawObj.GetName(), rawObj.GetNamespace())}
 		})
 		if err != nil {
@@ -218,6 +223,18 @@ func NewServer(ctx *context2.ControllerContext, requestHeaderCaFile, clientCaFil
 	}
 	h = filters.WithFakeKubelet(h, localConfig, cachedVirtualClient)
 	h = filters.WithK3sConnect(h)
+	h = filters.WithGarbageCollector(h)
+	if !ctx.Options.DisableSyncStatus {
+		h = filters.WithSyncStatus(h)
+	}
+
+	if len(ctx.Options.DryRunSyncers) > 0 {
+		h = filters.WithDryRunReport(h)
+	}
+
+	if ctx.Options.SleepAfter > 0 || ctx.Options.SleepSchedule != "" {
+		h = filters.WithActivityTracker(h, activity.Default)
+	}
 
 	if os.Getenv("DEBUG") == "true" {
 		h = filters.WithPprof(h)
@@ -299,9 +316,11 @@ func (s *Server) ServeOnListenerTLS(address string, port int, stopChan <-chan st
 func createCachedClient(ctx context.Context, config *rest.Config, namespace string, restMapper meta.RESTMapper, scheme *runtime.Scheme, registerIndices func(cache cache.Cache) error) (client.Client, error) {
 	// create the new cache
 	clientCache, err := cache.New(config, cache.Options{
-		Scheme:     scheme,
-		Mapper:     restMapper,
-		Namespaces: []string{namespace},
+		Scheme:           scheme,
+		Mapper:           restMapper,
+		Namespaces:       []string{namespace},
+		ByObject:         cachetransform.SecretsAndConfigMaps(cachetransform.StripUnusedFields),
+		DefaultTransform: cachetransform.StripManagedFields,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,619 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// PartitionFunc returns the cache options for the namespaced objects of a partition. The options
+// have to restrict the cache to exactly one namespace.
+type PartitionFunc func(options cache.Options, partition string) cache.Options
+
+// PartitionedCache only caches the namespaced objects of its current partitions, e.g. the virtual
+// namespaces a replica owns. Every partition has its own cache that is started when the partition
+// is added and stopped when it is removed, so the informers, event handlers and indices of the
+// syncers stay registered while the partitions change. Cluster scoped objects are cached as usual.
+type PartitionedCache struct {
+	partition PartitionFunc
+
+	config       *rest.Config
+	options      cache.Options
+	clusterCache cache.Cache
+
+	started chan struct{}
+
+	m          sync.Mutex
+	ctx        context.Context
+	desired    sets.Set[string]
+	partitions map[string]*partition
+	informers  map[informerKey]*partitionedInformer
+	indices    []index
+}
+
+type partition struct {
+	name      string
+	namespace string
+	cache     cache.Cache
+	informers map[informerKey]cache.Informer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type informerKey struct {
+	gvk     schema.GroupVersionKind
+	objType string
+}
+
+type index struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+var _ cache.Cache = &PartitionedCache{}
+
+// NewPartitionedCache creates a partitioned cache without any partitions, use New as the NewCache
+// function of the manager
+func NewPartitionedCache(partitionFunc PartitionFunc) *PartitionedCache {
+	return &PartitionedCache{
+		partition:  partitionFunc,
+		started:    make(chan struct{}),
+		desired:    sets.New[string](),
+		partitions: map[string]*partition{},
+		informers:  map[informerKey]*partitionedInformer{},
+	}
+}
+
+// New implements cache.NewCacheFunc
+func (c *PartitionedCache) New(config *rest.Config, options cache.Options) (cache.Cache, error) {
+	clusterOptions := options
+	clusterOptions.Namespaces = nil
+	clusterCache, err := cache.New(config, clusterOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	c.config = config
+	c.options = options
+	c.clusterCache = clusterCache
+	return c, nil
+}
+
+// SetPartitions starts the caches of the new partitions and stops the caches of the removed
+// ones. The caches are not started before the cache itself is started.
+func (c *PartitionedCache) SetPartitions(partitions []string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.desired = sets.New[string](partitions...)
+	if c.ctx == nil {
+		return nil
+	}
+
+	return c.syncPartitionsLocked()
+}
+
+// Partitions returns the sorted partitions that are currently cached
+func (c *PartitionedCache) Partitions() []string {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	partitions := []string{}
+	for name := range c.partitions {
+		partitions = append(partitions, name)
+	}
+	sort.Strings(partitions)
+	return partitions
+}
+
+func (c *PartitionedCache) syncPartitionsLocked() error {
+	for name, p := range c.partitions {
+		if !c.desired.Has(name) {
+			c.removePartitionLocked(p)
+		}
+	}
+
+	errs := []error{}
+	for _, name := range sets.List(c.desired) {
+		if _, ok := c.partitions[name]; ok {
+			continue
+		}
+
+		err := c.addPartitionLocked(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("add partition %s: %w", name, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// addPartitionLocked creates the cache of the partition with all known indices, informers and
+// event handlers. This doesn't block, as the cache isn't started until everything is registered.
+func (c *PartitionedCache) addPartitionLocked(name string) error {
+	options := c.partition(c.options, name)
+	if len(options.Namespaces) != 1 || options.Namespaces[0] == corev1.NamespaceAll {
+		return fmt.Errorf("partition has to be restricted to exactly one namespace")
+	}
+
+	partitionCache, err := cache.New(c.config, options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	p := &partition{
+		name:      name,
+		namespace: options.Namespaces[0],
+		cache:     partitionCache,
+		informers: map[informerKey]cache.Informer{},
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	for _, idx := range c.indices {
+		err = partitionCache.IndexField(ctx, idx.obj, idx.field, idx.extractValue)
+		if err != nil {
+			cancel()
+			return err
+		}
+	}
+	for key, informer := range c.informers {
+		i, err := partitionCache.GetInformer(ctx, informer.obj)
+		if err != nil {
+			cancel()
+			return err
+		}
+		for _, indexers := range informer.indexers {
+			err = i.AddIndexers(indexers)
+			if err != nil {
+				cancel()
+				return err
+			}
+		}
+		for _, handler := range informer.handlers {
+			registration, err := handler.addTo(i)
+			if err != nil {
+				cancel()
+				return err
+			}
+			handler.registrations[name] = registration
+		}
+		p.informers[key] = i
+	}
+
+	c.partitions[name] = p
+	go func() {
+		err := partitionCache.Start(ctx)
+		if err != nil {
+			klog.Errorf("Error running cache of partition %s: %v", name, err)
+		}
+	}()
+	return nil
+}
+
+func (c *PartitionedCache) removePartitionLocked(p *partition) {
+	p.cancel()
+	delete(c.partitions, p.name)
+	for _, informer := range c.informers {
+		for _, handler := range informer.handlers {
+			delete(handler.registrations, p.name)
+		}
+	}
+}
+
+// Start starts the cluster cache and the caches of the partitions until ctx is done
+func (c *PartitionedCache) Start(ctx context.Context) error {
+	c.m.Lock()
+	c.ctx = ctx
+	err := c.syncPartitionsLocked()
+	c.m.Unlock()
+	close(c.started)
+	if err != nil {
+		klog.Errorf("Error starting partitions: %v", err)
+	}
+
+	go func() {
+		err := c.clusterCache.Start(ctx)
+		if err != nil {
+			klog.Errorf("Error running cluster cache: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	return nil
+}
+
+// WaitForCacheSync waits for the cache to start and for the cluster cache and the caches of the
+// current partitions to sync. Partitions that are removed in the meantime count as synced.
+func (c *PartitionedCache) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-c.started:
+	case <-ctx.Done():
+		return false
+	}
+
+	for _, p := range c.partitionsFor(nil) {
+		waitCtx, cancel := context.WithCancel(ctx)
+		go func(p *partition) {
+			select {
+			case <-p.ctx.Done():
+			case <-waitCtx.Done():
+			}
+			cancel()
+		}(p)
+
+		synced := p.cache.WaitForCacheSync(waitCtx)
+		cancel()
+		if !synced && p.ctx.Err() == nil {
+			return false
+		}
+	}
+
+	return c.clusterCache.WaitForCacheSync(ctx)
+}
+
+// IndexField registers the index for all partitions, this has to happen before the partitions
+// are started
+func (c *PartitionedCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	namespaced, err := apiutil.IsObjectNamespaced(obj, c.options.Scheme, c.options.Mapper)
+	if err != nil {
+		return err
+	} else if !namespaced {
+		return c.clusterCache.IndexField(ctx, obj, field, extractValue)
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.indices = append(c.indices, index{obj: obj, field: field, extractValue: extractValue})
+	for _, p := range c.partitions {
+		err = p.cache.IndexField(p.ctx, obj, field, extractValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetInformer returns an informer that spans all current and future partitions
+func (c *PartitionedCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.options.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	namespaced, err := apiutil.IsGVKNamespaced(gvk, c.options.Mapper)
+	if err != nil {
+		return nil, err
+	} else if !namespaced {
+		return c.clusterCache.GetInformer(ctx, obj)
+	}
+
+	c.m.Lock()
+	key := informerKey{gvk: gvk, objType: fmt.Sprintf("%T", obj)}
+	informer, ok := c.informers[key]
+	if ok {
+		c.m.Unlock()
+		return informer, nil
+	}
+
+	informer = &partitionedInformer{cache: c, key: key, obj: obj.DeepCopyObject().(client.Object)}
+	c.informers[key] = informer
+	partitions := c.partitionsForLocked(nil)
+	c.m.Unlock()
+
+	// create the informer in the running partitions, which blocks until they are synced
+	for _, p := range partitions {
+		i, err := p.cache.GetInformer(p.ctx, informer.obj)
+		if err != nil {
+			if p.ctx.Err() != nil {
+				continue
+			}
+			return nil, err
+		}
+
+		c.m.Lock()
+		if c.partitions[p.name] == p {
+			p.informers[key] = i
+		}
+		c.m.Unlock()
+	}
+
+	return informer, nil
+}
+
+// GetInformerForKind returns an informer that spans all current and future partitions
+func (c *PartitionedCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	obj, err := c.options.Scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a client object", gvk.String())
+	}
+
+	return c.GetInformer(ctx, clientObj)
+}
+
+// Get returns the object from the partitions of its namespace. If the namespace is not part of
+// any partition, an error is returned instead of a not found, as the object might still exist.
+func (c *PartitionedCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	namespaced, err := apiutil.IsObjectNamespaced(obj, c.options.Scheme, c.options.Mapper)
+	if err != nil {
+		return err
+	} else if !namespaced {
+		return c.clusterCache.Get(ctx, key, obj, opts...)
+	}
+
+	namespace := key.Namespace
+	partitions := c.partitionsFor(&namespace)
+	if len(partitions) == 0 {
+		return fmt.Errorf("unable to get %s: namespace %s is not cached", key.String(), key.Namespace)
+	}
+
+	for _, p := range partitions {
+		err = p.cache.Get(ctx, key, obj, opts...)
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// List returns the objects of all partitions of the namespace or of all partitions if no namespace
+// is specified
+func (c *PartitionedCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, c.options.Scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	namespaced, err := apiutil.IsGVKNamespaced(gvk, c.options.Mapper)
+	if err != nil {
+		return err
+	} else if !namespaced {
+		return c.clusterCache.List(ctx, list, opts...)
+	}
+
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	var partitions []*partition
+	if listOpts.Namespace != corev1.NamespaceAll {
+		partitions = c.partitionsFor(&listOpts.Namespace)
+		if len(partitions) == 0 {
+			return fmt.Errorf("unable to list: namespace %s is not cached", listOpts.Namespace)
+		}
+	} else {
+		partitions = c.partitionsFor(nil)
+	}
+
+	listAccessor, err := apimeta.ListAccessor(list)
+	if err != nil {
+		return err
+	}
+	allItems, err := apimeta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	limitSet := listOpts.Limit > 0
+	resourceVersion := ""
+	for _, p := range partitions {
+		partitionList := list.DeepCopyObject().(client.ObjectList)
+		err = p.cache.List(ctx, partitionList, &listOpts)
+		if err != nil {
+			return err
+		}
+		items, err := apimeta.ExtractList(partitionList)
+		if err != nil {
+			return err
+		}
+		accessor, err := apimeta.ListAccessor(partitionList)
+		if err != nil {
+			return err
+		}
+		allItems = append(allItems, items...)
+		resourceVersion = accessor.GetResourceVersion()
+		if limitSet {
+			listOpts.Limit -= int64(len(items))
+			if listOpts.Limit <= 0 {
+				break
+			}
+		}
+	}
+	listAccessor.SetResourceVersion(resourceVersion)
+
+	return apimeta.SetList(list, allItems)
+}
+
+// partitionsFor returns the current partitions sorted by name, optionally only the ones of the namespace
+func (c *PartitionedCache) partitionsFor(namespace *string) []*partition {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.partitionsForLocked(namespace)
+}
+
+func (c *PartitionedCache) partitionsForLocked(namespace *string) []*partition {
+	partitions := []*partition{}
+	for _, p := range c.partitions {
+		if namespace == nil || p.namespace == *namespace {
+			partitions = append(partitions, p)
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].name < partitions[j].name
+	})
+
+	return partitions
+}
+
+// partitionedInformer registers event handlers and indexers in the informers of all current
+// partitions and the partitions that are added later on
+type partitionedInformer struct {
+	cache *PartitionedCache
+	key   informerKey
+	obj   client.Object
+
+	handlers []*handlerRegistration
+	indexers []toolscache.Indexers
+}
+
+type handlerRegistration struct {
+	cache        *PartitionedCache
+	handler      toolscache.ResourceEventHandler
+	resyncPeriod *time.Duration
+
+	// registrations of the handler by partition
+	registrations map[string]toolscache.ResourceEventHandlerRegistration
+}
+
+func (h *handlerRegistration) addTo(informer cache.Informer) (toolscache.ResourceEventHandlerRegistration, error) {
+	if h.resyncPeriod != nil {
+		return informer.AddEventHandlerWithResyncPeriod(h.handler, *h.resyncPeriod)
+	}
+
+	return informer.AddEventHandler(h.handler)
+}
+
+// HasSynced returns true if the handler is synced in all current partitions
+func (h *handlerRegistration) HasSynced() bool {
+	h.cache.m.Lock()
+	defer h.cache.m.Unlock()
+
+	for _, registration := range h.registrations {
+		if !registration.HasSynced() {
+			return false
+		}
+	}
+
+	return true
+}
+
+var _ cache.Informer = &partitionedInformer{}
+
+func (i *partitionedInformer) AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.addEventHandler(&handlerRegistration{cache: i.cache, handler: handler})
+}
+
+func (i *partitionedInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.addEventHandler(&handlerRegistration{cache: i.cache, handler: handler, resyncPeriod: &resyncPeriod})
+}
+
+// addEventHandler records the handler for future partitions and adds it to the current ones
+func (i *partitionedInformer) addEventHandler(handler *handlerRegistration) (toolscache.ResourceEventHandlerRegistration, error) {
+	handler.registrations = map[string]toolscache.ResourceEventHandlerRegistration{}
+
+	i.cache.m.Lock()
+	i.handlers = append(i.handlers, handler)
+	partitions := i.cache.partitionsForLocked(nil)
+	i.cache.m.Unlock()
+
+	for _, p := range partitions {
+		informer, err := p.cache.GetInformer(p.ctx, i.obj)
+		if err != nil {
+			if p.ctx.Err() != nil {
+				continue
+			}
+			return nil, err
+		}
+		registration, err := handler.addTo(informer)
+		if err != nil {
+			return nil, err
+		}
+
+		i.cache.m.Lock()
+		if i.cache.partitions[p.name] == p {
+			handler.registrations[p.name] = registration
+			p.informers[i.key] = informer
+		}
+		i.cache.m.Unlock()
+	}
+
+	return handler, nil
+}
+
+func (i *partitionedInformer) RemoveEventHandler(registration toolscache.ResourceEventHandlerRegistration) error {
+	handler, ok := registration.(*handlerRegistration)
+	if !ok {
+		return fmt.Errorf("registration was not returned by the partitioned informer")
+	}
+
+	i.cache.m.Lock()
+	defer i.cache.m.Unlock()
+
+	for index, h := range i.handlers {
+		if h == handler {
+			i.handlers = append(i.handlers[:index], i.handlers[index+1:]...)
+			break
+		}
+	}
+	for name, r := range handler.registrations {
+		p, ok := i.cache.partitions[name]
+		if !ok || p.informers[i.key] == nil {
+			continue
+		}
+
+		err := p.informers[i.key].RemoveEventHandler(r)
+		if err != nil {
+			return err
+		}
+	}
+	handler.registrations = map[string]toolscache.ResourceEventHandlerRegistration{}
+	return nil
+}
+
+func (i *partitionedInformer) AddIndexers(indexers toolscache.Indexers) error {
+	i.cache.m.Lock()
+	defer i.cache.m.Unlock()
+
+	i.indexers = append(i.indexers, indexers)
+	for _, p := range i.cache.partitions {
+		informer, ok := p.informers[i.key]
+		if !ok {
+			continue
+		}
+
+		err := informer.AddIndexers(indexers)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HasSynced returns true if the informers of all current partitions are synced
+func (i *partitionedInformer) HasSynced() bool {
+	i.cache.m.Lock()
+	defer i.cache.m.Unlock()
+
+	for _, p := range i.cache.partitions {
+		informer, ok := p.informers[i.key]
+		if !ok || !informer.HasSynced() {
+			return false
+		}
+	}
+
+	return true
+}
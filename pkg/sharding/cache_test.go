@@ -0,0 +1,205 @@
+package sharding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testLabel = "vcluster.loft.sh/namespace"
+
+func TestPartitionedCache(t *testing.T) {
+	configMaps := []corev1.ConfigMap{
+		newConfigMap("a", "a", nil),
+		newConfigMap("b", "b", nil),
+		newConfigMap("c", "c", nil),
+		newConfigMap("target", "from-a", map[string]string{testLabel: "a"}),
+		newConfigMap("target", "from-b", map[string]string{testLabel: "b"}),
+		newConfigMap("target", "unlabeled", nil),
+	}
+	server := httptest.NewServer(configMapServer(configMaps))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// partitions are either a namespace or a label of the target namespace
+	partitionedCache := NewPartitionedCache(func(options cache.Options, partition string) cache.Options {
+		if !strings.HasPrefix(partition, "target-") {
+			options.Namespaces = []string{partition}
+			return options
+		}
+
+		options.Namespaces = []string{"target"}
+		requirement, _ := labels.NewRequirement(testLabel, selection.DoesNotExist, nil)
+		options.DefaultLabelSelector = labels.NewSelector().Add(*requirement)
+		if partition != "target-" {
+			options.DefaultLabelSelector = labels.SelectorFromSet(labels.Set{testLabel: strings.TrimPrefix(partition, "target-")})
+		}
+		return options
+	})
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	c, err := partitionedCache.New(&rest.Config{Host: server.URL}, cache.Options{Scheme: scheme.Scheme, Mapper: mapper})
+	assert.NilError(t, err)
+	assert.NilError(t, c.IndexField(ctx, &corev1.ConfigMap{}, "name", func(obj client.Object) []string {
+		return []string{obj.GetName()}
+	}))
+	assert.NilError(t, partitionedCache.SetPartitions([]string{"a", "target-a"}))
+
+	// the event handler receives the objects of all partitions
+	events := &eventRecorder{}
+	informer, err := c.GetInformer(ctx, &corev1.ConfigMap{})
+	assert.NilError(t, err)
+	_, err = informer.AddEventHandler(events)
+	assert.NilError(t, err)
+
+	go func() {
+		_ = c.Start(ctx)
+	}()
+	assert.Assert(t, c.WaitForCacheSync(ctx))
+	assert.Assert(t, informer.HasSynced())
+	assert.DeepEqual(t, partitionedCache.Partitions(), []string{"a", "target-a"})
+	events.wait(t, "a/a", "target/from-a")
+
+	// only the objects of the partitions are cached
+	configMap := &corev1.ConfigMap{}
+	assert.NilError(t, c.Get(ctx, client.ObjectKey{Namespace: "a", Name: "a"}, configMap))
+	assert.NilError(t, c.Get(ctx, client.ObjectKey{Namespace: "target", Name: "from-a"}, configMap))
+	err = c.Get(ctx, client.ObjectKey{Namespace: "target", Name: "from-b"}, configMap)
+	assert.Assert(t, kerrors.IsNotFound(err))
+	err = c.Get(ctx, client.ObjectKey{Namespace: "b", Name: "b"}, configMap)
+	assert.Assert(t, err != nil && !kerrors.IsNotFound(err), "an uncached namespace must not be reported as not found")
+	assert.DeepEqual(t, listNames(ctx, t, c), []string{"a/a", "target/from-a"})
+
+	// the partitions change, the registered handlers and indices are kept
+	assert.NilError(t, partitionedCache.SetPartitions([]string{"b", "target-", "target-b"}))
+	assert.Assert(t, c.WaitForCacheSync(ctx))
+	assert.DeepEqual(t, partitionedCache.Partitions(), []string{"b", "target-", "target-b"})
+	events.wait(t, "a/a", "target/from-a", "b/b", "target/from-b", "target/unlabeled")
+	assert.NilError(t, c.Get(ctx, client.ObjectKey{Namespace: "b", Name: "b"}, configMap))
+	assert.NilError(t, c.Get(ctx, client.ObjectKey{Namespace: "target", Name: "unlabeled"}, configMap))
+	err = c.Get(ctx, client.ObjectKey{Namespace: "a", Name: "a"}, configMap)
+	assert.Assert(t, err != nil && !kerrors.IsNotFound(err))
+	assert.DeepEqual(t, listNames(ctx, t, c), []string{"b/b", "target/unlabeled", "target/from-b"})
+	assert.DeepEqual(t, listNames(ctx, t, c, client.InNamespace("target"), client.MatchingFields{"name": "from-b"}), []string{"target/from-b"})
+}
+
+func newConfigMap(namespace, name string, labels map[string]string) corev1.ConfigMap {
+	return corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            name,
+			Labels:          labels,
+			ResourceVersion: "1",
+		},
+	}
+}
+
+// configMapServer answers list requests for the config maps and keeps watch requests open
+func configMapServer(configMaps []corev1.ConfigMap) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if parts[len(parts)-1] != "configmaps" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("watch") == "true" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+
+		selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		list := &corev1.ConfigMapList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"},
+			ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		}
+		for _, configMap := range configMaps {
+			if (len(parts) < 4 || parts[3] == configMap.Namespace) && selector.Matches(labels.Set(configMap.Labels)) {
+				list.Items = append(list.Items, configMap)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	}
+}
+
+func listNames(ctx context.Context, t *testing.T, c cache.Cache, opts ...client.ListOption) []string {
+	list := &corev1.ConfigMapList{}
+	assert.NilError(t, c.List(ctx, list, opts...))
+
+	names := []string{}
+	for _, configMap := range list.Items {
+		names = append(names, configMap.Namespace+"/"+configMap.Name)
+	}
+	return names
+}
+
+type eventRecorder struct {
+	m     sync.Mutex
+	added []string
+}
+
+func (e *eventRecorder) OnAdd(obj interface{}, _ bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	configMap := obj.(*corev1.ConfigMap)
+	e.added = append(e.added, configMap.Namespace+"/"+configMap.Name)
+}
+
+func (e *eventRecorder) OnUpdate(_, _ interface{}) {}
+
+func (e *eventRecorder) OnDelete(_ interface{}) {}
+
+// wait waits until exactly the objects were added
+func (e *eventRecorder) wait(t *testing.T, added ...string) {
+	var actual []string
+	for i := 0; i < 100; i++ {
+		e.m.Lock()
+		actual = append([]string{}, e.added...)
+		e.m.Unlock()
+		if len(actual) >= len(added) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	assert.DeepEqual(t, sortedCopy(actual), sortedCopy(added))
+}
+
+func sortedCopy(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+var _ toolscache.ResourceEventHandler = &eventRecorder{}
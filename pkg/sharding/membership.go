@@ -0,0 +1,115 @@
+package sharding
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+)
+
+// MemberLabel marks the leases of the replicas that take part in the sharding of a virtual cluster
+const MemberLabel = "vcluster.loft.sh/shard-member"
+
+// Membership maintains the lease of a replica and finds the other live replicas through their leases
+type Membership struct {
+	Client    kubernetes.Interface
+	Namespace string
+
+	// Name is the name of the virtual cluster the replicas belong to
+	Name string
+
+	// Identity distinguishes this replica from the others
+	Identity string
+
+	// LeaseDuration is how long a lease is valid after it was renewed
+	LeaseDuration time.Duration
+
+	now func() time.Time
+}
+
+// Renew creates or renews the lease of this replica
+func (m *Membership) Renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(m.getNow())
+	lease, err := m.Client.CoordinationV1().Leases(m.Namespace).Get(ctx, m.leaseName(), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		_, err = m.Client.CoordinationV1().Leases(m.Namespace).Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.leaseName(),
+				Namespace: m.Namespace,
+				Labels: map[string]string{
+					MemberLabel: m.Name,
+				},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(m.Identity),
+				LeaseDurationSeconds: pointer.Int32(int32(m.LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	lease.Spec.HolderIdentity = pointer.String(m.Identity)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(m.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	_, err = m.Client.CoordinationV1().Leases(m.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// Members returns the sorted identities of all replicas whose lease is not expired
+func (m *Membership) Members(ctx context.Context) ([]string, error) {
+	leases, err := m.Client.CoordinationV1().Leases(m.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: MemberLabel + "=" + m.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.getNow()
+	members := []string{}
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+
+		expires := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if now.Before(expires) {
+			members = append(members, *lease.Spec.HolderIdentity)
+		}
+	}
+	sort.Strings(members)
+
+	return members, nil
+}
+
+// Leave deletes the lease of this replica, so the other replicas take over its namespaces
+// without waiting for the lease to expire
+func (m *Membership) Leave(ctx context.Context) error {
+	err := m.Client.CoordinationV1().Leases(m.Namespace).Delete(ctx, m.leaseName(), metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Membership) leaseName() string {
+	return translate.SafeConcatName("vcluster", m.Name, "shard", m.Identity)
+}
+
+func (m *Membership) getNow() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,76 @@
+package sharding
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes is the number of points every member gets on the ring, which spreads
+// the keys evenly across the members
+const virtualNodes = 128
+
+// Ring is a consistent hash ring that assigns keys to members. If a member joins or leaves,
+// only the keys of that member are moved.
+type Ring struct {
+	members []string
+	points  []uint32
+	owners  map[uint32]string
+}
+
+// NewRing creates a new ring for the given members
+func NewRing(members []string) *Ring {
+	r := &Ring{
+		owners: map[uint32]string{},
+	}
+	for _, member := range members {
+		r.members = append(r.members, member)
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+
+			// on collisions the lexically smaller member wins, so all members build the same ring
+			if existing, ok := r.owners[point]; ok {
+				if existing < member {
+					continue
+				}
+			} else {
+				r.points = append(r.points, point)
+			}
+			r.owners[point] = member
+		}
+	}
+	sort.Strings(r.members)
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i] < r.points[j]
+	})
+
+	return r
+}
+
+// Members returns the sorted members of the ring
+func (r *Ring) Members() []string {
+	return r.members
+}
+
+// Owner returns the member the key belongs to or an empty string if the ring has no members
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	point := hash(key)
+	idx := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i] >= point
+	})
+	if idx == len(r.points) {
+		idx = 0
+	}
+
+	return r.owners[r.points[idx]]
+}
+
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,265 @@
+package sharding
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Sharder distributes the virtual namespaces across all live replicas through a consistent
+// hash ring and keeps track of the namespaces this replica owns. Only the namespaces that
+// move are handed over when a replica joins or leaves or a namespace is created or deleted.
+type Sharder struct {
+	Membership    *Membership
+	VirtualClient kubernetes.Interface
+	Log           loghelper.Logger
+
+	// Interval is how often the lease is renewed and the namespaces are rebalanced
+	Interval time.Duration
+
+	// HandoverDelay is how long a replica waits before it syncs a namespace that another live
+	// replica owned before, so that replica has released the namespace in the meantime
+	HandoverDelay time.Duration
+
+	// OwnedChanged is called with the owned namespaces whenever they change, before Owns reports
+	// the change. It must not call the sharder.
+	OwnedChanged func(owned []string)
+
+	m         sync.Mutex
+	lastRenew time.Time
+	ring      *Ring
+	owned     sets.Set[string]
+	pending   map[string]time.Time
+	watchers  []chan event.GenericEvent
+}
+
+// Run renews the lease and rebalances the namespaces until ctx is done
+func (s *Sharder) Run(ctx context.Context) {
+	s.renew(ctx)
+	go func() {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.renew(ctx)
+			}
+		}
+	}()
+	wait.UntilWithContext(ctx, s.rebalance, s.Interval)
+
+	// release the namespaces and give them to the other replicas
+	s.release()
+	leaveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := s.Membership.Leave(leaveCtx)
+	if err != nil {
+		s.Log.Errorf("error leaving shard membership: %v", err)
+	}
+}
+
+// Owns returns true if this replica syncs the virtual namespace. As soon as the lease of this
+// replica expires, the other replicas take over, so it doesn't own any namespace anymore.
+func (s *Sharder) Owns(namespace string) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.renewedLocked() && s.owned.Has(namespace)
+}
+
+// Owned returns the sorted virtual namespaces this replica currently syncs
+func (s *Sharder) Owned() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return sets.List(s.owned)
+}
+
+// Watch returns a channel that receives a namespace event whenever this replica starts to own
+// a virtual namespace
+func (s *Sharder) Watch() <-chan event.GenericEvent {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	watcher := make(chan event.GenericEvent, 100)
+	s.watchers = append(s.watchers, watcher)
+	return watcher
+}
+
+func (s *Sharder) renew(ctx context.Context) {
+	// the renew time of the lease, the other replicas consider the lease expired from there on
+	renewTime := s.Membership.getNow()
+	err := s.Membership.Renew(ctx)
+	if err != nil {
+		s.Log.Errorf("error renewing shard lease: %v", err)
+		return
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.lastRenew = renewTime
+}
+
+func (s *Sharder) rebalance(ctx context.Context) {
+	// if we couldn't renew our lease, the other replicas will take over our namespaces
+	if !s.renewed() {
+		if len(s.Owned()) > 0 {
+			s.Log.Infof("shard lease expired, stop syncing %d namespaces", len(s.Owned()))
+		}
+		s.release()
+		return
+	}
+
+	members, err := s.Membership.Members(ctx)
+	if err != nil {
+		s.Log.Errorf("error retrieving shard members: %v", err)
+		return
+	}
+
+	namespaceList, err := s.VirtualClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		s.Log.Errorf("error listing virtual namespaces: %v", err)
+		return
+	}
+	namespaces := []string{}
+	for _, namespace := range namespaceList.Items {
+		namespaces = append(namespaces, namespace.Name)
+	}
+
+	ring := NewRing(members)
+	target := sets.New[string](OwnedNamespaces(ring, s.Membership.Identity, namespaces)...)
+	now := s.Membership.getNow()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.owned == nil {
+		s.owned = sets.New[string]()
+		s.pending = map[string]time.Time{}
+	}
+
+	// release the namespaces that moved to another replica right away
+	released := s.owned.Difference(target)
+	s.owned = s.owned.Difference(released)
+	for namespace := range s.pending {
+		if !target.Has(namespace) {
+			delete(s.pending, namespace)
+		}
+	}
+
+	// take over the new namespaces, but give a live previous owner time to release them first
+	acquired := []string{}
+	for _, namespace := range sets.List(target.Difference(s.owned)) {
+		if _, ok := s.pending[namespace]; !ok {
+			s.pending[namespace] = now
+			if s.hasLivePreviousOwner(namespace, members) {
+				s.pending[namespace] = now.Add(s.HandoverDelay)
+			}
+		}
+		if now.Before(s.pending[namespace]) {
+			continue
+		}
+
+		delete(s.pending, namespace)
+		s.owned.Insert(namespace)
+		acquired = append(acquired, namespace)
+	}
+	s.ring = ring
+
+	if released.Len() > 0 || len(acquired) > 0 {
+		s.Log.Infof("rebalanced virtual namespaces across %d replicas, this replica released %d, acquired %d and syncs %d of %d namespaces", len(members), released.Len(), len(acquired), s.owned.Len(), len(namespaces))
+		s.ownedChanged()
+	}
+	s.notify(acquired)
+}
+
+// hasLivePreviousOwner returns true if another replica of the members owned the namespace in the
+// last rebalance or if there was no rebalance yet, in which case the previous owner is unknown
+func (s *Sharder) hasLivePreviousOwner(namespace string, members []string) bool {
+	if s.ring == nil {
+		return len(members) > 1
+	}
+
+	previousOwner := s.ring.Owner(namespace)
+	if previousOwner == s.Membership.Identity {
+		return false
+	}
+	for _, member := range members {
+		if member == previousOwner {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notify sends the acquired namespaces to the watchers without blocking the rebalance
+func (s *Sharder) notify(namespaces []string) {
+	if len(namespaces) == 0 {
+		return
+	}
+
+	for _, watcher := range s.watchers {
+		go func(watcher chan event.GenericEvent) {
+			for _, namespace := range namespaces {
+				watcher <- event.GenericEvent{Object: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}}
+			}
+		}(watcher)
+	}
+}
+
+func (s *Sharder) renewed() bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.renewedLocked()
+}
+
+func (s *Sharder) renewedLocked() bool {
+	return !s.lastRenew.IsZero() && s.Membership.getNow().Sub(s.lastRenew) < s.Membership.LeaseDuration
+}
+
+// release gives up all namespaces, the next rebalance with a renewed lease acquires them again
+func (s *Sharder) release() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	owned := s.owned.Len() > 0
+	s.owned = nil
+	s.pending = nil
+	s.ring = nil
+	if owned {
+		s.ownedChanged()
+	}
+}
+
+// ownedChanged calls OwnedChanged, the lock has to be held
+func (s *Sharder) ownedChanged() {
+	if s.OwnedChanged != nil {
+		s.OwnedChanged(sets.List(s.owned))
+	}
+}
+
+// OwnedNamespaces returns the sorted namespaces the ring assigns to the member
+func OwnedNamespaces(ring *Ring, member string, namespaces []string) []string {
+	owned := []string{}
+	for _, namespace := range namespaces {
+		if ring.Owner(namespace) == member {
+			owned = append(owned, namespace)
+		}
+	}
+	sort.Strings(owned)
+
+	return owned
+}
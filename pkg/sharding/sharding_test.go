@@ -0,0 +1,161 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestRing(t *testing.T) {
+	namespaces := []string{}
+	for i := 0; i < 1000; i++ {
+		namespaces = append(namespaces, fmt.Sprintf("namespace-%d", i))
+	}
+
+	// all namespaces are assigned and spread across the members
+	ring := NewRing([]string{"a", "b", "c"})
+	total := 0
+	for _, member := range ring.Members() {
+		owned := OwnedNamespaces(ring, member, namespaces)
+		assert.Assert(t, len(owned) > 200, "member %s owns only %d namespaces", member, len(owned))
+		total += len(owned)
+	}
+	assert.Equal(t, total, len(namespaces))
+
+	// the ring doesn't depend on the order of the members
+	otherRing := NewRing([]string{"c", "a", "b"})
+	for _, namespace := range namespaces {
+		assert.Equal(t, ring.Owner(namespace), otherRing.Owner(namespace))
+	}
+
+	// if a member leaves, only its namespaces move
+	smallerRing := NewRing([]string{"a", "b"})
+	for _, namespace := range namespaces {
+		if ring.Owner(namespace) != "c" {
+			assert.Equal(t, smallerRing.Owner(namespace), ring.Owner(namespace))
+		}
+	}
+
+	assert.Equal(t, NewRing(nil).Owner("default"), "")
+}
+
+func TestSharder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	hostClient := fake.NewSimpleClientset()
+	namespaces := []string{}
+	virtualClient := fake.NewSimpleClientset()
+	for i := 0; i < 10; i++ {
+		namespace := fmt.Sprintf("namespace-%d", i)
+		namespaces = append(namespaces, namespace)
+		_, err := virtualClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	newSharder := func(identity string) *Sharder {
+		return &Sharder{
+			Membership: &Membership{
+				Client:        hostClient,
+				Namespace:     "vcluster",
+				Name:          "vcluster",
+				Identity:      identity,
+				LeaseDuration: time.Minute,
+				now: func() time.Time {
+					return now
+				},
+			},
+			VirtualClient: virtualClient,
+			Log:           loghelper.New("sharder-test"),
+			Interval:      time.Second,
+			HandoverDelay: 2 * time.Second,
+		}
+	}
+
+	// a single replica owns all namespaces right away
+	sharder := newSharder("a")
+	var changed []string
+	sharder.OwnedChanged = func(owned []string) {
+		changed = owned
+	}
+	acquired := sharder.Watch()
+	sharder.renew(ctx)
+	sharder.rebalance(ctx)
+	assert.DeepEqual(t, sharder.Owned(), namespaces)
+	assert.DeepEqual(t, changed, namespaces)
+	assert.DeepEqual(t, receive(t, acquired, len(namespaces)), namespaces)
+	assert.Assert(t, sharder.Owns("namespace-0"))
+	assert.Assert(t, !sharder.Owns("other"))
+
+	// nothing changes, so no namespace is acquired again
+	sharder.rebalance(ctx)
+	assert.Equal(t, len(acquired), 0)
+
+	// another replica joins, the first replica releases the namespaces of the other replica right away
+	other := newSharder("b")
+	otherAcquired := other.Watch()
+	other.renew(ctx)
+	ring := NewRing([]string{"a", "b"})
+	otherNamespaces := OwnedNamespaces(ring, "b", namespaces)
+	assert.Assert(t, len(otherNamespaces) > 0)
+	sharder.rebalance(ctx)
+	assert.DeepEqual(t, sharder.Owned(), OwnedNamespaces(ring, "a", namespaces))
+	assert.DeepEqual(t, changed, OwnedNamespaces(ring, "a", namespaces))
+	assert.Assert(t, !sharder.Owns(otherNamespaces[0]))
+
+	// the other replica waits for the handover delay before it takes over
+	other.rebalance(ctx)
+	assert.Equal(t, len(other.Owned()), 0)
+	now = now.Add(time.Second)
+	other.rebalance(ctx)
+	assert.Equal(t, len(other.Owned()), 0)
+	now = now.Add(time.Second)
+	other.rebalance(ctx)
+	assert.DeepEqual(t, other.Owned(), otherNamespaces)
+	assert.DeepEqual(t, receive(t, otherAcquired, len(otherNamespaces)), otherNamespaces)
+
+	// the other replica expires, its namespaces are taken over right away as it stopped syncing them
+	now = now.Add(50 * time.Second)
+	sharder.renew(ctx)
+	now = now.Add(20 * time.Second)
+	assert.Assert(t, !other.Owns(otherNamespaces[0]))
+	sharder.rebalance(ctx)
+	assert.DeepEqual(t, sharder.Owned(), namespaces)
+	assert.DeepEqual(t, receive(t, acquired, len(otherNamespaces)), otherNamespaces)
+
+	// our own lease expires, so we stop syncing even before the next rebalance
+	now = now.Add(time.Minute)
+	assert.Assert(t, !sharder.Owns("namespace-0"))
+	sharder.rebalance(ctx)
+	assert.Equal(t, len(sharder.Owned()), 0)
+	assert.Equal(t, len(changed), 0)
+
+	// leaving removes the lease
+	assert.NilError(t, sharder.Membership.Leave(ctx))
+	leases, err := hostClient.CoordinationV1().Leases("vcluster").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(leases.Items), 1)
+}
+
+// receive returns the sorted names of the next namespace events
+func receive(t *testing.T, events <-chan event.GenericEvent, count int) []string {
+	names := []string{}
+	for i := 0; i < count; i++ {
+		select {
+		case e := <-events:
+			names = append(names, e.Object.GetName())
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for namespace %d of %d", i+1, count)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,93 @@
+package cachetransform
+
+import (
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StripManagedFields removes the managed fields of an object before it is stored in a cache. vcluster never
+// reads them from the cache, but for objects such as pods they often take up more memory than the rest
+// of the object. Updates with empty managed fields keep the managed fields of the object in the cluster.
+func StripManagedFields(in interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(in)
+	if err != nil {
+		// tombstones and other non objects are stored as they are
+		return in, nil
+	}
+
+	accessor.SetManagedFields(nil)
+	return in, nil
+}
+
+// StripUnusedFields removes the managed fields and the last applied configuration annotation. kubectl apply
+// stores a copy of the whole object in the annotation, which doubles the size of secrets and config maps.
+// This must only be used for objects vcluster never updates from the cache, as an update would remove the
+// annotation from the object in the cluster.
+func StripUnusedFields(in interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(in)
+	if err != nil {
+		return in, nil
+	}
+
+	accessor.SetManagedFields(nil)
+	annotations := accessor.GetAnnotations()
+	if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		accessor.SetAnnotations(annotations)
+	}
+
+	return in, nil
+}
+
+// StripData returns a transform that additionally removes the data of secrets and config maps keepData
+// returns false for, so only their metadata is kept in the cache. Readers of such objects must not update
+// them from the cache, but patch them instead.
+func StripData(keepData func(obj metav1.Object) bool) toolscache.TransformFunc {
+	return func(in interface{}) (interface{}, error) {
+		out, err := StripUnusedFields(in)
+		if err != nil {
+			return nil, err
+		}
+
+		switch obj := out.(type) {
+		case *corev1.Secret:
+			if !keepData(obj) {
+				obj.Data = nil
+				obj.StringData = nil
+			}
+		case *corev1.ConfigMap:
+			if !keepData(obj) {
+				obj.Data = nil
+				obj.BinaryData = nil
+			}
+		}
+
+		return out, nil
+	}
+}
+
+// KeepManagedData keeps the data of the host objects vcluster syncs and of the objects in the namespace
+// vcluster runs in, such as the init manifests config map and the kube config secret
+func KeepManagedData(currentNamespace string) func(obj metav1.Object) bool {
+	return func(obj metav1.Object) bool {
+		if obj.GetNamespace() == currentNamespace {
+			return true
+		}
+
+		runtimeObj, ok := obj.(client.Object)
+		return ok && translate.Default.IsManaged(runtimeObj)
+	}
+}
+
+// SecretsAndConfigMaps returns the cache options that use the transform for secrets and config maps
+func SecretsAndConfigMaps(transform toolscache.TransformFunc) map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.Secret{}:    {Transform: transform},
+		&corev1.ConfigMap{}: {Transform: transform},
+	}
+}
@@ -0,0 +1,231 @@
+package cachetransform
+
+import (
+	"encoding/base64"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+func TestStripManagedFields(t *testing.T) {
+	pod := newFixturePod(0)
+	out, err := StripManagedFields(pod)
+	assert.NilError(t, err)
+	assert.Equal(t, len(out.(*corev1.Pod).ManagedFields), 0)
+	assert.Equal(t, out.(*corev1.Pod).Spec.Containers[0].Image, "nginx:1.25")
+
+	// tombstones are kept as they are
+	tombstone := toolscache.DeletedFinalStateUnknown{Key: "default/pod-0", Obj: newFixturePod(0)}
+	out, err = StripManagedFields(tombstone)
+	assert.NilError(t, err)
+	assert.Equal(t, len(out.(toolscache.DeletedFinalStateUnknown).Obj.(*corev1.Pod).ManagedFields), 1)
+}
+
+func TestStripUnusedFields(t *testing.T) {
+	secret := newFixtureSecret(0)
+	out, err := StripUnusedFields(secret)
+	assert.NilError(t, err)
+	assert.Equal(t, len(out.(*corev1.Secret).ManagedFields), 0)
+	_, ok := out.(*corev1.Secret).Annotations[corev1.LastAppliedConfigAnnotation]
+	assert.Assert(t, !ok)
+	assert.Equal(t, out.(*corev1.Secret).Annotations["app.kubernetes.io/version"], "1.0.0")
+	assert.Equal(t, len(out.(*corev1.Secret).Data), 2)
+}
+
+func TestStripData(t *testing.T) {
+	transform := StripData(func(obj metav1.Object) bool {
+		return obj.GetName() == "secret-0"
+	})
+
+	out, err := transform(newFixtureSecret(0))
+	assert.NilError(t, err)
+	assert.Equal(t, len(out.(*corev1.Secret).Data), 2)
+	_, ok := out.(*corev1.Secret).Annotations[corev1.LastAppliedConfigAnnotation]
+	assert.Assert(t, !ok)
+
+	out, err = transform(newFixtureSecret(1))
+	assert.NilError(t, err)
+	assert.Equal(t, len(out.(*corev1.Secret).Data), 0)
+	assert.Equal(t, out.(*corev1.Secret).Labels["app"], "nginx")
+
+	out, err = transform(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-map", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(out.(*corev1.ConfigMap).Data), 0)
+
+	// other objects keep their data
+	out, err = transform(newFixturePod(1))
+	assert.NilError(t, err)
+	assert.Equal(t, out.(*corev1.Pod).Spec.Containers[0].Image, "nginx:1.25")
+}
+
+// BenchmarkPodCache compares the memory a cache needs for 10k pods with full objects, without managed
+// fields and with metadata only objects. Run with:
+// go test ./pkg/util/cachetransform -run none -bench PodCache -benchtime 1x
+func BenchmarkPodCache(b *testing.B) {
+	const pods = 10000
+
+	b.Run("full", func(b *testing.B) {
+		benchmarkCache(b, pods, "pod", func(i int) interface{} {
+			return newFixturePod(i)
+		})
+	})
+	b.Run("strip-managed-fields", func(b *testing.B) {
+		benchmarkCache(b, pods, "pod", func(i int) interface{} {
+			out, _ := StripManagedFields(newFixturePod(i))
+			return out
+		})
+	})
+	b.Run("metadata-only", func(b *testing.B) {
+		benchmarkCache(b, pods, "pod", func(i int) interface{} {
+			out, _ := StripManagedFields(meta.AsPartialObjectMetadata(newFixturePod(i)))
+			return out
+		})
+	})
+}
+
+// BenchmarkSecretCache compares the memory a cache needs for 10k secrets applied with kubectl with full
+// objects, without managed fields and the last applied configuration and with metadata only objects. Run with:
+// go test ./pkg/util/cachetransform -run none -bench SecretCache -benchtime 1x
+func BenchmarkSecretCache(b *testing.B) {
+	const secrets = 10000
+
+	b.Run("full", func(b *testing.B) {
+		benchmarkCache(b, secrets, "secret", func(i int) interface{} {
+			return newFixtureSecret(i)
+		})
+	})
+	b.Run("strip-unused-fields", func(b *testing.B) {
+		benchmarkCache(b, secrets, "secret", func(i int) interface{} {
+			out, _ := StripUnusedFields(newFixtureSecret(i))
+			return out
+		})
+	})
+	b.Run("metadata-only", func(b *testing.B) {
+		stripData := StripData(func(obj metav1.Object) bool {
+			return false
+		})
+		benchmarkCache(b, secrets, "secret", func(i int) interface{} {
+			out, _ := stripData(newFixtureSecret(i))
+			return out
+		})
+	})
+}
+
+func benchmarkCache(b *testing.B, objects int, unit string, newObject func(i int) interface{}) {
+	for i := 0; i < b.N; i++ {
+		before := heapInUse()
+		store := toolscache.NewStore(toolscache.MetaNamespaceKeyFunc)
+		for j := 0; j < objects; j++ {
+			err := store.Add(newObject(j))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		after := heapInUse()
+
+		b.ReportMetric(float64(after-before)/float64(objects), "heap-bytes/"+unit)
+		runtime.KeepAlive(store)
+	}
+}
+
+func heapInUse() int64 {
+	runtime.GC()
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+	return int64(stats.HeapInuse)
+}
+
+// newFixturePod returns a pod that resembles a pod of a deployment as it is stored in the api server
+func newFixturePod(i int) *corev1.Pod {
+	env := []corev1.EnvVar{}
+	managedEnv := []string{}
+	for j := 0; j < 10; j++ {
+		name := fmt.Sprintf("ENV_VARIABLE_%d", j)
+		env = append(env, corev1.EnvVar{Name: name, Value: strings.Repeat("v", 32)})
+		managedEnv = append(managedEnv, fmt.Sprintf(`"k:{\"name\":\"%s\"}":{".":{},"f:name":{},"f:value":{}}`, name))
+	}
+	managedFields := `{"f:metadata":{"f:generateName":{},"f:labels":{".":{},"f:app":{},"f:pod-template-hash":{}},"f:ownerReferences":{".":{},"k:{\"uid\":\"5f2b2b9e-1f0c-4a1c-9d6f-7a1e6c9b2d11\"}":{}}},` +
+		`"f:spec":{"f:containers":{"k:{\"name\":\"nginx\"}":{".":{},"f:env":{".":{},` + strings.Join(managedEnv, ",") + `},"f:image":{},"f:imagePullPolicy":{},"f:name":{},"f:ports":{".":{},"k:{\"containerPort\":80,\"protocol\":\"TCP\"}":{".":{},"f:containerPort":{},"f:protocol":{}}},"f:resources":{},"f:terminationMessagePath":{},"f:terminationMessagePolicy":{}}},` +
+		`"f:dnsPolicy":{},"f:enableServiceLinks":{},"f:restartPolicy":{},"f:schedulerName":{},"f:securityContext":{},"f:terminationGracePeriodSeconds":{}}}`
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         fmt.Sprintf("pod-%d", i),
+			Namespace:    "default",
+			GenerateName: "nginx-7c5ddbdf54-",
+			Labels: map[string]string{
+				"app":               "nginx",
+				"pod-template-hash": "7c5ddbdf54",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "nginx-7c5ddbdf54",
+				UID:        "5f2b2b9e-1f0c-4a1c-9d6f-7a1e6c9b2d11",
+			}},
+			ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager:    "kube-controller-manager",
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				APIVersion: "v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(managedFields)},
+			}},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "nginx",
+				Image: "nginx:1.25",
+				Env:   env,
+				Ports: []corev1.ContainerPort{{ContainerPort: 80, Protocol: corev1.ProtocolTCP}},
+			}},
+			NodeName: "node-1",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "10.0.0.1",
+		},
+	}
+}
+
+// newFixtureSecret returns a secret that resembles a secret created with kubectl apply
+func newFixtureSecret(i int) *corev1.Secret {
+	data := map[string][]byte{
+		"tls.crt": []byte(strings.Repeat("c", 1024)),
+		"tls.key": []byte(strings.Repeat("k", 1024)),
+	}
+	lastApplied := fmt.Sprintf(`{"apiVersion":"v1","data":{"tls.crt":"%s","tls.key":"%s"},"kind":"Secret","metadata":{"annotations":{},"labels":{"app":"nginx"},"name":"secret-%d","namespace":"default"},"type":"kubernetes.io/tls"}`,
+		base64.StdEncoding.EncodeToString(data["tls.crt"]), base64.StdEncoding.EncodeToString(data["tls.key"]), i)
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("secret-%d", i),
+			Namespace: "default",
+			Labels: map[string]string{
+				"app": "nginx",
+			},
+			Annotations: map[string]string{
+				"app.kubernetes.io/version":        "1.0.0",
+				corev1.LastAppliedConfigAnnotation: lastApplied,
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager:    "kubectl-client-side-apply",
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				APIVersion: "v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:tls.crt":{},"f:tls.key":{}},"f:metadata":{"f:annotations":{".":{},"f:kubectl.kubernetes.io/last-applied-configuration":{}},"f:labels":{".":{},"f:app":{}}},"f:type":{}}`)},
+			}},
+		},
+		Data: data,
+		Type: corev1.SecretTypeTLS,
+	}
+}
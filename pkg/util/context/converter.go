@@ -17,5 +17,7 @@ func ToRegisterContext(ctx *context.ControllerContext) *synccontext.RegisterCont
 
 		VirtualManager:  ctx.VirtualManager,
 		PhysicalManager: ctx.LocalManager,
+
+		NamespaceFilter: ctx.NamespaceFilter,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	volumesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	volumegroupsnapshotv1alpha1 "github.com/loft-sh/vcluster/pkg/apis/volumegroupsnapshot/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -28,6 +29,11 @@ func NewScheme() *runtime.Scheme {
 	if err != nil {
 		panic(err)
 	}
+
+	err = volumegroupsnapshotv1alpha1.AddToScheme(scheme)
+	if err != nil {
+		panic(err)
+	}
 	return scheme
 }
 
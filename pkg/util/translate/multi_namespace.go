@@ -86,7 +86,7 @@ func (s *multiNamespace) IsManagedCluster(obj runtime.Object) bool {
 		return false
 	}
 
-	return metaAccessor.GetLabels()[MarkerLabel] == SafeConcatName(s.currentNamespace, "x", Suffix)
+	return metaAccessor.GetLabels()[MarkerLabel] == s.MarkerLabelCluster()
 }
 
 func (s *multiNamespace) IsTargetedNamespace(ns string) bool {
@@ -112,6 +112,10 @@ func (s *multiNamespace) PhysicalNamespace(vNamespace string) string {
 	return fmt.Sprintf("%s-%s-%s", s.getNamespacePrefix(), hex.EncodeToString(sha[0:])[0:8], s.getNamespaceSuffix())
 }
 
+func (s *multiNamespace) MarkerLabelCluster() string {
+	return SafeConcatName(s.currentNamespace, "x", Suffix)
+}
+
 func (s *multiNamespace) TranslateLabelsCluster(vObj client.Object, pObj client.Object, syncedLabels []string) map[string]string {
 	newLabels := map[string]string{}
 	if vObj != nil {
@@ -143,7 +147,7 @@ func (s *multiNamespace) TranslateLabelsCluster(vObj client.Object, pObj client.
 			newLabels[ControllerLabel] = pObjLabels[ControllerLabel]
 		}
 	}
-	newLabels[MarkerLabel] = SafeConcatName(s.currentNamespace, "x", Suffix)
+	newLabels[MarkerLabel] = s.MarkerLabelCluster()
 	return newLabels
 }
 
@@ -0,0 +1,87 @@
+package translate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NameTemplateData is the data that is available within a physical name template
+type NameTemplateData struct {
+	// Name is the name of the virtual object
+	Name string
+	// Namespace is the namespace of the virtual object
+	Namespace string
+	// Suffix is the name of the virtual cluster
+	Suffix string
+	// TargetNamespace is the host namespace the object is synced to
+	TargetNamespace string
+}
+
+// NewSingleNamespaceTranslatorWithNameTemplate creates a new single namespace translator that
+// uses the given go template (e.g. {{.Namespace}}-{{.Name}}) to build the physical names of
+// namespaced objects. If the rendered name is longer than 63 characters it will be shortened
+// with a hash and if it is not a valid name, the default naming scheme is used instead.
+func NewSingleNamespaceTranslatorWithNameTemplate(targetNamespace, nameTemplate string) (Translator, error) {
+	if nameTemplate == "" {
+		return NewSingleNamespaceTranslator(targetNamespace), nil
+	}
+
+	t, err := ParseNameTemplate(nameTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &singleNamespace{
+		targetNamespace: targetNamespace,
+		nameTemplate:    t,
+	}, nil
+}
+
+// ParseNameTemplate parses and validates the given physical name template
+func ParseNameTemplate(nameTemplate string) (*template.Template, error) {
+	t, err := template.New("name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse name template: %w", err)
+	}
+
+	// make sure the template can be rendered
+	_, err = executeNameTemplate(t, &NameTemplateData{Name: "name", Namespace: "namespace", Suffix: "suffix", TargetNamespace: "target"})
+	if err != nil {
+		return nil, fmt.Errorf("execute name template: %w", err)
+	}
+
+	return t, nil
+}
+
+// RenderNameTemplate renders the physical name for the given data and returns false
+// if the rendered name cannot be used as physical name.
+func RenderNameTemplate(t *template.Template, data *NameTemplateData) (string, bool) {
+	rendered, err := executeNameTemplate(t, data)
+	if err != nil || rendered == "" {
+		return "", false
+	}
+
+	// hash the name if it's too long
+	physicalName := SafeConcatName(rendered)
+
+	// the physical name needs to be a valid service name as well
+	if len(validation.IsDNS1035Label(physicalName)) > 0 {
+		return "", false
+	}
+
+	return physicalName, true
+}
+
+func executeNameTemplate(t *template.Template, data *NameTemplateData) (string, error) {
+	buf := &bytes.Buffer{}
+	err := t.Execute(buf, data)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
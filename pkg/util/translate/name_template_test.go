@@ -0,0 +1,42 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNameTemplate(t *testing.T) {
+	_, err := NewSingleNamespaceTranslatorWithNameTemplate("test", "{{.Namespace")
+	assert.ErrorContains(t, err, "parse name template")
+
+	_, err = NewSingleNamespaceTranslatorWithNameTemplate("test", "{{.Unknown}}")
+	assert.ErrorContains(t, err, "execute name template")
+
+	translator, err := NewSingleNamespaceTranslatorWithNameTemplate("test", "{{.Namespace}}-{{.Name}}")
+	assert.NilError(t, err)
+
+	// templated name
+	assert.Equal(t, translator.PhysicalName("nginx", "default"), "default-nginx")
+	assert.Equal(t, translator.PhysicalName("", "default"), "")
+
+	// hashed name if too long
+	longName := strings.Repeat("a", 63)
+	physicalName := translator.PhysicalName(longName, "default")
+	assert.Equal(t, len(physicalName), 63)
+	assert.Assert(t, strings.HasPrefix(physicalName, "default-aaa"))
+	assert.Assert(t, physicalName != translator.PhysicalName(longName+"b", "default"))
+
+	// fallback to default naming if rendered name is invalid
+	assert.Equal(t, translator.PhysicalName("nginx", "1-invalid"), SafeConcatName("nginx", "x", "1-invalid", "x", Suffix))
+
+	// objects need to be recognized as managed
+	pObj := &metav1.PartialObjectMetadata{}
+	pObj.SetName("default-nginx")
+	pObj.SetNamespace("test")
+	pObj.SetLabels(map[string]string{MarkerLabel: Suffix})
+	pObj.SetAnnotations(map[string]string{NameAnnotation: "nginx", NamespaceAnnotation: "default"})
+	assert.Assert(t, translator.IsManaged(pObj))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"regexp"
 	"strings"
+	"text/template"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -23,6 +24,9 @@ func NewSingleNamespaceTranslator(targetNamespace string) Translator {
 
 type singleNamespace struct {
 	targetNamespace string
+
+	// nameTemplate is an optional template to build physical names
+	nameTemplate *template.Template
 }
 
 func (s *singleNamespace) SingleNamespaceTarget() bool {
@@ -34,6 +38,17 @@ func (s *singleNamespace) PhysicalName(name, namespace string) string {
 	if name == "" {
 		return ""
 	}
+	if s.nameTemplate != nil {
+		physicalName, ok := RenderNameTemplate(s.nameTemplate, &NameTemplateData{
+			Name:            name,
+			Namespace:       namespace,
+			Suffix:          Suffix,
+			TargetNamespace: s.targetNamespace,
+		})
+		if ok {
+			return physicalName
+		}
+	}
 	return SafeConcatName(name, "x", namespace, "x", Suffix)
 }
 
@@ -85,7 +100,7 @@ func (s *singleNamespace) IsManagedCluster(obj runtime.Object) bool {
 		return false
 	}
 
-	return metaAccessor.GetLabels()[MarkerLabel] == SafeConcatName(s.targetNamespace, "x", Suffix)
+	return metaAccessor.GetLabels()[MarkerLabel] == s.MarkerLabelCluster()
 }
 
 func (s *singleNamespace) IsTargetedNamespace(ns string) bool {
@@ -101,6 +116,10 @@ func (s *singleNamespace) PhysicalNamespace(vNamespace string) string {
 	return s.targetNamespace
 }
 
+func (s *singleNamespace) MarkerLabelCluster() string {
+	return SafeConcatName(s.targetNamespace, "x", Suffix)
+}
+
 func (s *singleNamespace) TranslateLabelsCluster(vObj client.Object, pObj client.Object, syncedLabels []string) map[string]string {
 	newLabels := map[string]string{}
 	if vObj != nil {
@@ -132,7 +151,7 @@ func (s *singleNamespace) TranslateLabelsCluster(vObj client.Object, pObj client
 			newLabels[ControllerLabel] = pObjLabels[ControllerLabel]
 		}
 	}
-	newLabels[MarkerLabel] = SafeConcatName(s.targetNamespace, "x", Suffix)
+	newLabels[MarkerLabel] = s.MarkerLabelCluster()
 	return newLabels
 }
 
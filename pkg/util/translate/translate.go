@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/log"
 	"github.com/pkg/errors"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -139,7 +140,7 @@ func applyAnnotations(fromAnnotations map[string]string, toAnnotations map[strin
 		toAnnotations = map[string]string{}
 	}
 
-	excludedKeys := []string{ManagedAnnotationsAnnotation, ManagedLabelsAnnotation}
+	excludedKeys := []string{ManagedAnnotationsAnnotation, ManagedLabelsAnnotation, constants.SyncStatusAnnotation, constants.PauseSyncAnnotation}
 	excludedKeys = append(excludedKeys, excludeAnnotations...)
 	mergedAnnotations, managedKeys := applyMaps(fromAnnotations, toAnnotations, ApplyMapsOptions{
 		ManagedKeys: strings.Split(toAnnotations[ManagedAnnotationsAnnotation], "\n"),
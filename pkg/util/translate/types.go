@@ -72,6 +72,23 @@ type Translator interface {
 	ConvertLabelKey(string) string
 }
 
+// ClusterMarkerLabeler is an optional interface for translators that can
+// return the marker label value of cluster scoped objects directly
+type ClusterMarkerLabeler interface {
+	// MarkerLabelCluster returns the value of the marker label of cluster scoped objects
+	MarkerLabelCluster() string
+}
+
+// MarkerLabelCluster returns the value of the marker label the translator sets on
+// cluster scoped objects
+func MarkerLabelCluster(t Translator) string {
+	if labeler, ok := t.(ClusterMarkerLabeler); ok {
+		return labeler.MarkerLabelCluster()
+	}
+
+	return t.TranslateLabelsCluster(nil, nil, nil)[MarkerLabel]
+}
+
 // PhysicalNameTranslator transforms a virtual cluster name to a physical name
 type PhysicalNameTranslator func(vName string, vObj client.Object) string
 